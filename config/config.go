@@ -1,29 +1,354 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 type Config struct {
-	AppDir       string `yaml:"app_dir"`
-	Language     string `yaml:"language"`
-	WalletsDir   string `yaml:"wallets_dir"`
-	DatabasePath string `yaml:"database_path"`
+	AppDir         string                   `yaml:"app_dir"`
+	Language       string                   `yaml:"language"`
+	WalletsDir     string                   `yaml:"wallets_dir"`
+	DatabasePath   string                   `yaml:"database_path"`
+	Explorer       ExplorerConfig           `yaml:"explorer"`
+	ActiveNetwork  string                   `yaml:"active_network"`
+	Networks       map[string]NetworkConfig `yaml:"networks"`
+	Pricing        PricingConfig            `yaml:"pricing"`
+	SignaturesFile string                   `yaml:"signatures_file"` // Local 4-byte database used to decode calldata
+	ENSCacheTTL    time.Duration            `yaml:"ens_cache_ttl"`
+	GapLimit       int                      `yaml:"discovery_gap_limit"` // Unused addresses before HD account discovery stops
+	Dev            DevConfig                `yaml:"dev"`
+	RPC            RPCConfig                `yaml:"rpc"`
+	KeyBackends    KeyBackendsConfig        `yaml:"key_backends"`
+	Keyring        KeyringConfig            `yaml:"keyring"`
+	MasterPassword MasterPasswordConfig     `yaml:"master_password"`
+	Session        SessionConfig            `yaml:"session"`
+	UnlockThrottle UnlockThrottleConfig     `yaml:"unlock_throttle"`
+	// Password sources for operations that cannot prompt, tried per wallet first
+	// (keyed by address) and then globally, in order
+	PasswordProviders []PasswordProviderConfig            `yaml:"password_providers"`
+	WalletPasswords   map[string][]PasswordProviderConfig `yaml:"wallet_passwords"`
+}
+
+// PasswordProviderConfig describes one password source. Type is env, file, pass
+// or command; {address} in any setting is replaced with the wallet address.
+type PasswordProviderConfig struct {
+	Type     string   `yaml:"type"`
+	Variable string   `yaml:"variable"` // env: environment variable name
+	Path     string   `yaml:"path"`     // file: mode 0600 or stricter, owned by the user
+	Program  string   `yaml:"program"`  // pass: pass (default) or gopass
+	Entry    string   `yaml:"entry"`    // pass: entry in the password store
+	Command  []string `yaml:"command"`  // command: program and arguments; stdout is the password
+}
+
+// KeyringConfig controls storing wallet passwords in the desktop keyring. Each
+// wallet still opts in separately; Disabled turns the integration off entirely.
+type KeyringConfig struct {
+	Disabled bool   `yaml:"disabled"`
+	Service  string `yaml:"service"` // Name the passwords are filed under
+}
+
+// MasterPasswordConfig controls the application master password. Disabled only
+// skips setting one on first run; once set it is always required.
+type MasterPasswordConfig struct {
+	Disabled bool `yaml:"disabled"`
+	// Sources of the master password for headless commands, tried in order
+	Providers []PasswordProviderConfig `yaml:"providers"`
+}
+
+// SessionConfig controls how long unlocked wallets stay in memory and the
+// inactivity lock. A negative value disables the feature.
+type SessionConfig struct {
+	TTL      time.Duration `yaml:"ttl"`       // Unlocked wallets are reopened without the password
+	IdleLock time.Duration `yaml:"idle_lock"` // Time without key presses before the application locks
+}
+
+// UnlockThrottleConfig limits wallet password guessing: each wrong password
+// doubles the wait before the next one, and MaxAttempts consecutive failures lock
+// the wallet out until the cooldown ends or the master password is entered.
+type UnlockThrottleConfig struct {
+	Disabled    bool          `yaml:"disabled"`
+	MaxAttempts int           `yaml:"max_attempts"` // A negative value keeps only the backoff
+	BaseDelay   time.Duration `yaml:"base_delay"`   // Wait after the first failure
+	MaxDelay    time.Duration `yaml:"max_delay"`
+	Cooldown    time.Duration `yaml:"cooldown"`
+}
+
+// KeyBackendsConfig lists the backends that can hold wallet keys. Default names
+// the backend used for new wallets; the local keystore is always available.
+type KeyBackendsConfig struct {
+	Default string       `yaml:"default"`
+	Vault   VaultConfig  `yaml:"vault"`
+	KMS     KMSConfig    `yaml:"kms"`
+	PKCS11  PKCS11Config `yaml:"pkcs11"`
+}
+
+// PKCS11Config selects a token through a PKCS#11 module such as SoftHSMv2 or a
+// vendor HSM library. Slot takes precedence over TokenLabel.
+type PKCS11Config struct {
+	Library    string `yaml:"library"` // Path of the module; empty disables the backend
+	Slot       *uint  `yaml:"slot"`
+	TokenLabel string `yaml:"token_label"`
+	PIN        string `yaml:"pin"`
+	DeleteKeys bool   `yaml:"delete_keys"` // Destroy keys on the token when their wallet is deleted
+}
+
+// KMSConfig connects to AWS KMS. Credentials come from the standard AWS chain;
+// Endpoint points the client at another endpoint such as a local KMS emulator.
+type KMSConfig struct {
+	Region         string `yaml:"region"` // Empty disables the backend
+	Endpoint       string `yaml:"endpoint"`
+	Profile        string `yaml:"profile"`
+	DeleteKeys     bool   `yaml:"delete_keys"`     // Schedule keys for deletion when their wallet is deleted
+	DeletionWindow int32  `yaml:"deletion_window"` // Days KMS waits before deleting; 0 uses the KMS default
+}
+
+// VaultConfig connects to a HashiCorp Vault Transit engine. Authentication uses
+// Token when set, otherwise AppRole with RoleID and SecretID.
+type VaultConfig struct {
+	Address      string `yaml:"address"` // Empty disables the backend
+	Namespace    string `yaml:"namespace"`
+	Mount        string `yaml:"mount"`
+	Token        string `yaml:"token"`
+	RoleID       string `yaml:"role_id"`
+	SecretID     string `yaml:"secret_id"`
+	AppRoleMount string `yaml:"approle_mount"`
+	KeyType      string `yaml:"key_type"`    // Transit key type of secp256k1 keys
+	DeleteKeys   bool   `yaml:"delete_keys"` // Destroy keys in Vault when their wallet is deleted
+}
+
+// RPCConfig tunes the failover client used when a network lists several endpoints
+type RPCConfig struct {
+	HealthInterval time.Duration `yaml:"health_interval"`
+	Timeout        time.Duration `yaml:"timeout"`      // Limit for one attempt before failing over
+	MaxHeadLag     uint64        `yaml:"max_head_lag"` // Blocks an endpoint may trail the others
+	RateLimit      float64       `yaml:"rate_limit"`   // Requests per second per endpoint, 0 disables
+	CacheDepth     uint64        `yaml:"cache_depth"`  // Confirmations before block-bound results are cached
+}
+
+// DevConfig controls the local chain started with the `dev` command
+type DevConfig struct {
+	RPCHost       string `yaml:"rpc_host"`
+	RPCPort       int    `yaml:"rpc_port"`
+	Prefund       string `yaml:"prefund"` // Native balance given to every wallet, in ETH
+	BlockGasLimit uint64 `yaml:"block_gas_limit"`
+}
+
+// NetworkConfig describes an EVM network reachable through JSON-RPC
+type NetworkConfig struct {
+	Name           string   `yaml:"name"`
+	ChainID        int64    `yaml:"chain_id"`
+	RPCURL         string   `yaml:"rpc_url"`
+	RPCURLs        []string `yaml:"rpc_urls"` // Fallback endpoints tried after rpc_url
+	Symbol         string   `yaml:"symbol"`
+	NFTCollections []string `yaml:"nft_collections"`
+	LogsFromBlock  uint64   `yaml:"logs_from_block"`
+	LogsBlockRange uint64   `yaml:"logs_block_range"`
+	ENSRegistry    string   `yaml:"ens_registry"` // Empty disables name resolution
+	Disperse       string   `yaml:"disperse_contract"`
+
+	// Balances proven with eth_getProof against a trusted state root
+	Verification VerificationConfig `yaml:"verification"`
+
+	// Portfolio valuation
+	Tokens        []TokenConfig     `yaml:"tokens"`
+	PriceID       string            `yaml:"price_id"`       // CoinGecko id of the native coin
+	PricePlatform string            `yaml:"price_platform"` // CoinGecko asset platform for token prices
+	PriceFeeds    map[string]string `yaml:"price_feeds"`    // Chainlink aggregator per symbol
+}
+
+type TokenConfig struct {
+	Symbol      string  `yaml:"symbol"`
+	Address     string  `yaml:"address"`
+	Decimals    int     `yaml:"decimals"`
+	BalanceSlot *uint64 `yaml:"balance_slot,omitempty"` // Storage slot of the balances mapping, needed to verify the balance
+}
+
+// VerificationConfig selects the trusted checkpoint used in verified mode: a block
+// pinned by number and hash, or the header of a node the user trusts at TrustedBlock
+type VerificationConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	CheckpointBlock uint64 `yaml:"checkpoint_block"`
+	CheckpointHash  string `yaml:"checkpoint_hash"`
+	TrustedRPC      string `yaml:"trusted_rpc"`
+	TrustedBlock    string `yaml:"trusted_block"` // Block tag requested from trusted_rpc, "finalized" by default
+}
+
+// PricingConfig selects the price feed used for portfolio valuation:
+// "coingecko" (or any compatible HTTP API), "static" (a local prices file) or "chainlink"
+type PricingConfig struct {
+	Provider        string        `yaml:"provider"`
+	Currency        string        `yaml:"currency"`
+	BaseURL         string        `yaml:"base_url"`
+	APIKey          string        `yaml:"api_key"`
+	APIKeyHeader    string        `yaml:"api_key_header"`
+	StaticFile      string        `yaml:"static_file"`
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+// ExplorerConfig points to the Etherscan-compatible API used for transaction
+// history. Its chain is the active network's.
+type ExplorerConfig struct {
+	BaseURL  string        `yaml:"base_url"`
+	APIKey   string        `yaml:"api_key"`
+	PageSize int           `yaml:"page_size"`
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+const (
+	DefaultExplorerURL      = "https://api.etherscan.io/v2/api"
+	DefaultExplorerPageSize = 25
+	DefaultExplorerCacheTTL = 5 * time.Minute
+	DefaultNetwork          = "mainnet"
+	DefaultPriceProvider    = "coingecko"
+	DefaultPriceURL         = "https://api.coingecko.com/api/v3"
+	DefaultPriceKeyHeader   = "x-cg-demo-api-key"
+	DefaultCurrency         = "usd"
+	DefaultPriceRefresh     = 5 * time.Minute
+	DefaultENSRegistry      = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+	DefaultENSCacheTTL      = 10 * time.Minute
+	DefaultGapLimit         = 20
+	DevNetwork              = "dev"
+	DefaultDevRPCHost       = "127.0.0.1"
+	DefaultDevRPCPort       = 8545
+	DefaultDevPrefund       = "1000"
+	DefaultRPCHealth        = 30 * time.Second
+	DefaultRPCTimeout       = 15 * time.Second
+	DefaultRPCMaxHeadLag    = 5
+	DefaultRPCCacheDepth    = 64
+	DefaultTrustedBlock     = "finalized"
+	DefaultKeyBackend       = "keystore"
+	DefaultVaultMount       = "transit"
+	DefaultVaultAppRole     = "approle"
+	DefaultVaultKeyType     = "ecdsa-secp256k1"
+	DefaultKeyringService   = "blocowallet"
+	DefaultSessionTTL       = 5 * time.Minute
+	DefaultSessionIdleLock  = 10 * time.Minute
+	DefaultUnlockAttempts   = 5
+	DefaultUnlockBaseDelay  = time.Second
+	DefaultUnlockMaxDelay   = time.Minute
+	DefaultUnlockCooldown   = 30 * time.Minute
+)
+
+func defaultPricing(appDir string) PricingConfig {
+	return PricingConfig{
+		Provider:        DefaultPriceProvider,
+		Currency:        DefaultCurrency,
+		BaseURL:         DefaultPriceURL,
+		APIKeyHeader:    DefaultPriceKeyHeader,
+		StaticFile:      filepath.Join(appDir, "prices.yaml"),
+		RefreshInterval: DefaultPriceRefresh,
+	}
+}
+
+func defaultDev() DevConfig {
+	return DevConfig{
+		RPCHost: DefaultDevRPCHost,
+		RPCPort: DefaultDevRPCPort,
+		Prefund: DefaultDevPrefund,
+	}
+}
+
+func defaultRPC() RPCConfig {
+	return RPCConfig{
+		HealthInterval: DefaultRPCHealth,
+		Timeout:        DefaultRPCTimeout,
+		MaxHeadLag:     DefaultRPCMaxHeadLag,
+		CacheDepth:     DefaultRPCCacheDepth,
+	}
+}
+
+func defaultNetworks() map[string]NetworkConfig {
+	return map[string]NetworkConfig{
+		DefaultNetwork: {
+			Name:          "Ethereum Mainnet",
+			ChainID:       1,
+			RPCURL:        "https://ethereum-rpc.publicnode.com",
+			RPCURLs:       []string{"https://eth.llamarpc.com"},
+			Symbol:        "ETH",
+			PriceID:       "ethereum",
+			PricePlatform: "ethereum",
+			ENSRegistry:   DefaultENSRegistry,
+			Disperse:      "0xD152f549545093347A162Dce210e7293f1452150",
+			PriceFeeds: map[string]string{
+				"ETH": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419",
+			},
+		},
+	}
+}
+
+// Network returns the configuration of the active network
+func (c *Config) Network() (NetworkConfig, error) {
+	network, ok := c.Networks[c.ActiveNetwork]
+	if !ok {
+		return NetworkConfig{}, fmt.Errorf("network %q is not configured", c.ActiveNetwork)
+	}
+	if network.Symbol == "" {
+		network.Symbol = "ETH"
+	}
+	if network.Verification.TrustedBlock == "" {
+		network.Verification.TrustedBlock = DefaultTrustedBlock
+	}
+	return network, nil
+}
+
+// Endpoints returns rpc_url followed by the fallback endpoints, without duplicates
+func (n NetworkConfig) Endpoints() []string {
+	var endpoints []string
+	seen := map[string]bool{}
+	for _, endpoint := range append([]string{n.RPCURL}, n.RPCURLs...) {
+		if endpoint == "" || seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// RegisterDevNetwork adds the local dev chain and makes it the active network.
+// The change only lives in memory, so the next regular run keeps the saved network.
+func (c *Config) RegisterDevNetwork(rpcURL string, chainID int64) {
+	if c.Networks == nil {
+		c.Networks = map[string]NetworkConfig{}
+	}
+	c.Networks[DevNetwork] = NetworkConfig{
+		Name:    "BLOCO Devnet",
+		ChainID: chainID,
+		RPCURL:  rpcURL,
+		Symbol:  "ETH",
+	}
+	c.ActiveNetwork = DevNetwork
 }
 
 func LoadConfig(appDir string) (*Config, error) {
 	configPath := filepath.Join(appDir, "config.yaml")
 
-	// If a config file doesn't exist, create it with default values
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	// If a config file doesn't exist, create it with default values. It holds
+	// backend credentials and API keys, so only its owner may read it.
+	if info, err := os.Stat(configPath); os.IsNotExist(err) {
 		defaultConfig := &Config{
 			AppDir:       appDir,
 			Language:     "en",
 			WalletsDir:   filepath.Join(appDir, "keystore"),
 			DatabasePath: filepath.Join(appDir, "wallets.db"),
+			Explorer: ExplorerConfig{
+				BaseURL:  DefaultExplorerURL,
+				PageSize: DefaultExplorerPageSize,
+				CacheTTL: DefaultExplorerCacheTTL,
+			},
+			ActiveNetwork:  DefaultNetwork,
+			Networks:       defaultNetworks(),
+			Pricing:        defaultPricing(appDir),
+			SignaturesFile: filepath.Join(appDir, "signatures.txt"),
+			ENSCacheTTL:    DefaultENSCacheTTL,
+			GapLimit:       DefaultGapLimit,
+			Dev:            defaultDev(),
+			RPC:            defaultRPC(),
 		}
 
 		configData, err := yaml.Marshal(defaultConfig)
@@ -31,10 +356,15 @@ func LoadConfig(appDir string) (*Config, error) {
 			return nil, err
 		}
 
-		err = os.WriteFile(configPath, configData, 0644)
+		err = os.WriteFile(configPath, configData, 0600)
 		if err != nil {
 			return nil, err
 		}
+	} else if err == nil && info.Mode().Perm()&0077 != 0 {
+		// Files written by older versions were readable by every user
+		if err := os.Chmod(configPath, 0600); err != nil {
+			return nil, err
+		}
 	}
 
 	// Load the configuration file
@@ -74,6 +404,102 @@ func LoadConfig(appDir string) (*Config, error) {
 		cfg.DatabasePath = filepath.Join(appDir, "wallets.db")
 	}
 
+	// Older config files have no explorer section
+	if cfg.Explorer.BaseURL == "" {
+		cfg.Explorer.BaseURL = DefaultExplorerURL
+	}
+	if cfg.Explorer.PageSize <= 0 {
+		cfg.Explorer.PageSize = DefaultExplorerPageSize
+	}
+	if cfg.Explorer.CacheTTL <= 0 {
+		cfg.Explorer.CacheTTL = DefaultExplorerCacheTTL
+	}
+
+	if len(cfg.Networks) == 0 {
+		cfg.Networks = defaultNetworks()
+	}
+	if cfg.ActiveNetwork == "" {
+		cfg.ActiveNetwork = DefaultNetwork
+	}
+
+	if cfg.Pricing.Provider == "" {
+		cfg.Pricing = defaultPricing(appDir)
+	}
+	if cfg.Pricing.Currency == "" {
+		cfg.Pricing.Currency = DefaultCurrency
+	}
+	if cfg.Pricing.RefreshInterval <= 0 {
+		cfg.Pricing.RefreshInterval = DefaultPriceRefresh
+	}
+	cfg.Pricing.StaticFile = expandPath(cfg.Pricing.StaticFile, homeDir)
+
+	if cfg.SignaturesFile == "" {
+		cfg.SignaturesFile = filepath.Join(appDir, "signatures.txt")
+	}
+	cfg.SignaturesFile = expandPath(cfg.SignaturesFile, homeDir)
+
+	if cfg.ENSCacheTTL <= 0 {
+		cfg.ENSCacheTTL = DefaultENSCacheTTL
+	}
+	if cfg.GapLimit <= 0 {
+		cfg.GapLimit = DefaultGapLimit
+	}
+
+	if cfg.Dev.RPCHost == "" {
+		cfg.Dev.RPCHost = DefaultDevRPCHost
+	}
+	if cfg.Dev.RPCPort <= 0 {
+		cfg.Dev.RPCPort = DefaultDevRPCPort
+	}
+	if cfg.Dev.Prefund == "" {
+		cfg.Dev.Prefund = DefaultDevPrefund
+	}
+
+	if cfg.KeyBackends.Default == "" {
+		cfg.KeyBackends.Default = DefaultKeyBackend
+	}
+	if cfg.KeyBackends.Vault.Mount == "" {
+		cfg.KeyBackends.Vault.Mount = DefaultVaultMount
+	}
+	if cfg.KeyBackends.Vault.AppRoleMount == "" {
+		cfg.KeyBackends.Vault.AppRoleMount = DefaultVaultAppRole
+	}
+	if cfg.KeyBackends.Vault.KeyType == "" {
+		cfg.KeyBackends.Vault.KeyType = DefaultVaultKeyType
+	}
+	if cfg.Keyring.Service == "" {
+		cfg.Keyring.Service = DefaultKeyringService
+	}
+	if cfg.Session.TTL == 0 {
+		cfg.Session.TTL = DefaultSessionTTL
+	}
+	if cfg.Session.IdleLock == 0 {
+		cfg.Session.IdleLock = DefaultSessionIdleLock
+	}
+	if cfg.UnlockThrottle.MaxAttempts == 0 {
+		cfg.UnlockThrottle.MaxAttempts = DefaultUnlockAttempts
+	}
+	if cfg.UnlockThrottle.BaseDelay <= 0 {
+		cfg.UnlockThrottle.BaseDelay = DefaultUnlockBaseDelay
+	}
+	if cfg.UnlockThrottle.MaxDelay <= 0 {
+		cfg.UnlockThrottle.MaxDelay = DefaultUnlockMaxDelay
+	}
+	if cfg.UnlockThrottle.Cooldown <= 0 {
+		cfg.UnlockThrottle.Cooldown = DefaultUnlockCooldown
+	}
+
+	// Older config files have no rpc section; a zero max_head_lag or cache_depth is kept
+	if cfg.RPC == (RPCConfig{}) {
+		cfg.RPC = defaultRPC()
+	}
+	if cfg.RPC.HealthInterval <= 0 {
+		cfg.RPC.HealthInterval = DefaultRPCHealth
+	}
+	if cfg.RPC.Timeout <= 0 {
+		cfg.RPC.Timeout = DefaultRPCTimeout
+	}
+
 	return cfg, nil
 }
 
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigKeepsTheFilePrivate(t *testing.T) {
+	appDir := t.TempDir()
+	configPath := filepath.Join(appDir, "config.yaml")
+
+	mode := func() os.FileMode {
+		t.Helper()
+		info, err := os.Stat(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Mode().Perm()
+	}
+
+	if _, err := LoadConfig(appDir); err != nil {
+		t.Fatal(err)
+	}
+	if got := mode(); got != 0600 {
+		t.Errorf("new config file mode = %o, want 600", got)
+	}
+
+	// A file left readable by an older version is tightened on load
+	if err := os.Chmod(configPath, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(appDir); err != nil {
+		t.Fatal(err)
+	}
+	if got := mode(); got != 0600 {
+		t.Errorf("existing config file mode = %o, want 600", got)
+	}
+}
@@ -16,10 +16,23 @@ const (
 	ListWalletsView           = "list_wallets"
 	WalletPasswordView        = "wallet_password"
 	WalletDetailsView         = "wallet_details"
+	HistoryView               = "wallet_history"
+	LedgerView                = "ledger"
+	NFTView                   = "wallet_nfts"
+	TxReviewView              = "tx_review"
+	ContractsView             = "contracts"
+	ApprovalsView             = "wallet_approvals"
+	DiscoveryView             = "account_discovery"
+	SweepView                 = "wallet_sweep"
+	PaymentsView              = "batch_payments"
+	VaultView                 = "vault"
+	TwoFactorView             = "two_factor"
 	StyleWidth                = 40
 	StyleMargin               = 1
 	ConfigFontsPath           = "config/fonts.json"
 	SplashDuration            = 2 * time.Second
 	ErrorFontNotFoundMessage  = "Fonte não encontrada nos diretórios especificados."
 	MnemonicWordCount         = 12
+	DefaultNativeSymbol       = "ETH"
+	NativeDecimals            = 18
 )
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// ChainClient is the subset of the Ethereum JSON-RPC API used by the services.
+// It is satisfied by *ethclient.Client and by go-ethereum's simulated backend.
+type ChainClient interface {
+	ethereum.BlockNumberReader
+	ethereum.ChainReader
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+	ethereum.GasPricer
+	ethereum.GasPricer1559
+	ethereum.FeeHistoryReader
+	ethereum.LogFilterer
+	ethereum.PendingStateReader
+	ethereum.PendingContractCaller
+	ethereum.TransactionReader
+	ethereum.TransactionSender
+	ethereum.ChainIDReader
+}
+
+// RPCCaller performs raw JSON-RPC calls for methods without a typed client wrapper,
+// such as eth_simulateV1. It is satisfied by *rpc.Client.
+type RPCCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// BlockProducer mines blocks on demand on a development chain and returns the new head number
+type BlockProducer interface {
+	Mine(ctx context.Context) (uint64, error)
+}
+
+// EndpointHealth is the result of the last health check of an RPC endpoint
+type EndpointHealth struct {
+	URL       string
+	Healthy   bool
+	Latency   time.Duration
+	Head      uint64 // Latest block reported by the endpoint
+	Error     string // Reason the endpoint is unhealthy
+	CheckedAt time.Time
+}
+
+// EndpointMonitor reports the RPC endpoint currently serving requests
+type EndpointMonitor interface {
+	ActiveEndpoint() EndpointHealth
+}
+
+// RPCCache stores JSON-RPC results that can no longer change, such as receipts
+// and blocks buried under enough confirmations. The key identifies the request.
+type RPCCache interface {
+	GetRPCResult(chainID int64, key string) ([]byte, bool, error)
+	SaveRPCResult(chainID int64, key string, result []byte) error
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// CheckpointProvider returns a block header whose state root is trusted independently
+// of the RPC endpoint, such as a pinned block hash or the finalized head of the
+// user's own node. Balances proven against that root cannot be forged by the endpoint.
+type CheckpointProvider interface {
+	TrustedHeader(ctx context.Context) (*types.Header, error)
+}
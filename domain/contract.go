@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// SavedContract is a contract address with its ABI, remembered per network
+type SavedContract struct {
+	ID        int
+	ChainID   int64
+	Name      string
+	Address   string
+	ABI       string // ABI JSON as loaded from the user's file
+	CreatedAt time.Time
+}
+
+// ContractRepository persists the contracts used in the workbench.
+// SaveContract replaces an existing entry for the same chain and address.
+type ContractRepository interface {
+	SaveContract(contract *SavedContract) error
+	GetContracts(chainID int64) ([]SavedContract, error)
+	DeleteContract(id int) error
+}
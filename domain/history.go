@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// HistoryKind identifies the category of transfers returned by a HistoryProvider
+type HistoryKind string
+
+const (
+	HistoryNormal   HistoryKind = "normal"
+	HistoryInternal HistoryKind = "internal"
+	HistoryToken    HistoryKind = "token"
+)
+
+// HistoryKinds lists the kinds in the order they are presented to the user
+var HistoryKinds = []HistoryKind{HistoryNormal, HistoryInternal, HistoryToken}
+
+type HistoryEntry struct {
+	Kind            HistoryKind
+	Hash            string
+	BlockNumber     uint64
+	Timestamp       time.Time
+	From            string
+	To              string
+	Value           string // Amount in base units (wei or token units), decimal encoded
+	Fee             string // Fee paid in wei, empty when the provider does not report it
+	ContractAddress string
+	TokenSymbol     string
+	TokenDecimals   int
+	Failed          bool
+}
+
+// HistoryProvider fetches the transfer history of an address from an external indexer
+type HistoryProvider interface {
+	FetchHistory(ctx context.Context, address string, kind HistoryKind, page, pageSize int) ([]HistoryEntry, error)
+}
+
+// HistoryRepository caches history pages locally so they survive restarts and outages.
+// GetHistoryPage returns a zero time when the page has never been cached.
+type HistoryRepository interface {
+	GetHistoryPage(chainID int64, address string, kind HistoryKind, page, pageSize int) ([]HistoryEntry, time.Time, error)
+	SaveHistoryPage(chainID int64, address string, kind HistoryKind, page, pageSize int, entries []HistoryEntry) error
+}
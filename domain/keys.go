@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+)
+
+// DefaultKeyBackend names the local encrypted keystore, which also holds every
+// wallet created before backends were recorded
+const DefaultKeyBackend = "keystore"
+
+// KeyBackend stores secp256k1 private keys and signs with them. Keys are addressed
+// by a backend-specific reference, such as a keystore file path or a remote key id.
+// The password is only meaningful to backends that encrypt keys locally.
+type KeyBackend interface {
+	Name() string
+	// CreateKey generates a new key inside the backend and returns its reference
+	CreateKey(ctx context.Context, password string) (string, error)
+	// ImportKey stores an existing private key and returns its reference
+	ImportKey(ctx context.Context, key *ecdsa.PrivateKey, password string) (string, error)
+	// SignDigest signs a 32-byte digest and returns a 65-byte [R || S || V]
+	// signature with V in {0, 1}, as produced by crypto.Sign
+	SignDigest(ctx context.Context, ref, password string, digest []byte) ([]byte, error)
+	// PublicKey exports the public key of the key at ref
+	PublicKey(ctx context.Context, ref, password string) (*ecdsa.PublicKey, error)
+	// DeleteKey removes the key; deleting a missing key is not an error
+	DeleteKey(ctx context.Context, ref string) error
+}
+
+// KeyExporter is implemented by backends that can hand the private key back,
+// such as the local keystore. Wallets in other backends never expose their key.
+type KeyExporter interface {
+	ExportKey(ctx context.Context, ref, password string) (*ecdsa.PrivateKey, error)
+}
+
+// KeyDiscarder is implemented by backends that may keep the keys of deleted
+// wallets. DiscardKey removes a key whatever that setting, for keys that never
+// belonged to a registered wallet.
+type KeyDiscarder interface {
+	DiscardKey(ctx context.Context, ref string) error
+}
+
+// ErrIncorrectPassword is returned when a password does not decrypt a locally
+// encrypted key
+var ErrIncorrectPassword = errors.New("incorrect password")
+
+// ErrSecretNotFound is returned when the keyring holds no password for a wallet
+var ErrSecretNotFound = errors.New("password not found in the keyring")
+
+// SecretStore keeps wallet passwords in the desktop keyring, keyed by address.
+// Available reports whether a keyring daemon answered, so callers can fall back
+// to prompting when it did not.
+type SecretStore interface {
+	Available() bool
+	GetSecret(address string) (string, error)
+	SetSecret(address, secret string) error
+	DeleteSecret(address string) error
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+type LedgerStatus string
+
+const (
+	LedgerSigned    LedgerStatus = "signed"
+	LedgerBroadcast LedgerStatus = "broadcast"
+	LedgerConfirmed LedgerStatus = "confirmed"
+	LedgerFailed    LedgerStatus = "failed"
+)
+
+// LedgerStatuses lists every status in lifecycle order
+var LedgerStatuses = []LedgerStatus{LedgerSigned, LedgerBroadcast, LedgerConfirmed, LedgerFailed}
+
+// LedgerEntry is the local record of a transaction signed or broadcast by the application
+type LedgerEntry struct {
+	ID        int
+	Hash      string
+	ChainID   int64
+	From      string
+	To        string
+	Value     string // Native amount in wei, decimal encoded
+	Fee       string // Maximum fee when signed, actual fee once confirmed
+	Timestamp time.Time
+	Memo      string
+	Status    LedgerStatus
+}
+
+// LedgerFilter restricts ledger queries; zero values match everything
+type LedgerFilter struct {
+	Address string
+	Since   time.Time
+	Until   time.Time
+	Status  LedgerStatus
+}
+
+type LedgerRepository interface {
+	AddLedgerEntry(entry *LedgerEntry) error
+	UpdateLedgerStatus(hash string, status LedgerStatus, fee string) error
+	GetLedgerEntries(filter LedgerFilter) ([]LedgerEntry, error)
+}
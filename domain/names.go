@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// NameResolver maps human-readable names (ENS) to addresses and back.
+// LookupAddress returns an empty name when the address has no verified primary name.
+type NameResolver interface {
+	Resolve(ctx context.Context, name string) (string, error)
+	LookupAddress(ctx context.Context, address string) (string, error)
+}
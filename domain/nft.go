@@ -0,0 +1,18 @@
+package domain
+
+type NFTStandard string
+
+const (
+	ERC721  NFTStandard = "ERC-721"
+	ERC1155 NFTStandard = "ERC-1155"
+)
+
+// NFT is a token currently held by a wallet
+type NFT struct {
+	Contract    string
+	Standard    NFTStandard
+	TokenID     string // Decimal encoded
+	Balance     string // Always 1 for ERC-721
+	Collection  string // Contract name when exposed by the collection
+	MetadataURI string
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Asset is a native coin or ERC-20 token whose balance is valued in fiat
+type Asset struct {
+	Symbol   string
+	Contract string // Empty for the native coin
+	Decimals int
+}
+
+// ID identifies the asset in price maps: "native" or the lowercase token contract
+func (a Asset) ID() string {
+	if a.Contract == "" {
+		return "native"
+	}
+	return strings.ToLower(a.Contract)
+}
+
+type Price struct {
+	Value     float64
+	UpdatedAt time.Time
+}
+
+// PriceProvider quotes assets in a fiat currency. Assets without a known price
+// are omitted from the result instead of failing the whole request.
+type PriceProvider interface {
+	GetPrices(ctx context.Context, assets []Asset, currency string) (map[string]Price, error)
+}
@@ -4,5 +4,6 @@ type WalletRepository interface {
 	AddWallet(wallet *Wallet) error
 	GetAllWallets() ([]Wallet, error)
 	DeleteWallet(walletID int) error
+	SetWalletKeyring(walletID int, enabled bool) error
 	Close() error
 }
@@ -0,0 +1,8 @@
+package domain
+
+// SelectorRepository is the local 4-byte database mapping function selectors
+// (lowercase 0x-prefixed hex) to the text signatures known to produce them
+type SelectorRepository interface {
+	GetSignatures(selector string) ([]string, error)
+	AddSignatures(signatures map[string][]string) error
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "math/big"
+
+// TwoFactorOperation is a sensitive operation that can require a TOTP code
+type TwoFactorOperation string
+
+const (
+	TwoFactorReveal TwoFactorOperation = "reveal" // Showing the private key and mnemonic
+	TwoFactorExport TwoFactorOperation = "export" // Exporting the wallet's ledger
+	TwoFactorDelete TwoFactorOperation = "delete"
+	TwoFactorSign   TwoFactorOperation = "sign" // Signing above the threshold
+)
+
+// TwoFactorPolicy selects the operations of a wallet that need a code besides
+// the password
+type TwoFactorPolicy struct {
+	Reveal        bool
+	Export        bool
+	Delete        bool
+	SignThreshold *big.Int // Native amount signed in one operation above which a code is needed; nil never asks
+}
+
+// Requires reports whether op needs a code; value is the native amount signed,
+// or nil when the operation moves assets the threshold cannot price (token
+// transfers, approvals, contract calls), which always counts as above it
+func (p TwoFactorPolicy) Requires(op TwoFactorOperation, value *big.Int) bool {
+	switch op {
+	case TwoFactorReveal:
+		return p.Reveal
+	case TwoFactorExport:
+		return p.Export
+	case TwoFactorDelete:
+		return p.Delete
+	case TwoFactorSign:
+		return p.SignThreshold != nil && (value == nil || value.Cmp(p.SignThreshold) > 0)
+	}
+	return false
+}
+
+// TwoFactor is the RFC 6238 TOTP enrollment of a wallet
+type TwoFactor struct {
+	WalletID      int
+	Secret        string   // Base32 shared secret, encrypted with the vault data key at rest
+	RecoveryCodes []string // SHA-256 hashes of the unused recovery codes
+	Policy        TwoFactorPolicy
+	LastStep      int64 // Time step of the last accepted code, so a code is never accepted twice
+}
+
+type TwoFactorRepository interface {
+	// GetTwoFactor returns nil when the wallet is not enrolled
+	GetTwoFactor(walletID int) (*TwoFactor, error)
+	// SaveTwoFactor stores the enrollment; the secret is only written encrypted,
+	// so it fails while the vault is locked
+	SaveTwoFactor(tf *TwoFactor) error
+	DeleteTwoFactor(walletID int) error
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Secrets whose wrong guesses are counted, each with its own attempts
+const (
+	UnlockWalletPassword = "wallet_password"
+	UnlockTwoFactor      = "two_factor"      // TOTP and recovery codes
+	UnlockMasterPassword = "master_password" // Not tied to a wallet, counted with wallet ID 0
+)
+
+// UnlockAttempts counts the consecutive wrong guesses of a secret of a wallet
+type UnlockAttempts struct {
+	WalletID  int
+	Secret    string    // One of the Unlock* secrets
+	Failures  int       // Since the last successful unlock or the end of a lockout
+	RetryAt   time.Time // No password is tried before this moment
+	LockedOut bool      // RetryAt is the end of a lockout rather than a backoff
+}
+
+// Audit events for wallet unlocks
+const (
+	AuditUnlockFailed   = "unlock_failed"   // Wrong password
+	AuditUnlockRejected = "unlock_rejected" // Attempt refused during a backoff or lockout
+	AuditLockout        = "unlock_lockout"  // Too many failures, the wallet is locked out
+	AuditLockoutRelease = "lockout_release" // Lockout ended with the master password
+	AuditReleaseFailed  = "lockout_release_failed"
+)
+
+// AuditEntry records a security event; the log is append-only
+type AuditEntry struct {
+	ID       int
+	Time     time.Time
+	WalletID int
+	Address  string
+	Event    string
+	Detail   string
+}
+
+type UnlockRepository interface {
+	// GetUnlockAttempts returns a zero record when the secret has no failures
+	GetUnlockAttempts(walletID int, secret string) (*UnlockAttempts, error)
+	SaveUnlockAttempts(attempts *UnlockAttempts) error
+	ResetUnlockAttempts(walletID int, secret string) error
+	AddAuditEntry(entry *AuditEntry) error
+}
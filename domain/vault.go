@@ -0,0 +1,37 @@
+package domain
+
+import "errors"
+
+// ErrVaultLocked is returned when encrypted metadata is written while the
+// master password is set but not entered
+var ErrVaultLocked = errors.New("the vault is locked")
+
+// VaultRecord holds the application master password material. Metadata is
+// encrypted with a random data key, stored twice: sealed with the key derived
+// from the master password and sealed with the recovery key.
+type VaultRecord struct {
+	KDF         string // Key derivation function, "argon2id"
+	Salt        []byte
+	Time        uint32 // argon2id passes
+	Memory      uint32 // argon2id memory in KiB
+	Threads     uint8
+	PasswordKey []byte // Data key sealed with the password-derived key
+	RecoveryKey []byte // Data key sealed with the recovery key
+}
+
+// FieldCipher encrypts metadata columns at rest
+type FieldCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+type VaultRepository interface {
+	// GetVault returns nil when no master password has been set
+	GetVault() (*VaultRecord, error)
+	SaveVault(record *VaultRecord) error
+	// SetFieldCipher enables encryption of the metadata fields; nil locks them,
+	// and encrypted values then read as empty
+	SetFieldCipher(cipher FieldCipher)
+	// EncryptMetadata encrypts the metadata fields still stored in plaintext
+	EncryptMetadata() error
+}
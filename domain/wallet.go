@@ -1,8 +1,10 @@
 package domain
 
 type Wallet struct {
-	ID           int
-	Address      string
-	KeyStorePath string
-	Mnemonic     string
+	ID       int
+	Address  string
+	Backend  string // Name of the KeyBackend holding the key
+	KeyRef   string // Keystore file path or the key id in a remote backend
+	Mnemonic string
+	Keyring  bool // The password is kept in the desktop keyring
 }
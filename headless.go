@@ -0,0 +1,189 @@
+package main
+
+import (
+	"blocowallet/config"
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newPasswordChain monta os provedores de senha globais e por wallet da configuração
+func newPasswordChain(cfg *config.Config) (*usecases.PasswordChain, error) {
+	global, err := newPasswordProviders(cfg.PasswordProviders)
+	if err != nil {
+		return nil, err
+	}
+	perWallet := make(map[string][]usecases.PasswordProvider)
+	for address, providerConfigs := range cfg.WalletPasswords {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("endereço inválido em wallet_passwords: %s", address)
+		}
+		if perWallet[address], err = newPasswordProviders(providerConfigs); err != nil {
+			return nil, err
+		}
+	}
+	return usecases.NewPasswordChain(global, perWallet), nil
+}
+
+func newPasswordProviders(providerConfigs []config.PasswordProviderConfig) ([]usecases.PasswordProvider, error) {
+	var providers []usecases.PasswordProvider
+	for _, provider := range providerConfigs {
+		switch provider.Type {
+		case "env":
+			if provider.Variable == "" {
+				return nil, fmt.Errorf("provedor de senha env sem variable")
+			}
+			providers = append(providers, &usecases.EnvPasswordProvider{Variable: provider.Variable})
+		case "file":
+			if provider.Path == "" {
+				return nil, fmt.Errorf("provedor de senha file sem path")
+			}
+			providers = append(providers, &usecases.FilePasswordProvider{Path: provider.Path})
+		case "pass":
+			if provider.Entry == "" {
+				return nil, fmt.Errorf("provedor de senha pass sem entry")
+			}
+			program := provider.Program
+			if program == "" {
+				program = "pass"
+			}
+			providers = append(providers, &usecases.PassPasswordProvider{Program: program, Entry: provider.Entry})
+		case "command":
+			if len(provider.Command) == 0 {
+				return nil, fmt.Errorf("provedor de senha command sem command")
+			}
+			providers = append(providers, &usecases.CommandPasswordProvider{Command: provider.Command})
+		default:
+			return nil, fmt.Errorf("tipo de provedor de senha desconhecido: %s", provider.Type)
+		}
+	}
+	return providers, nil
+}
+
+// unlockVaultHeadless abre a senha mestra pelos provedores de master_password,
+// pois com ela definida os metadados só são gravados cifrados
+func unlockVaultHeadless(vault *usecases.VaultService, providerConfigs []config.PasswordProviderConfig) error {
+	providers, err := newPasswordProviders(providerConfigs)
+	if err != nil {
+		return err
+	}
+	if len(providers) == 0 {
+		return errors.New(localization.Labels["headless_master_password"])
+	}
+	return vault.UnlockNonInteractive(context.Background(), providers)
+}
+
+// runPayments executa um pagamento em lote sem interface:
+// pay <endereço da wallet> <arquivo.csv> [--disperse]
+// A wallet é desbloqueada pelos provedores de senha e os resultados são gravados
+// ao lado do arquivo, então repetir o comando retoma as linhas pendentes. Lotes
+// acima do limite de dois fatores da wallet são recusados.
+func runPayments(service *usecases.WalletService, payments *usecases.PaymentService, twoFactor *usecases.TwoFactorService, args []string) error {
+	var positional []string
+	disperse := false
+	for _, arg := range args {
+		if arg == "--disperse" {
+			disperse = true
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	if len(positional) != 2 || !common.IsHexAddress(positional[0]) {
+		return errors.New(localization.Labels["pay_usage"])
+	}
+	if disperse && payments.Disperse == nil {
+		return errors.New(localization.Labels["pay_no_disperse"])
+	}
+	address, path := common.HexToAddress(positional[0]), positional[1]
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	wallets, err := service.GetAllWallets()
+	if err != nil {
+		return err
+	}
+	var details *usecases.WalletDetails
+	for i := range wallets {
+		if strings.EqualFold(wallets[i].Address, address.Hex()) {
+			if details, err = service.UnlockNonInteractive(ctx, &wallets[i]); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if details == nil {
+		return fmt.Errorf(localization.Labels["pay_unknown_wallet"], address.Hex())
+	}
+
+	items, err := payments.LoadPayments(ctx, path)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, p := range items {
+		if p.Status != usecases.PaymentSent {
+			pending++
+		}
+	}
+	if pending == 0 {
+		fmt.Println(localization.Labels["pay_nothing"])
+		return nil
+	}
+	totals, err := payments.Totals(ctx, address, items)
+	if err != nil {
+		return err
+	}
+	for _, total := range totals {
+		if total.Short() {
+			return fmt.Errorf(localization.Labels["pay_short"], total.Asset.Symbol)
+		}
+	}
+	// Sem terminal não há como pedir o código de dois fatores
+	signed := usecases.SignedTotal(totals)
+	required, err := twoFactor.Required(details.Wallet.ID, domain.TwoFactorSign, signed)
+	if err != nil {
+		return err
+	}
+	if required && signed == nil {
+		return fmt.Errorf(localization.Labels["pay_two_factor_tokens"], details.Wallet.Address)
+	}
+	if required {
+		return fmt.Errorf(localization.Labels["pay_two_factor"], details.Wallet.Address,
+			usecases.FormatUnits(signed, constants.NativeDecimals), constants.DefaultNativeSymbol)
+	}
+
+	batch, err := payments.Build(ctx, address, items, disperse)
+	if err != nil {
+		return err
+	}
+	if err := payments.Sign(ctx, details, batch, items); err != nil {
+		return err
+	}
+	fmt.Printf(localization.Labels["pay_signed"]+"\n", len(batch.Txs),
+		usecases.FormatUnits(batch.Fees, constants.NativeDecimals), constants.DefaultNativeSymbol)
+
+	results := usecases.ResultsPath(path)
+	for i, tx := range batch.Txs {
+		err := payments.Broadcast(ctx, tx)
+		batch.RecordBroadcast(items, i, err)
+		if writeErr := usecases.WriteResults(results, items); writeErr != nil {
+			return writeErr
+		}
+		if err != nil {
+			return fmt.Errorf(localization.Labels["pay_stopped"], i+1, len(batch.Txs), err, results)
+		}
+		fmt.Printf(localization.Labels["pay_sent"]+"\n", i+1, len(batch.Txs), tx.Hash().Hex())
+	}
+	fmt.Printf(localization.Labels["pay_done"]+"\n", len(batch.Txs), results)
+	return nil
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const aggregatorV3ABIJSON = `[
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"latestRoundData","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},
+		{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}]}
+]`
+
+var aggregatorV3ABI, _ = abi.JSON(strings.NewReader(aggregatorV3ABIJSON))
+
+// ChainlinkPriceProvider reads prices on-chain from Chainlink AggregatorV3 feeds.
+// Feeds are configured per asset symbol and must be quoted in the requested currency.
+type ChainlinkPriceProvider struct {
+	Client domain.ChainClient
+	Feeds  map[string]common.Address
+}
+
+var _ domain.PriceProvider = &ChainlinkPriceProvider{}
+
+func NewChainlinkPriceProvider(client domain.ChainClient, feeds map[string]string) *ChainlinkPriceProvider {
+	provider := &ChainlinkPriceProvider{Client: client, Feeds: map[string]common.Address{}}
+	for symbol, feed := range feeds {
+		if common.IsHexAddress(feed) {
+			provider.Feeds[strings.ToUpper(symbol)] = common.HexToAddress(feed)
+		}
+	}
+	return provider
+}
+
+func (p *ChainlinkPriceProvider) GetPrices(ctx context.Context, assets []domain.Asset, _ string) (map[string]domain.Price, error) {
+	prices := map[string]domain.Price{}
+	for _, asset := range assets {
+		feed, ok := p.Feeds[strings.ToUpper(asset.Symbol)]
+		if !ok {
+			continue
+		}
+		price, err := p.latestPrice(ctx, feed)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the %s price feed: %v", asset.Symbol, err)
+		}
+		prices[asset.ID()] = price
+	}
+	return prices, nil
+}
+
+func (p *ChainlinkPriceProvider) latestPrice(ctx context.Context, feed common.Address) (domain.Price, error) {
+	decimalsOut, err := p.call(ctx, feed, "decimals")
+	if err != nil {
+		return domain.Price{}, err
+	}
+	roundOut, err := p.call(ctx, feed, "latestRoundData")
+	if err != nil {
+		return domain.Price{}, err
+	}
+
+	decimals := decimalsOut[0].(uint8)
+	answer := roundOut[1].(*big.Int)
+	updatedAt := roundOut[3].(*big.Int)
+	if answer.Sign() <= 0 {
+		return domain.Price{}, fmt.Errorf("feed %s returned a non-positive answer", feed.Hex())
+	}
+
+	value, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(answer),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)),
+	).Float64()
+	return domain.Price{Value: value, UpdatedAt: time.Unix(updatedAt.Int64(), 0)}, nil
+}
+
+func (p *ChainlinkPriceProvider) call(ctx context.Context, feed common.Address, method string) ([]interface{}, error) {
+	data, err := aggregatorV3ABI.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	output, err := p.Client.CallContract(ctx, ethereum.CallMsg{To: &feed, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return aggregatorV3ABI.Unpack(method, output)
+}
@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// StaticCheckpoint trusts a block pinned by number and hash in the configuration.
+// The header is read from the untrusted endpoint and accepted only when its
+// recomputed hash matches, which authenticates the state root it carries.
+type StaticCheckpoint struct {
+	RPC    domain.RPCCaller
+	Number uint64
+	Hash   common.Hash
+}
+
+var _ domain.CheckpointProvider = &StaticCheckpoint{}
+
+func NewStaticCheckpoint(rpc domain.RPCCaller, number uint64, hash string) *StaticCheckpoint {
+	return &StaticCheckpoint{RPC: rpc, Number: number, Hash: common.HexToHash(hash)}
+}
+
+func (c *StaticCheckpoint) TrustedHeader(ctx context.Context) (*types.Header, error) {
+	header, err := fetchHeader(ctx, c.RPC, hexutil.EncodeUint64(c.Number))
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() != c.Hash {
+		return nil, fmt.Errorf("header of block %d hashes to %s, checkpoint is %s", c.Number, header.Hash().Hex(), c.Hash.Hex())
+	}
+	return header, nil
+}
+
+// TrustedNodeCheckpoint reads the header from a node the user controls, such as
+// a local full node or light client, at a tag like "finalized"
+type TrustedNodeCheckpoint struct {
+	RPC   domain.RPCCaller
+	Block string
+}
+
+var _ domain.CheckpointProvider = &TrustedNodeCheckpoint{}
+
+func NewTrustedNodeCheckpoint(rpc domain.RPCCaller, block string) *TrustedNodeCheckpoint {
+	return &TrustedNodeCheckpoint{RPC: rpc, Block: block}
+}
+
+func (c *TrustedNodeCheckpoint) TrustedHeader(ctx context.Context) (*types.Header, error) {
+	return fetchHeader(ctx, c.RPC, c.Block)
+}
+
+// fetchHeader reads a header through eth_getBlockByNumber. Nodes name the EIP-7685
+// field requestsHash, which the bundled header type does not decode, so it is read
+// separately; without it the recomputed hash of Prague blocks would not match.
+func fetchHeader(ctx context.Context, rpc domain.RPCCaller, block string) (*types.Header, error) {
+	var raw json.RawMessage
+	if err := rpc.CallContext(ctx, &raw, "eth_getBlockByNumber", block, false); err != nil {
+		return nil, fmt.Errorf("error reading block %s: %v", block, err)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, errors.New("block " + block + " not found")
+	}
+	var header types.Header
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("invalid header of block %s: %v", block, err)
+	}
+	var extra struct {
+		RequestsHash *common.Hash `json:"requestsHash"`
+	}
+	if err := json.Unmarshal(raw, &extra); err == nil && header.RequestsHash == nil {
+		header.RequestsHash = extra.RequestsHash
+	}
+	return &header, nil
+}
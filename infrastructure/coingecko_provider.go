@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CoinGeckoProvider implements domain.PriceProvider with the CoinGecko
+// simple price API or any service exposing the same endpoints.
+type CoinGeckoProvider struct {
+	BaseURL      string
+	APIKey       string
+	APIKeyHeader string
+	NativeID     string // Coin id of the native asset, e.g. "ethereum"
+	Platform     string // Asset platform used for token contracts, e.g. "ethereum"
+	HTTPClient   *http.Client
+}
+
+var _ domain.PriceProvider = &CoinGeckoProvider{}
+
+func NewCoinGeckoProvider(baseURL, apiKey, apiKeyHeader, nativeID, platform string) *CoinGeckoProvider {
+	return &CoinGeckoProvider{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		APIKey:       apiKey,
+		APIKeyHeader: apiKeyHeader,
+		NativeID:     nativeID,
+		Platform:     platform,
+		HTTPClient:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// coinGeckoQuotes maps a coin id or contract to the quote per currency plus last_updated_at
+type coinGeckoQuotes map[string]map[string]float64
+
+func (p *CoinGeckoProvider) GetPrices(ctx context.Context, assets []domain.Asset, currency string) (map[string]domain.Price, error) {
+	currency = strings.ToLower(currency)
+	prices := map[string]domain.Price{}
+
+	var contracts []string
+	for _, asset := range assets {
+		if asset.Contract == "" {
+			if p.NativeID == "" {
+				continue
+			}
+			params := url.Values{}
+			params.Set("ids", p.NativeID)
+			params.Set("vs_currencies", currency)
+			params.Set("include_last_updated_at", "true")
+			quotes, err := p.get(ctx, "/simple/price", params)
+			if err != nil {
+				return nil, err
+			}
+			if price, ok := quotes.price(p.NativeID, currency); ok {
+				prices[asset.ID()] = price
+			}
+		} else {
+			contracts = append(contracts, asset.ID())
+		}
+	}
+
+	if len(contracts) > 0 && p.Platform != "" {
+		params := url.Values{}
+		params.Set("contract_addresses", strings.Join(contracts, ","))
+		params.Set("vs_currencies", currency)
+		params.Set("include_last_updated_at", "true")
+		quotes, err := p.get(ctx, "/simple/token_price/"+url.PathEscape(p.Platform), params)
+		if err != nil {
+			return nil, err
+		}
+		for _, contract := range contracts {
+			if price, ok := quotes.price(contract, currency); ok {
+				prices[contract] = price
+			}
+		}
+	}
+
+	return prices, nil
+}
+
+func (p *CoinGeckoProvider) get(ctx context.Context, path string, params url.Values) (coinGeckoQuotes, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.APIKey != "" && p.APIKeyHeader != "" {
+		req.Header.Set(p.APIKeyHeader, p.APIKey)
+	}
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying the price API: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price API returned HTTP %d", resp.StatusCode)
+	}
+
+	var quotes coinGeckoQuotes
+	if err := json.NewDecoder(resp.Body).Decode(&quotes); err != nil {
+		return nil, fmt.Errorf("error decoding the price API response: %v", err)
+	}
+	// Contract addresses may come back with a different case
+	normalized := coinGeckoQuotes{}
+	for key, value := range quotes {
+		normalized[strings.ToLower(key)] = value
+	}
+	return normalized, nil
+}
+
+func (q coinGeckoQuotes) price(id, currency string) (domain.Price, bool) {
+	quote, ok := q[strings.ToLower(id)]
+	if !ok {
+		return domain.Price{}, false
+	}
+	value, ok := quote[currency]
+	if !ok {
+		return domain.Price{}, false
+	}
+	updatedAt := time.Now()
+	if timestamp, ok := quote["last_updated_at"]; ok && timestamp > 0 {
+		updatedAt = time.Unix(int64(timestamp), 0)
+	}
+	return domain.Price{Value: value, UpdatedAt: updatedAt}, true
+}
@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// DevChainID is the chain ID of go-ethereum's simulated backend
+const DevChainID = 1337
+
+// DevChain is an in-process development chain backed by go-ethereum's simulated
+// backend and served over HTTP JSON-RPC. Blocks are only produced by Mine.
+type DevChain struct {
+	backend  *simulated.Backend
+	Endpoint string
+}
+
+var _ domain.BlockProducer = &DevChain{}
+
+// NewDevChain starts the chain with the given accounts funded in the genesis block
+// and serves the eth, net and web3 namespaces on host:port.
+func NewDevChain(host string, port int, gasLimit uint64, prefund map[common.Address]*big.Int) (chain *DevChain, err error) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	// The simulated backend panics when the node fails to start, so check the port first
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("dev chain address %s is not available: %v", address, err)
+	}
+	if err := listener.Close(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error starting the dev chain: %v", r)
+		}
+	}()
+
+	alloc := make(types.GenesisAlloc, len(prefund))
+	for account, balance := range prefund {
+		alloc[account] = types.Account{Balance: balance}
+	}
+	options := []func(*node.Config, *ethconfig.Config){
+		func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+			nodeConf.HTTPHost = host
+			nodeConf.HTTPPort = port
+			nodeConf.HTTPModules = []string{"eth", "net", "web3"}
+			nodeConf.HTTPCors = []string{"*"}
+			nodeConf.HTTPVirtualHosts = []string{"localhost", host}
+			ethConf.NetworkId = DevChainID
+		},
+	}
+	if gasLimit > 0 {
+		options = append(options, simulated.WithBlockGasLimit(gasLimit))
+	}
+	backend := simulated.NewBackend(alloc, options...)
+
+	return &DevChain{
+		backend:  backend,
+		Endpoint: "http://" + address,
+	}, nil
+}
+
+// Mine seals the pending transactions into a new block
+func (c *DevChain) Mine(ctx context.Context) (uint64, error) {
+	c.backend.Commit()
+	return c.backend.Client().BlockNumber(ctx)
+}
+
+func (c *DevChain) Close() error {
+	return c.backend.Close()
+}
@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const ensABIJSON = `[
+	{"type":"function","name":"resolver","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"name","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"string"}]}
+]`
+
+var ensABI, _ = abi.JSON(strings.NewReader(ensABIJSON))
+
+// ErrNameNotFound is returned when a name has no resolver or no address record
+var ErrNameNotFound = errors.New("name not found")
+
+type ensCacheEntry struct {
+	value   string
+	err     error
+	expires time.Time
+}
+
+// ENSResolver resolves ENS names through the registry configured for the network.
+// Forward and reverse results, including misses, are cached for TTL.
+type ENSResolver struct {
+	Client   domain.ChainClient
+	Registry common.Address
+	TTL      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]ensCacheEntry
+}
+
+var _ domain.NameResolver = &ENSResolver{}
+
+func NewENSResolver(client domain.ChainClient, registry string, ttl time.Duration) *ENSResolver {
+	return &ENSResolver{
+		Client:   client,
+		Registry: common.HexToAddress(registry),
+		TTL:      ttl,
+		cache:    map[string]ensCacheEntry{},
+	}
+}
+
+// Resolve returns the address record of name
+func (r *ENSResolver) Resolve(ctx context.Context, name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return r.cached("forward:"+name, func() (string, error) {
+		node, err := namehash(name)
+		if err != nil {
+			return "", err
+		}
+		resolver, err := r.resolver(ctx, node)
+		if err != nil {
+			return "", err
+		}
+		out, err := r.call(ctx, resolver, "addr", node)
+		if err != nil {
+			return "", fmt.Errorf("error resolving %s: %v", name, err)
+		}
+		address := out[0].(common.Address)
+		if address == (common.Address{}) {
+			return "", ErrNameNotFound
+		}
+		return address.Hex(), nil
+	})
+}
+
+// LookupAddress returns the primary name of address. The reverse record is only
+// trusted when the name resolves back to the same address.
+func (r *ENSResolver) LookupAddress(ctx context.Context, address string) (string, error) {
+	if !common.IsHexAddress(address) {
+		return "", fmt.Errorf("invalid address: %s", address)
+	}
+	account := common.HexToAddress(address)
+	return r.cached("reverse:"+strings.ToLower(account.Hex()), func() (string, error) {
+		node, err := namehash(strings.ToLower(account.Hex()[2:]) + ".addr.reverse")
+		if err != nil {
+			return "", err
+		}
+		resolver, err := r.resolver(ctx, node)
+		if errors.Is(err, ErrNameNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		out, err := r.call(ctx, resolver, "name", node)
+		if err != nil {
+			return "", fmt.Errorf("error reading the reverse record of %s: %v", account.Hex(), err)
+		}
+		name, _ := out[0].(string)
+		if name == "" {
+			return "", nil
+		}
+
+		forward, err := r.Resolve(ctx, name)
+		if errors.Is(err, ErrNameNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !strings.EqualFold(forward, account.Hex()) {
+			return "", nil
+		}
+		return name, nil
+	})
+}
+
+func (r *ENSResolver) resolver(ctx context.Context, node [32]byte) (common.Address, error) {
+	if r.Registry == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("no ENS registry configured for this network")
+	}
+	out, err := r.call(ctx, r.Registry, "resolver", node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error querying the ENS registry: %v", err)
+	}
+	resolver := out[0].(common.Address)
+	if resolver == (common.Address{}) {
+		return common.Address{}, ErrNameNotFound
+	}
+	return resolver, nil
+}
+
+func (r *ENSResolver) call(ctx context.Context, contract common.Address, method string, node [32]byte) ([]interface{}, error) {
+	data, err := ensABI.Pack(method, node)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.Client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ensABI.Unpack(method, output)
+}
+
+// cached returns a fresh cache entry or computes and stores a new one.
+// Transient errors are not cached so the next lookup retries.
+func (r *ENSResolver) cached(key string, compute func() (string, error)) (string, error) {
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.value, entry.err
+	}
+
+	value, err := compute()
+	if err == nil || errors.Is(err, ErrNameNotFound) {
+		r.mu.Lock()
+		r.cache[key] = ensCacheEntry{value: value, err: err, expires: time.Now().Add(r.TTL)}
+		r.mu.Unlock()
+	}
+	return value, err
+}
+
+// namehash implements the EIP-137 name hash. Names are lowercased; full UTS-46
+// normalization is not applied, so names with non-ASCII characters are rejected.
+func namehash(name string) ([32]byte, error) {
+	var node [32]byte
+	if name == "" {
+		return node, nil
+	}
+	for _, c := range name {
+		if c > 0x7f {
+			return node, fmt.Errorf("unsupported characters in name %q", name)
+		}
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		if labels[i] == "" {
+			return node, fmt.Errorf("invalid name %q", name)
+		}
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		copy(node[:], crypto.Keccak256(node[:], labelHash))
+	}
+	return node, nil
+}
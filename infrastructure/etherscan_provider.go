@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EtherscanProvider implements domain.HistoryProvider on top of the Etherscan
+// account API. Any explorer exposing the same API (Blockscout, Routescan, a
+// local stand-in) can be used by pointing BaseURL to it.
+type EtherscanProvider struct {
+	BaseURL    string
+	APIKey     string
+	ChainID    int64
+	HTTPClient *http.Client
+}
+
+var _ domain.HistoryProvider = &EtherscanProvider{}
+
+func NewEtherscanProvider(baseURL, apiKey string, chainID int64) *EtherscanProvider {
+	return &EtherscanProvider{
+		BaseURL:    baseURL,
+		APIKey:     apiKey,
+		ChainID:    chainID,
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type etherscanResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type etherscanTx struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	GasPrice        string `json:"gasPrice"`
+	GasUsed         string `json:"gasUsed"`
+	IsError         string `json:"isError"`
+	ContractAddress string `json:"contractAddress"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenDecimal    string `json:"tokenDecimal"`
+}
+
+var etherscanActions = map[domain.HistoryKind]string{
+	domain.HistoryNormal:   "txlist",
+	domain.HistoryInternal: "txlistinternal",
+	domain.HistoryToken:    "tokentx",
+}
+
+func (p *EtherscanProvider) FetchHistory(ctx context.Context, address string, kind domain.HistoryKind, page, pageSize int) ([]domain.HistoryEntry, error) {
+	action, ok := etherscanActions[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported history kind: %s", kind)
+	}
+
+	params := url.Values{}
+	params.Set("module", "account")
+	params.Set("action", action)
+	params.Set("address", address)
+	params.Set("startblock", "0")
+	params.Set("endblock", "99999999")
+	params.Set("page", strconv.Itoa(page))
+	params.Set("offset", strconv.Itoa(pageSize))
+	params.Set("sort", "desc")
+	if p.ChainID > 0 {
+		params.Set("chainid", strconv.FormatInt(p.ChainID, 10))
+	}
+	if p.APIKey != "" {
+		params.Set("apikey", p.APIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying the explorer: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("explorer returned HTTP %d", resp.StatusCode)
+	}
+
+	var body etherscanResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error decoding the explorer response: %v", err)
+	}
+
+	var txs []etherscanTx
+	if err := json.Unmarshal(body.Result, &txs); err != nil {
+		// On failure Etherscan returns a plain string in the result field
+		var message string
+		if json.Unmarshal(body.Result, &message) == nil && message != "" {
+			return nil, fmt.Errorf("explorer error: %s", message)
+		}
+		return nil, fmt.Errorf("explorer error: %s", body.Message)
+	}
+	if body.Status != "1" && len(txs) == 0 && !strings.HasPrefix(body.Message, "No transactions") {
+		return nil, fmt.Errorf("explorer error: %s", body.Message)
+	}
+
+	entries := make([]domain.HistoryEntry, 0, len(txs))
+	for _, tx := range txs {
+		entries = append(entries, tx.toEntry(kind))
+	}
+	return entries, nil
+}
+
+func (tx etherscanTx) toEntry(kind domain.HistoryKind) domain.HistoryEntry {
+	blockNumber, _ := strconv.ParseUint(tx.BlockNumber, 10, 64)
+	timestamp, _ := strconv.ParseInt(tx.TimeStamp, 10, 64)
+	decimals := 18
+	if tx.TokenDecimal != "" {
+		decimals, _ = strconv.Atoi(tx.TokenDecimal)
+	}
+
+	entry := domain.HistoryEntry{
+		Kind:            kind,
+		Hash:            tx.Hash,
+		BlockNumber:     blockNumber,
+		Timestamp:       time.Unix(timestamp, 0),
+		From:            tx.From,
+		To:              tx.To,
+		Value:           tx.Value,
+		ContractAddress: tx.ContractAddress,
+		TokenSymbol:     tx.TokenSymbol,
+		TokenDecimals:   decimals,
+		Failed:          tx.IsError == "1",
+	}
+
+	// Internal transactions don't carry gas price; the fee belongs to the parent transaction
+	gasPrice, okPrice := new(big.Int).SetString(tx.GasPrice, 10)
+	gasUsed, okUsed := new(big.Int).SetString(tx.GasUsed, 10)
+	if kind == domain.HistoryNormal && okPrice && okUsed {
+		entry.Fee = new(big.Int).Mul(gasPrice, gasUsed).String()
+	}
+	return entry
+}
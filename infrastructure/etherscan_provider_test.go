@@ -0,0 +1,161 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// explorerStandIn answers the Etherscan account API for one address holding
+// total normal transactions, newest first, and records the queries it received
+type explorerStandIn struct {
+	address   string
+	total     int
+	rateLimit bool
+	queries   []map[string]string
+}
+
+func (s *explorerStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := map[string]string{}
+	for key := range r.URL.Query() {
+		query[key] = r.URL.Query().Get(key)
+	}
+	s.queries = append(s.queries, query)
+
+	reply := func(status, message string, result interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": status, "message": message, "result": result})
+	}
+	if s.rateLimit {
+		reply("0", "NOTOK", "Max rate limit reached")
+		return
+	}
+	if query["module"] != "account" || query["action"] != "txlist" {
+		reply("0", "NOTOK", "Error! Missing Or invalid Action name")
+		return
+	}
+
+	page, _ := strconv.Atoi(query["page"])
+	offset, _ := strconv.Atoi(query["offset"])
+	var txs []etherscanTx
+	if strings.EqualFold(query["address"], s.address) {
+		for i := (page - 1) * offset; i < page*offset && i < s.total; i++ {
+			block := s.total - i
+			txs = append(txs, etherscanTx{
+				BlockNumber: strconv.Itoa(block),
+				TimeStamp:   strconv.Itoa(1_700_000_000 + block),
+				Hash:        fmt.Sprintf("0x%064x", block),
+				From:        s.address,
+				To:          "0x00000000000000000000000000000000000b10c0",
+				Value:       "1000",
+				GasPrice:    "2",
+				GasUsed:     "21000",
+				IsError:     "0",
+			})
+		}
+	}
+	if len(txs) == 0 {
+		reply("0", "No transactions found", []etherscanTx{})
+		return
+	}
+	reply("1", "OK", txs)
+}
+
+func TestEtherscanProviderPaging(t *testing.T) {
+	standIn := &explorerStandIn{address: "0x00000000000000000000000000000000000ca5e5", total: 5}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+	provider := NewEtherscanProvider(server.URL, "key", 11155111)
+	ctx := context.Background()
+
+	tests := []struct {
+		page       int
+		wantBlocks []uint64
+	}{
+		{page: 1, wantBlocks: []uint64{5, 4}},
+		{page: 2, wantBlocks: []uint64{3, 2}},
+		{page: 3, wantBlocks: []uint64{1}},
+		{page: 4},
+	}
+	for _, tt := range tests {
+		entries, err := provider.FetchHistory(ctx, standIn.address, domain.HistoryNormal, tt.page, 2)
+		if err != nil {
+			t.Fatalf("page %d: %v", tt.page, err)
+		}
+		if len(entries) != len(tt.wantBlocks) {
+			t.Fatalf("page %d: got %d entries, want %d", tt.page, len(entries), len(tt.wantBlocks))
+		}
+		for i, entry := range entries {
+			if entry.BlockNumber != tt.wantBlocks[i] {
+				t.Errorf("page %d entry %d: block %d, want %d", tt.page, i, entry.BlockNumber, tt.wantBlocks[i])
+			}
+			if entry.Kind != domain.HistoryNormal || entry.Fee != "42000" {
+				t.Errorf("page %d entry %d: kind %s fee %s", tt.page, i, entry.Kind, entry.Fee)
+			}
+		}
+
+		query := standIn.queries[len(standIn.queries)-1]
+		want := map[string]string{
+			"page":    strconv.Itoa(tt.page),
+			"offset":  "2",
+			"sort":    "desc",
+			"chainid": "11155111",
+			"apikey":  "key",
+		}
+		for key, value := range want {
+			if query[key] != value {
+				t.Errorf("page %d: %s = %q, want %q", tt.page, key, query[key], value)
+			}
+		}
+	}
+}
+
+func TestEtherscanProviderNoTransactions(t *testing.T) {
+	standIn := &explorerStandIn{address: "0x00000000000000000000000000000000000ca5e5"}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+	provider := NewEtherscanProvider(server.URL, "", 1)
+
+	entries, err := provider.FetchHistory(context.Background(), standIn.address, domain.HistoryNormal, 1, 25)
+	if err != nil {
+		t.Fatalf("an empty history was reported as an error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %d entries, want none", len(entries))
+	}
+	if _, ok := standIn.queries[0]["apikey"]; ok {
+		t.Error("an empty API key was sent")
+	}
+}
+
+func TestEtherscanProviderErrors(t *testing.T) {
+	standIn := &explorerStandIn{address: "0x00000000000000000000000000000000000ca5e5", total: 1, rateLimit: true}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+	provider := NewEtherscanProvider(server.URL, "key", 1)
+	ctx := context.Background()
+
+	_, err := provider.FetchHistory(ctx, standIn.address, domain.HistoryNormal, 1, 25)
+	if err == nil || !strings.Contains(err.Error(), "Max rate limit reached") {
+		t.Errorf("rate limit: got %v, want the explorer's message", err)
+	}
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	provider.BaseURL = unavailable.URL
+	_, err = provider.FetchHistory(ctx, standIn.address, domain.HistoryNormal, 1, 25)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("HTTP error: got %v, want the status code", err)
+	}
+
+	if _, err := provider.FetchHistory(ctx, standIn.address, domain.HistoryKind("unknown"), 1, 25); err == nil {
+		t.Error("an unsupported kind was queried")
+	}
+}
@@ -0,0 +1,598 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FailoverOptions tunes health checks, rate limiting and caching of a FailoverTransport
+type FailoverOptions struct {
+	HealthInterval time.Duration // Time between background health checks
+	Timeout        time.Duration // Limit for a single attempt against one endpoint
+	MaxHeadLag     uint64        // Blocks an endpoint may trail the best head and stay healthy
+	RateLimit      float64       // Requests per second sent to each endpoint, 0 disables the limit
+	CacheDepth     uint64        // Confirmations before results bound to a block are cached
+}
+
+// FailoverTransport is an http.RoundTripper for go-ethereum's RPC client that
+// spreads JSON-RPC requests over several endpoints of the same network. Requests
+// go to the first healthy endpoint in configuration order and move to the next
+// one on network errors or non-200 responses such as 429 and 5xx. Immutable results are served from
+// the cache when one is configured.
+type FailoverTransport struct {
+	ChainID int64
+	Cache   domain.RPCCache
+	Options FailoverOptions
+
+	endpoints []*rpcEndpoint
+	client    *http.Client
+	active    atomic.Int32
+	head      atomic.Uint64 // Best head seen by the last health check
+	stop      chan struct{}
+
+	genesisMu sync.Mutex
+	genesis   string // Genesis hash of the network, learned from the first endpoint that answers
+	closeOnce sync.Once
+}
+
+var _ domain.EndpointMonitor = &FailoverTransport{}
+
+type rpcEndpoint struct {
+	url     string
+	limiter *rateLimiter
+
+	mu         sync.Mutex
+	health     domain.EndpointHealth
+	wrongChain bool   // Never used, not even as a last resort
+	genesis    string // Genesis hash reported by the endpoint
+}
+
+type rpcMessage struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   json.RawMessage   `json:"error,omitempty"`
+}
+
+// NewFailoverTransport checks every endpoint once before returning, so the first
+// request already goes to a healthy one, and keeps checking them in the background
+// until Close. Only HTTP(S) endpoints are supported.
+func NewFailoverTransport(urls []string, chainID int64, cache domain.RPCCache, options FailoverOptions) (*FailoverTransport, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no RPC endpoints configured")
+	}
+	t := &FailoverTransport{
+		ChainID: chainID,
+		Cache:   cache,
+		Options: options,
+		client:  &http.Client{},
+		stop:    make(chan struct{}),
+	}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPC endpoint %q: %v", raw, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("RPC endpoint %s: failover only supports http and https", redactURL(raw))
+		}
+		t.endpoints = append(t.endpoints, &rpcEndpoint{
+			url:     raw,
+			limiter: newRateLimiter(options.RateLimit),
+			health:  domain.EndpointHealth{URL: redactURL(raw)},
+		})
+	}
+
+	t.CheckHealth(context.Background())
+	if options.HealthInterval > 0 {
+		go t.watch()
+	}
+	return t, nil
+}
+
+// IsHTTPEndpoint reports whether rawURL can be served by a FailoverTransport
+func IsHTTPEndpoint(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// Dial returns an RPC client whose requests all go through the transport
+func (t *FailoverTransport) Dial(ctx context.Context) (*rpc.Client, error) {
+	return rpc.DialOptions(ctx, t.endpoints[0].url, rpc.WithHTTPClient(&http.Client{Transport: t}))
+}
+
+// Close stops the background health checks
+func (t *FailoverTransport) Close() {
+	t.closeOnce.Do(func() { close(t.stop) })
+}
+
+// ActiveEndpoint returns the health of the endpoint that served the last request
+func (t *FailoverTransport) ActiveEndpoint() domain.EndpointHealth {
+	ep := t.endpoints[t.active.Load()]
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.health
+}
+
+// Endpoints returns the health of every endpoint in configuration order
+func (t *FailoverTransport) Endpoints() []domain.EndpointHealth {
+	health := make([]domain.EndpointHealth, 0, len(t.endpoints))
+	for _, ep := range t.endpoints {
+		ep.mu.Lock()
+		health = append(health, ep.health)
+		ep.mu.Unlock()
+	}
+	return health
+}
+
+func (t *FailoverTransport) watch() {
+	ticker := time.NewTicker(t.Options.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.CheckHealth(context.Background())
+		}
+	}
+}
+
+// CheckHealth probes every endpoint for latency, head block and chain ID. Endpoints
+// on another chain or trailing the best head by more than MaxHeadLag are unhealthy.
+func (t *FailoverTransport) CheckHealth(ctx context.Context) {
+	results := make([]domain.EndpointHealth, len(t.endpoints))
+	wrongChain := make([]bool, len(t.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range t.endpoints {
+		wg.Add(1)
+		go func(i int, ep *rpcEndpoint) {
+			defer wg.Done()
+			results[i], wrongChain[i] = t.probe(ctx, ep)
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var best uint64
+	for _, health := range results {
+		if health.Healthy && health.Head > best {
+			best = health.Head
+		}
+	}
+	for i, ep := range t.endpoints {
+		health := results[i]
+		if health.Healthy && t.Options.MaxHeadLag > 0 && health.Head+t.Options.MaxHeadLag < best {
+			health.Healthy = false
+			health.Error = fmt.Sprintf("%d blocks behind", best-health.Head)
+		}
+		ep.mu.Lock()
+		ep.health = health
+		ep.wrongChain = wrongChain[i]
+		ep.mu.Unlock()
+	}
+	if best > 0 {
+		t.head.Store(best)
+	}
+	if order := t.order(); len(order) > 0 {
+		t.active.Store(int32(order[0]))
+	}
+}
+
+// probe returns the health of one endpoint and whether it serves another chain
+func (t *FailoverTransport) probe(ctx context.Context, ep *rpcEndpoint) (domain.EndpointHealth, bool) {
+	health := domain.EndpointHealth{URL: redactURL(ep.url), CheckedAt: time.Now()}
+
+	var chainID hexutil.Big
+	if _, err := t.call(ctx, ep, "eth_chainId", &chainID); err != nil {
+		health.Error = err.Error()
+		return health, false
+	}
+	if t.ChainID != 0 && chainID.ToInt().Cmp(big.NewInt(t.ChainID)) != 0 {
+		health.Error = fmt.Sprintf("chain ID %s, expected %d", chainID.ToInt(), t.ChainID)
+		return health, true
+	}
+	genesis, err := t.endpointGenesis(ctx, ep)
+	if err != nil {
+		health.Error = err.Error()
+		return health, false
+	}
+	if expected := t.networkGenesis(genesis); genesis != expected {
+		health.Error = fmt.Sprintf("genesis %s, expected %s", genesis, expected)
+		return health, true
+	}
+
+	var head hexutil.Uint64
+	latency, err := t.call(ctx, ep, "eth_blockNumber", &head)
+	if err != nil {
+		health.Error = err.Error()
+		return health, false
+	}
+	health.Latency = latency
+	health.Head = uint64(head)
+	health.Healthy = true
+	return health, false
+}
+
+// call sends a request straight to one endpoint and returns its round-trip time,
+// not counting the wait for the rate limiter
+func (t *FailoverTransport) call(ctx context.Context, ep *rpcEndpoint, method string, result interface{}, params ...interface{}) (time.Duration, error) {
+	raw := make([]json.RawMessage, 0, len(params))
+	for _, param := range params {
+		encoded, err := json.Marshal(param)
+		if err != nil {
+			return 0, err
+		}
+		raw = append(raw, encoded)
+	}
+	body, err := json.Marshal(rpcMessage{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: method, Params: raw})
+	if err != nil {
+		return 0, err
+	}
+	if err := ep.limiter.wait(ctx); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	payload, err := t.post(ctx, ep, body, nil)
+	if err != nil {
+		return 0, err
+	}
+	latency := time.Since(start)
+	var response rpcMessage
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return 0, fmt.Errorf("invalid %s response: %v", method, err)
+	}
+	if len(response.Error) > 0 {
+		return 0, fmt.Errorf("%s failed: %s", method, response.Error)
+	}
+	return latency, json.Unmarshal(response.Result, result)
+}
+
+// endpointGenesis returns the hash of the endpoint's genesis block, asked once
+func (t *FailoverTransport) endpointGenesis(ctx context.Context, ep *rpcEndpoint) (string, error) {
+	ep.mu.Lock()
+	genesis := ep.genesis
+	ep.mu.Unlock()
+	if genesis != "" {
+		return genesis, nil
+	}
+	var block struct {
+		Hash string `json:"hash"`
+	}
+	if _, err := t.call(ctx, ep, "eth_getBlockByNumber", &block, "0x0", false); err != nil {
+		return "", err
+	}
+	if block.Hash == "" {
+		return "", errors.New("the endpoint has no genesis block")
+	}
+	genesis = strings.ToLower(block.Hash)
+	ep.mu.Lock()
+	ep.genesis = genesis
+	ep.mu.Unlock()
+	return genesis, nil
+}
+
+// networkGenesis returns the genesis hash of the network, adopting genesis when
+// none is known yet
+func (t *FailoverTransport) networkGenesis(genesis string) string {
+	t.genesisMu.Lock()
+	defer t.genesisMu.Unlock()
+	if t.genesis == "" {
+		t.genesis = genesis
+	}
+	return t.genesis
+}
+
+// cacheKey binds the request to the network's genesis, since chains such as
+// local devnets reuse a chain ID; nothing is cached before the genesis is known
+func (t *FailoverTransport) cacheKey(request rpcMessage) string {
+	key := rpcCacheKey(request)
+	t.genesisMu.Lock()
+	genesis := t.genesis
+	t.genesisMu.Unlock()
+	if key == "" || genesis == "" {
+		return ""
+	}
+	return genesis + "/" + key
+}
+
+// order lists endpoint indexes with healthy endpoints first, both groups in
+// configuration order, so unhealthy endpoints remain a last resort. Endpoints
+// serving another chain are left out.
+func (t *FailoverTransport) order() []int {
+	var healthy, unhealthy []int
+	for i, ep := range t.endpoints {
+		ep.mu.Lock()
+		ok, skip := ep.health.Healthy, ep.wrongChain
+		ep.mu.Unlock()
+		if skip {
+			continue
+		}
+		if ok {
+			healthy = append(healthy, i)
+		} else {
+			unhealthy = append(unhealthy, i)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Batches are forwarded as they are; only single requests are cached
+	var request rpcMessage
+	cacheKey := ""
+	if t.Cache != nil && json.Unmarshal(body, &request) == nil {
+		cacheKey = t.cacheKey(request)
+	}
+	if cacheKey != "" {
+		result, ok, err := t.Cache.GetRPCResult(t.ChainID, cacheKey)
+		if err != nil {
+			log.Printf("Error reading the RPC cache: %v\n", err)
+		}
+		if ok {
+			return t.cachedResponse(req, request.ID, result)
+		}
+	}
+
+	ctx := req.Context()
+	var lastErr error
+	for _, index := range t.order() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		ep := t.endpoints[index]
+		err := ep.limiter.wait(ctx)
+		var payload []byte
+		if err == nil {
+			payload, err = t.post(ctx, ep, body, req.Header)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				ep.fail(err)
+			}
+			lastErr = err
+			continue
+		}
+		t.active.Store(int32(index))
+		if cacheKey != "" {
+			t.store(cacheKey, request, payload)
+		}
+		return jsonResponse(req, payload), nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no endpoint serves chain %d", t.ChainID)
+	}
+	return nil, fmt.Errorf("all RPC endpoints failed: %v", lastErr)
+}
+
+// post sends body to one endpoint and returns the response payload. Network
+// errors and non-200 responses are errors so the caller moves to the next endpoint.
+func (t *FailoverTransport) post(ctx context.Context, ep *rpcEndpoint, body []byte, header http.Header) ([]byte, error) {
+	if t.Options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Options.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if header != nil {
+		req.Header = header.Clone()
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", redactURL(ep.url), resp.Status)
+	}
+	return payload, nil
+}
+
+func (t *FailoverTransport) cachedResponse(req *http.Request, id json.RawMessage, result []byte) (*http.Response, error) {
+	payload, err := json.Marshal(rpcMessage{JSONRPC: "2.0", ID: id, Result: result})
+	if err != nil {
+		return nil, err
+	}
+	return jsonResponse(req, payload), nil
+}
+
+func jsonResponse(req *http.Request, payload []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(payload)),
+		ContentLength: int64(len(payload)),
+		Request:       req,
+	}
+}
+
+func (t *FailoverTransport) store(key string, request rpcMessage, payload []byte) {
+	var response rpcMessage
+	if json.Unmarshal(payload, &response) != nil || len(response.Error) > 0 {
+		return
+	}
+	if !t.immutable(request, response.Result) {
+		return
+	}
+	if err := t.Cache.SaveRPCResult(t.ChainID, key, response.Result); err != nil {
+		log.Printf("Error writing the RPC cache: %v\n", err)
+	}
+}
+
+// rpcCacheKey returns the cache key of methods whose results may become immutable
+func rpcCacheKey(request rpcMessage) string {
+	switch request.Method {
+	case "eth_getBlockByHash", "eth_getBlockByNumber", "eth_getTransactionReceipt", "eth_getTransactionByHash",
+		"eth_getBalance", "eth_getCode", "eth_getTransactionCount", "eth_getStorageAt", "eth_call", "eth_getLogs":
+	default:
+		return ""
+	}
+	params, err := json.Marshal(request.Params)
+	if err != nil {
+		return ""
+	}
+	return request.Method + ":" + string(params)
+}
+
+// immutable reports whether result can no longer change: blocks by hash, and
+// anything bound to a block with at least CacheDepth confirmations
+func (t *FailoverTransport) immutable(request rpcMessage, result json.RawMessage) bool {
+	if len(result) == 0 || string(result) == "null" {
+		return false
+	}
+	switch request.Method {
+	case "eth_getBlockByHash":
+		return true
+	case "eth_getBlockByNumber":
+		return t.settledParam(request.Params, 0)
+	case "eth_getTransactionReceipt", "eth_getTransactionByHash":
+		var mined struct {
+			BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+		}
+		if json.Unmarshal(result, &mined) != nil || mined.BlockNumber == nil {
+			return false
+		}
+		return t.settled(uint64(*mined.BlockNumber))
+	case "eth_getBalance", "eth_getCode", "eth_getTransactionCount", "eth_call":
+		return t.settledParam(request.Params, 1)
+	case "eth_getStorageAt":
+		return t.settledParam(request.Params, 2)
+	case "eth_getLogs":
+		if len(request.Params) == 0 {
+			return false
+		}
+		var filter struct {
+			FromBlock json.RawMessage `json:"fromBlock"`
+			ToBlock   json.RawMessage `json:"toBlock"`
+		}
+		if json.Unmarshal(request.Params[0], &filter) != nil {
+			return false
+		}
+		from, ok := blockNumberParam(filter.FromBlock)
+		if !ok {
+			return false
+		}
+		to, ok := blockNumberParam(filter.ToBlock)
+		return ok && from <= to && t.settled(to)
+	}
+	return false
+}
+
+func (t *FailoverTransport) settledParam(params []json.RawMessage, index int) bool {
+	if index >= len(params) {
+		return false
+	}
+	number, ok := blockNumberParam(params[index])
+	return ok && t.settled(number)
+}
+
+func (t *FailoverTransport) settled(number uint64) bool {
+	head := t.head.Load()
+	return head > 0 && number+t.Options.CacheDepth <= head
+}
+
+// blockNumberParam decodes an explicit block number; tags such as "latest" are rejected
+func blockNumberParam(raw json.RawMessage) (uint64, bool) {
+	var tag string
+	if json.Unmarshal(raw, &tag) != nil || !strings.HasPrefix(tag, "0x") {
+		return 0, false
+	}
+	number, err := hexutil.DecodeUint64(tag)
+	return number, err == nil
+}
+
+func (ep *rpcEndpoint) fail(err error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.health.Healthy = false
+	ep.health.Error = err.Error()
+	ep.health.CheckedAt = time.Now()
+}
+
+// redactURL hides credentials and API keys in the path or query of an endpoint URL
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Host
+}
+
+// rateLimiter spaces requests evenly at the configured rate
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// memoryRPCCache is an in-memory domain.RPCCache
+type memoryRPCCache struct {
+	mu      sync.Mutex
+	results map[string][]byte
+}
+
+func (c *memoryRPCCache) GetRPCResult(chainID int64, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.results[key]
+	return result, ok, nil
+}
+
+func (c *memoryRPCCache) SaveRPCResult(chainID int64, key string, result []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[key] = result
+	return nil
+}
+
+func newTestDevChain(t *testing.T, prefund map[common.Address]*big.Int) *DevChain {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	chain, err := NewDevChain("127.0.0.1", port, 0, prefund)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	// Settle the genesis block so its results can be cached
+	if _, err := chain.Mine(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return chain
+}
+
+func TestFailoverCacheSeparatesChainsSharingAnID(t *testing.T) {
+	account := common.HexToAddress("0x00000000000000000000000000000000000ca5e5")
+	funded := big.NewInt(1_000_000)
+	chains := []struct {
+		name    string
+		chain   *DevChain
+		balance *big.Int
+	}{
+		{name: "funded", chain: newTestDevChain(t, map[common.Address]*big.Int{account: funded}), balance: funded},
+		{name: "empty", chain: newTestDevChain(t, nil), balance: new(big.Int)},
+	}
+
+	cache := &memoryRPCCache{results: map[string][]byte{}}
+	ctx := context.Background()
+	for _, c := range chains {
+		transport, err := NewFailoverTransport([]string{c.chain.Endpoint}, DevChainID, cache, FailoverOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer transport.Close()
+		rpcClient, err := transport.Dial(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := ethclient.NewClient(rpcClient)
+		defer client.Close()
+
+		// The second read is served from the cache
+		for i := 0; i < 2; i++ {
+			balance, err := client.BalanceAt(ctx, account, big.NewInt(0))
+			if err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+			if balance.Cmp(c.balance) != 0 {
+				t.Errorf("%s: read %d: balance %s, want %s", c.name, i+1, balance, c.balance)
+			}
+		}
+	}
+
+	var balanceKeys []string
+	for key := range cache.results {
+		if strings.Contains(key, "eth_getBalance") {
+			balanceKeys = append(balanceKeys, key)
+		}
+	}
+	if len(balanceKeys) != 2 {
+		t.Errorf("got %d cached balances, want one per chain: %v", len(balanceKeys), balanceKeys)
+	}
+}
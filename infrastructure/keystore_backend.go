@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeystoreBackend keeps keys as KeyStoreV3 files named after their address
+type KeystoreBackend struct {
+	KeyStore *keystore.KeyStore
+}
+
+var (
+	_ domain.KeyBackend  = &KeystoreBackend{}
+	_ domain.KeyExporter = &KeystoreBackend{}
+)
+
+func NewKeystoreBackend(ks *keystore.KeyStore) *KeystoreBackend {
+	return &KeystoreBackend{KeyStore: ks}
+}
+
+func (b *KeystoreBackend) Name() string {
+	return domain.DefaultKeyBackend
+}
+
+func (b *KeystoreBackend) CreateKey(ctx context.Context, password string) (string, error) {
+	account, err := b.KeyStore.NewAccount(password)
+	if err != nil {
+		return "", err
+	}
+	return renameKeyFile(account.URL.Path, account.Address.Hex())
+}
+
+func (b *KeystoreBackend) ImportKey(ctx context.Context, key *ecdsa.PrivateKey, password string) (string, error) {
+	account, err := b.KeyStore.ImportECDSA(key, password)
+	if err != nil {
+		return "", err
+	}
+	return renameKeyFile(account.URL.Path, account.Address.Hex())
+}
+
+func (b *KeystoreBackend) SignDigest(ctx context.Context, ref, password string, digest []byte) ([]byte, error) {
+	key, err := b.ExportKey(ctx, ref, password)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(digest, key)
+}
+
+func (b *KeystoreBackend) PublicKey(ctx context.Context, ref, password string) (*ecdsa.PublicKey, error) {
+	key, err := b.ExportKey(ctx, ref, password)
+	if err != nil {
+		return nil, err
+	}
+	return &key.PublicKey, nil
+}
+
+func (b *KeystoreBackend) ExportKey(ctx context.Context, ref, password string) (*ecdsa.PrivateKey, error) {
+	keyJSON, err := os.ReadFile(ref)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the wallet file: %v", err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, domain.ErrIncorrectPassword
+	}
+	return key.PrivateKey, nil
+}
+
+func (b *KeystoreBackend) DeleteKey(ctx context.Context, ref string) error {
+	err := os.Remove(ref)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove keystore file: %v", err)
+	}
+	return nil
+}
+
+// renameKeyFile gives the keystore file the address as its name
+func renameKeyFile(path, address string) (string, error) {
+	newPath := filepath.Join(filepath.Dir(path), fmt.Sprintf("%s.json", address))
+	if err := os.Rename(path, newPath); err != nil {
+		return "", fmt.Errorf("error renaming the wallet file: %v", err)
+	}
+	return newPath, nil
+}
@@ -0,0 +1,220 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KMSOptions configures the AWS KMS client. Endpoint overrides the regional
+// endpoint, e.g. to point at a local KMS emulator.
+type KMSOptions struct {
+	Region         string
+	Endpoint       string
+	Profile        string
+	DeleteKeys     bool  // When false, deleting a wallet keeps its key in KMS
+	DeletionWindow int32 // Days before a scheduled key deletion takes effect
+}
+
+// KMSBackend keeps ECC_SECG_P256K1 keys in AWS KMS and signs digests with the
+// KMS Sign API, so private keys never leave KMS. Credentials come from the
+// standard AWS chain (environment, shared config, instance role).
+type KMSBackend struct {
+	Options KMSOptions
+
+	client *kms.Client
+	mu     sync.Mutex
+	keys   map[string]*ecdsa.PublicKey // Public keys already read, by key id
+}
+
+var _ domain.KeyBackend = &KMSBackend{}
+var _ domain.KeyDiscarder = &KMSBackend{}
+
+func NewKMSBackend(ctx context.Context, options KMSOptions) (*KMSBackend, error) {
+	var loaders []func(*awsconfig.LoadOptions) error
+	if options.Region != "" {
+		loaders = append(loaders, awsconfig.WithRegion(options.Region))
+	}
+	if options.Profile != "" {
+		loaders = append(loaders, awsconfig.WithSharedConfigProfile(options.Profile))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, loaders...)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the AWS configuration: %v", err)
+	}
+	client := kms.NewFromConfig(awsConfig, func(o *kms.Options) {
+		if options.Endpoint != "" {
+			o.BaseEndpoint = aws.String(options.Endpoint)
+		}
+	})
+	return &KMSBackend{
+		Options: options,
+		client:  client,
+		keys:    map[string]*ecdsa.PublicKey{},
+	}, nil
+}
+
+func (b *KMSBackend) Name() string {
+	return "kms"
+}
+
+func (b *KMSBackend) CreateKey(ctx context.Context, password string) (string, error) {
+	created, err := b.client.CreateKey(ctx, &kms.CreateKeyInput{
+		KeySpec:     types.KeySpecEccSecgP256k1,
+		KeyUsage:    types.KeyUsageTypeSignVerify,
+		Description: aws.String("BLOCO wallet key"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating the key in KMS: %v", err)
+	}
+	return aws.ToString(created.KeyMetadata.KeyId), nil
+}
+
+// ImportKey creates a key with external origin and imports the private key as
+// its key material, wrapped with the RSA key KMS issues for the import
+func (b *KMSBackend) ImportKey(ctx context.Context, key *ecdsa.PrivateKey, password string) (string, error) {
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	created, err := b.client.CreateKey(ctx, &kms.CreateKeyInput{
+		KeySpec:     types.KeySpecEccSecgP256k1,
+		KeyUsage:    types.KeyUsageTypeSignVerify,
+		Origin:      types.OriginTypeExternal,
+		Description: aws.String("BLOCO wallet key " + address),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating the key in KMS: %v", err)
+	}
+	keyID := aws.ToString(created.KeyMetadata.KeyId)
+
+	if err := b.importKeyMaterial(ctx, keyID, key); err != nil {
+		// A key without material is unusable, so don't leave it behind; if that
+		// fails too, name the key so it can be removed by hand
+		if deleteErr := b.scheduleDeletion(ctx, keyID); deleteErr != nil {
+			return "", fmt.Errorf("%v (the unused KMS key %s could not be scheduled for deletion: %v)", err, keyID, deleteErr)
+		}
+		return "", err
+	}
+	return keyID, nil
+}
+
+func (b *KMSBackend) importKeyMaterial(ctx context.Context, keyID string, key *ecdsa.PrivateKey) error {
+	params, err := b.client.GetParametersForImport(ctx, &kms.GetParametersForImportInput{
+		KeyId:             aws.String(keyID),
+		WrappingAlgorithm: types.AlgorithmSpecRsaAesKeyWrapSha256,
+		WrappingKeySpec:   types.WrappingKeySpecRsa4096,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading the KMS import parameters: %v", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(params.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid KMS wrapping key: %v", err)
+	}
+	wrappingKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("the KMS wrapping key is not an RSA key")
+	}
+
+	wrapped, err := wrapSecp256k1Key(wrappingKey, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.client.ImportKeyMaterial(ctx, &kms.ImportKeyMaterialInput{
+		KeyId:                aws.String(keyID),
+		ImportToken:          params.ImportToken,
+		EncryptedKeyMaterial: wrapped,
+		ExpirationModel:      types.ExpirationModelTypeKeyMaterialDoesNotExpire,
+	})
+	if err != nil {
+		return fmt.Errorf("error importing the key into KMS: %v", err)
+	}
+	return nil
+}
+
+func (b *KMSBackend) SignDigest(ctx context.Context, ref, password string, digest []byte) ([]byte, error) {
+	pub, err := b.PublicKey(ctx, ref, password)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := b.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(ref),
+		Message:          digest,
+		MessageType:      types.MessageTypeDigest,
+		SigningAlgorithm: types.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing with KMS: %v", err)
+	}
+	return ethSignature(signed.Signature, digest, pub)
+}
+
+func (b *KMSBackend) PublicKey(ctx context.Context, ref, password string) (*ecdsa.PublicKey, error) {
+	b.mu.Lock()
+	cached, ok := b.keys[ref]
+	b.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	response, err := b.client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(ref)})
+	if err != nil {
+		return nil, fmt.Errorf("error reading the KMS public key: %v", err)
+	}
+	if response.KeySpec != types.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("kms key %s is %s, not %s", ref, response.KeySpec, types.KeySpecEccSecgP256k1)
+	}
+	pub, err := parseSecp256k1PublicKey(response.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.keys[ref] = pub
+	b.mu.Unlock()
+	return pub, nil
+}
+
+// DeleteKey schedules the key for deletion; KMS keeps it recoverable for the
+// configured window
+func (b *KMSBackend) DeleteKey(ctx context.Context, ref string) error {
+	if !b.Options.DeleteKeys {
+		return nil
+	}
+	return b.DiscardKey(ctx, ref)
+}
+
+// DiscardKey schedules the key for deletion even when DeleteKeys is off
+func (b *KMSBackend) DiscardKey(ctx context.Context, ref string) error {
+	b.mu.Lock()
+	delete(b.keys, ref)
+	b.mu.Unlock()
+	return b.scheduleDeletion(ctx, ref)
+}
+
+func (b *KMSBackend) scheduleDeletion(ctx context.Context, keyID string) error {
+	input := &kms.ScheduleKeyDeletionInput{KeyId: aws.String(keyID)}
+	if b.Options.DeletionWindow > 0 {
+		input.PendingWindowInDays = aws.Int32(b.Options.DeletionWindow)
+	}
+	_, err := b.client.ScheduleKeyDeletion(ctx, input)
+	var notFound *types.NotFoundException
+	var invalidState *types.KMSInvalidStateException
+	if errors.As(err, &notFound) || errors.As(err, &invalidState) {
+		// Already gone or already pending deletion
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error scheduling the deletion of the KMS key: %v", err)
+	}
+	return nil
+}
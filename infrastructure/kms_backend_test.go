@@ -0,0 +1,346 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// kmsStandIn serves the parts of the AWS KMS JSON API used by KMSBackend,
+// keeping secp256k1 keys in memory
+type kmsStandIn struct {
+	t           *testing.T
+	wrappingKey *rsa.PrivateKey
+
+	mu          sync.Mutex
+	created     int
+	keys        map[string]*ecdsa.PrivateKey // nil while an external key waits for its material
+	deleted     map[string]int32             // Keys scheduled for deletion, with their window
+	importError string                       // Exception returned by ImportKeyMaterial when set
+	deleteError string                       // Exception returned by ScheduleKeyDeletion when set
+}
+
+func newKMSStandIn(t *testing.T) (*kmsStandIn, *httptest.Server) {
+	t.Helper()
+	wrappingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stand := &kmsStandIn{
+		t:           t,
+		wrappingKey: wrappingKey,
+		keys:        map[string]*ecdsa.PrivateKey{},
+		deleted:     map[string]int32{},
+	}
+	server := httptest.NewServer(http.HandlerFunc(stand.serve))
+	t.Cleanup(server.Close)
+	return stand, server
+}
+
+func (s *kmsStandIn) reply(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.t.Error(err)
+	}
+}
+
+func (s *kmsStandIn) fail(w http.ResponseWriter, exception, message string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"__type": exception, "message": message})
+}
+
+// key returns the usable key named by KeyId, failing the request otherwise
+func (s *kmsStandIn) key(w http.ResponseWriter, keyID string) *ecdsa.PrivateKey {
+	key, ok := s.keys[keyID]
+	switch {
+	case !ok:
+		s.fail(w, "NotFoundException", "key "+keyID+" does not exist")
+	case key == nil:
+		s.fail(w, "KMSInvalidStateException", "key "+keyID+" is pending import")
+	case s.deleted[keyID] != 0:
+		s.fail(w, "KMSInvalidStateException", "key "+keyID+" is pending deletion")
+	default:
+		return key
+	}
+	return nil
+}
+
+func (s *kmsStandIn) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var body struct {
+		KeyId                string
+		KeySpec              string
+		KeyUsage             string
+		Origin               string
+		ImportToken          []byte
+		EncryptedKeyMaterial []byte
+		Message              []byte
+		MessageType          string
+		SigningAlgorithm     string
+		WrappingAlgorithm    string
+		PendingWindowInDays  int32
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.fail(w, "ValidationException", err.Error())
+		return
+	}
+
+	switch operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "TrentService."); operation {
+	case "CreateKey":
+		if body.KeySpec != "ECC_SECG_P256K1" || body.KeyUsage != "SIGN_VERIFY" {
+			s.fail(w, "ValidationException", fmt.Sprintf("unsupported key %s/%s", body.KeySpec, body.KeyUsage))
+			return
+		}
+		s.created++
+		keyID := fmt.Sprintf("key-%d", s.created)
+		if body.Origin == "EXTERNAL" {
+			s.keys[keyID] = nil
+		} else {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				s.t.Error(err)
+			}
+			s.keys[keyID] = key
+		}
+		s.reply(w, map[string]interface{}{"KeyMetadata": map[string]string{"KeyId": keyID, "KeySpec": body.KeySpec}})
+
+	case "GetParametersForImport":
+		if key, ok := s.keys[body.KeyId]; !ok || key != nil {
+			s.fail(w, "UnsupportedOperationException", "key "+body.KeyId+" does not take key material")
+			return
+		}
+		if body.WrappingAlgorithm != "RSA_AES_KEY_WRAP_SHA_256" {
+			s.fail(w, "ValidationException", "unsupported wrapping algorithm "+body.WrappingAlgorithm)
+			return
+		}
+		der, err := x509.MarshalPKIXPublicKey(&s.wrappingKey.PublicKey)
+		if err != nil {
+			s.t.Error(err)
+		}
+		s.reply(w, map[string]interface{}{"KeyId": body.KeyId, "ImportToken": []byte("token-" + body.KeyId), "PublicKey": der})
+
+	case "ImportKeyMaterial":
+		if s.importError != "" {
+			s.fail(w, s.importError, "the key material was rejected")
+			return
+		}
+		if string(body.ImportToken) != "token-"+body.KeyId {
+			s.fail(w, "InvalidImportTokenException", "the import token does not belong to "+body.KeyId)
+			return
+		}
+		key, err := unwrapSecp256k1Key(s.wrappingKey, body.EncryptedKeyMaterial)
+		if err != nil {
+			s.fail(w, "IncorrectKeyMaterialException", err.Error())
+			return
+		}
+		s.keys[body.KeyId] = key
+		s.reply(w, map[string]interface{}{})
+
+	case "GetPublicKey":
+		key := s.key(w, body.KeyId)
+		if key == nil {
+			return
+		}
+		encoded, err := marshalSecp256k1PublicKey(&key.PublicKey)
+		if err != nil {
+			s.t.Error(err)
+		}
+		block, _ := pem.Decode(encoded)
+		s.reply(w, map[string]interface{}{"KeyId": body.KeyId, "KeySpec": "ECC_SECG_P256K1", "PublicKey": block.Bytes})
+
+	case "Sign":
+		key := s.key(w, body.KeyId)
+		if key == nil {
+			return
+		}
+		if body.MessageType != "DIGEST" || body.SigningAlgorithm != "ECDSA_SHA_256" || len(body.Message) != 32 {
+			s.fail(w, "ValidationException", "unsupported signing request")
+			return
+		}
+		s.reply(w, map[string]interface{}{"KeyId": body.KeyId, "Signature": highSDER(s.t, key, body.Message)})
+
+	case "ScheduleKeyDeletion":
+		if s.deleteError != "" {
+			s.fail(w, s.deleteError, "deletion refused")
+			return
+		}
+		if _, ok := s.keys[body.KeyId]; !ok {
+			s.fail(w, "NotFoundException", "key "+body.KeyId+" does not exist")
+			return
+		}
+		if s.deleted[body.KeyId] != 0 {
+			s.fail(w, "KMSInvalidStateException", "key "+body.KeyId+" is pending deletion")
+			return
+		}
+		window := body.PendingWindowInDays
+		if window == 0 {
+			window = 30
+		}
+		s.deleted[body.KeyId] = window
+		s.reply(w, map[string]interface{}{"KeyId": body.KeyId, "PendingWindowInDays": window})
+
+	default:
+		s.fail(w, "UnknownOperationException", "unsupported operation "+operation)
+	}
+}
+
+func newTestKMSBackend(t *testing.T, endpoint string) *KMSBackend {
+	t.Helper()
+	// Static credentials, so the SDK neither reads the user's AWS files nor
+	// asks an instance metadata service
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	backend, err := NewKMSBackend(context.Background(), KMSOptions{
+		Region:         "us-east-1",
+		Endpoint:       endpoint,
+		DeleteKeys:     true,
+		DeletionWindow: 7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backend
+}
+
+// checkKMSSignature signs through the backend and checks the signature
+// recovers to the key's address
+func checkKMSSignature(t *testing.T, backend *KMSBackend, ref string, want *ecdsa.PublicKey) {
+	t.Helper()
+	ctx := context.Background()
+	pub, err := backend.PublicKey(ctx, ref, "")
+	if err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+	if want != nil && !bytes.Equal(crypto.FromECDSAPub(pub), crypto.FromECDSAPub(want)) {
+		t.Fatal("the public key read from KMS differs from the imported key")
+	}
+	digest := crypto.Keccak256([]byte(ref))
+	signature, err := backend.SignDigest(ctx, ref, "", digest)
+	if err != nil {
+		t.Fatalf("SignDigest: %v", err)
+	}
+	recovered, err := crypto.SigToPub(digest, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*recovered) != crypto.PubkeyToAddress(*pub) {
+		t.Errorf("signature recovers to %s, want %s", crypto.PubkeyToAddress(*recovered).Hex(), crypto.PubkeyToAddress(*pub).Hex())
+	}
+}
+
+func TestKMSBackendCreateSignAndDelete(t *testing.T) {
+	stand, server := newKMSStandIn(t)
+	backend := newTestKMSBackend(t, server.URL)
+	ctx := context.Background()
+
+	ref, err := backend.CreateKey(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateKey: %v", err)
+	}
+	checkKMSSignature(t, backend, ref, nil)
+
+	if err := backend.DeleteKey(ctx, ref); err != nil {
+		t.Fatalf("DeleteKey: %v", err)
+	}
+	if stand.deleted[ref] != 7 {
+		t.Errorf("deletion window = %d, want the configured 7 days", stand.deleted[ref])
+	}
+	if _, err := backend.PublicKey(ctx, ref, ""); err == nil {
+		t.Error("a key pending deletion still has a public key")
+	}
+	// Keys already pending deletion or gone are not an error
+	if err := backend.DeleteKey(ctx, ref); err != nil {
+		t.Errorf("DeleteKey of a key pending deletion: %v", err)
+	}
+	if err := backend.DeleteKey(ctx, "missing"); err != nil {
+		t.Errorf("DeleteKey of a missing key: %v", err)
+	}
+}
+
+func TestKMSBackendDiscardIgnoresDeleteKeys(t *testing.T) {
+	stand, server := newKMSStandIn(t)
+	backend := newTestKMSBackend(t, server.URL)
+	backend.Options.DeleteKeys = false
+	ctx := context.Background()
+	ref, err := backend.CreateKey(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.DeleteKey(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stand.deleted[ref]; ok {
+		t.Fatal("DeleteKey scheduled a deletion although DeleteKeys is off")
+	}
+	if err := backend.DiscardKey(ctx, ref); err != nil {
+		t.Fatalf("DiscardKey: %v", err)
+	}
+	if _, ok := stand.deleted[ref]; !ok {
+		t.Error("DiscardKey left the key in KMS")
+	}
+}
+
+func TestKMSBackendImport(t *testing.T) {
+	stand, server := newKMSStandIn(t)
+	backend := newTestKMSBackend(t, server.URL)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := backend.ImportKey(context.Background(), key, "")
+	if err != nil {
+		t.Fatalf("ImportKey: %v", err)
+	}
+	if stored := stand.keys[ref]; stored == nil || !bytes.Equal(crypto.FromECDSA(stored), crypto.FromECDSA(key)) {
+		t.Fatal("KMS did not receive the imported key")
+	}
+	checkKMSSignature(t, backend, ref, &key.PublicKey)
+}
+
+func TestKMSBackendAbortedImport(t *testing.T) {
+	stand, server := newKMSStandIn(t)
+	backend := newTestKMSBackend(t, server.URL)
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The key created for the import is scheduled for deletion
+	stand.importError = "ExpiredImportTokenException"
+	_, err = backend.ImportKey(ctx, key, "")
+	if err == nil || !strings.Contains(err.Error(), "ExpiredImportTokenException") {
+		t.Fatalf("got %v, want the import error", err)
+	}
+	if _, ok := stand.deleted["key-1"]; !ok {
+		t.Error("the key left without material was not scheduled for deletion")
+	}
+
+	// When that fails too, the error names the key to remove by hand
+	stand.deleteError = "AccessDeniedException"
+	_, err = backend.ImportKey(ctx, key, "")
+	if err == nil || !strings.Contains(err.Error(), "ExpiredImportTokenException") || !strings.Contains(err.Error(), "key-2") {
+		t.Errorf("got %v, want the import error naming the unused key", err)
+	}
+}
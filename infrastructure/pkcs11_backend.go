@@ -0,0 +1,437 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+)
+
+// PKCS11Options selects the token holding wallet keys. The slot is used when
+// set, otherwise the token with TokenLabel; a lone token needs neither.
+type PKCS11Options struct {
+	Library    string
+	Slot       *uint
+	TokenLabel string
+	PIN        string
+	DeleteKeys bool // When false, deleting a wallet keeps its key on the token
+}
+
+// PKCS11Backend keeps secp256k1 keys on an HSM or token reached through a
+// PKCS#11 module and signs digests with CKM_ECDSA. Keys are referenced by their
+// CKA_ID and labelled with the wallet address. The token is unlocked with the
+// configured PIN; wallet passwords are not used.
+type PKCS11Backend struct {
+	Options PKCS11Options
+
+	module  *pkcs11.Ctx
+	mu      sync.Mutex // PKCS#11 sessions must not be used concurrently
+	session pkcs11.SessionHandle
+	open    bool
+	keys    map[string]*ecdsa.PublicKey // Public keys already read, by key id
+}
+
+var _ domain.KeyBackend = &PKCS11Backend{}
+var _ domain.KeyDiscarder = &PKCS11Backend{}
+
+func NewPKCS11Backend(options PKCS11Options) (*PKCS11Backend, error) {
+	module := pkcs11.New(options.Library)
+	if module == nil {
+		return nil, fmt.Errorf("cannot load the PKCS#11 module %s", options.Library)
+	}
+	if err := module.Initialize(); err != nil && !isPKCS11Error(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		module.Destroy()
+		return nil, fmt.Errorf("error initializing the PKCS#11 module: %v", err)
+	}
+	return &PKCS11Backend{
+		Options: options,
+		module:  module,
+		keys:    map[string]*ecdsa.PublicKey{},
+	}, nil
+}
+
+func (b *PKCS11Backend) Name() string {
+	return "pkcs11"
+}
+
+func (b *PKCS11Backend) CreateKey(ctx context.Context, password string) (string, error) {
+	id, err := newKeyID()
+	if err != nil {
+		return "", err
+	}
+	params, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		return "", err
+	}
+	publicTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+	}
+	privateTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+	}
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)}
+
+	ref := hex.EncodeToString(id)
+	err = b.withSession("generating the key", func(session pkcs11.SessionHandle) error {
+		publicHandle, privateHandle, err := b.module.GenerateKeyPair(session, mechanism, publicTemplate, privateTemplate)
+		if err != nil {
+			return err
+		}
+		pub, err := b.readPublicKey(session, publicHandle)
+		if err != nil {
+			return err
+		}
+		// The address is only known once the key exists, so label it afterwards
+		label := []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyLabel(pub))}
+		for _, handle := range []pkcs11.ObjectHandle{publicHandle, privateHandle} {
+			if err := b.module.SetAttributeValue(session, handle, label); err != nil {
+				return err
+			}
+		}
+		b.keys[ref] = pub
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+// ImportKey stores the private key on the token as a sensitive, non-extractable
+// object, together with its public key
+func (b *PKCS11Backend) ImportKey(ctx context.Context, key *ecdsa.PrivateKey, password string) (string, error) {
+	id, err := newKeyID()
+	if err != nil {
+		return "", err
+	}
+	params, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		return "", err
+	}
+	point, err := asn1.Marshal(crypto.FromECDSAPub(&key.PublicKey))
+	if err != nil {
+		return "", err
+	}
+	label := keyLabel(&key.PublicKey)
+	privateTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, crypto.FromECDSA(key)),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	publicTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, point),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+
+	ref := hex.EncodeToString(id)
+	err = b.withSession("importing the key", func(session pkcs11.SessionHandle) error {
+		privateHandle, err := b.module.CreateObject(session, privateTemplate)
+		if err != nil {
+			return err
+		}
+		if _, err := b.module.CreateObject(session, publicTemplate); err != nil {
+			b.module.DestroyObject(session, privateHandle)
+			return err
+		}
+		b.keys[ref] = &key.PublicKey
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+func (b *PKCS11Backend) SignDigest(ctx context.Context, ref, password string, digest []byte) ([]byte, error) {
+	pub, err := b.PublicKey(ctx, ref, password)
+	if err != nil {
+		return nil, err
+	}
+	id, err := hex.DecodeString(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PKCS#11 key id: %s", ref)
+	}
+
+	var raw []byte
+	err = b.withSession("signing", func(session pkcs11.SessionHandle) error {
+		handle, err := b.findKey(session, pkcs11.CKO_PRIVATE_KEY, id)
+		if err != nil {
+			return err
+		}
+		mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}
+		if err := b.module.SignInit(session, mechanism, handle); err != nil {
+			return err
+		}
+		raw, err = b.module.Sign(session, digest)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// CKM_ECDSA returns R and S concatenated, each as long as the curve order
+	if len(raw) != 64 {
+		return nil, fmt.Errorf("unexpected PKCS#11 signature length %d", len(raw))
+	}
+	return ethSignatureRS(new(big.Int).SetBytes(raw[:32]), new(big.Int).SetBytes(raw[32:]), digest, pub)
+}
+
+func (b *PKCS11Backend) PublicKey(ctx context.Context, ref, password string) (*ecdsa.PublicKey, error) {
+	id, err := hex.DecodeString(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PKCS#11 key id: %s", ref)
+	}
+	b.mu.Lock()
+	cached, ok := b.keys[ref]
+	b.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var pub *ecdsa.PublicKey
+	err = b.withSession("reading the public key", func(session pkcs11.SessionHandle) error {
+		handle, err := b.findKey(session, pkcs11.CKO_PUBLIC_KEY, id)
+		if err != nil {
+			return err
+		}
+		if pub, err = b.readPublicKey(session, handle); err != nil {
+			return err
+		}
+		b.keys[ref] = pub
+		return nil
+	})
+	return pub, err
+}
+
+func (b *PKCS11Backend) DeleteKey(ctx context.Context, ref string) error {
+	if !b.Options.DeleteKeys {
+		return nil
+	}
+	return b.DiscardKey(ctx, ref)
+}
+
+// DiscardKey destroys the key objects even when DeleteKeys is off
+func (b *PKCS11Backend) DiscardKey(ctx context.Context, ref string) error {
+	id, err := hex.DecodeString(ref)
+	if err != nil {
+		return fmt.Errorf("invalid PKCS#11 key id: %s", ref)
+	}
+	return b.withSession("deleting the key", func(session pkcs11.SessionHandle) error {
+		delete(b.keys, ref)
+		handles, err := b.findObjects(session, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, id)})
+		if err != nil {
+			return err
+		}
+		for _, handle := range handles {
+			if err := b.module.DestroyObject(session, handle); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close logs out and unloads the PKCS#11 module
+func (b *PKCS11Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		b.module.Logout(b.session)
+		b.module.CloseSession(b.session)
+		b.open = false
+	}
+	err := b.module.Finalize()
+	b.module.Destroy()
+	return err
+}
+
+// withSession runs fn with a logged-in session, opening one again if the token
+// dropped it (e.g. after being removed and reinserted). fn returns the module's
+// errors unwrapped so lost sessions can be recognized; they are described with
+// action here.
+func (b *PKCS11Backend) withSession(action string, fn func(pkcs11.SessionHandle) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		if !b.open {
+			if err := b.openSession(); err != nil {
+				return err
+			}
+		}
+		err := fn(b.session)
+		if attempt == 0 && isPKCS11Error(err, pkcs11.CKR_SESSION_HANDLE_INVALID, pkcs11.CKR_SESSION_CLOSED,
+			pkcs11.CKR_USER_NOT_LOGGED_IN, pkcs11.CKR_DEVICE_REMOVED, pkcs11.CKR_TOKEN_NOT_PRESENT) {
+			b.module.CloseSession(b.session)
+			b.open = false
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error %s on the token: %v", action, err)
+		}
+		return nil
+	}
+}
+
+func (b *PKCS11Backend) openSession() error {
+	slot, err := b.findSlot()
+	if err != nil {
+		return err
+	}
+	session, err := b.module.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return fmt.Errorf("error opening a PKCS#11 session: %v", err)
+	}
+	if err := b.module.Login(session, pkcs11.CKU_USER, b.Options.PIN); err != nil && !isPKCS11Error(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		b.module.CloseSession(session)
+		return fmt.Errorf("error logging in to the token: %v", err)
+	}
+	b.session = session
+	b.open = true
+	return nil
+}
+
+func (b *PKCS11Backend) findSlot() (uint, error) {
+	slots, err := b.module.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("error listing PKCS#11 slots: %v", err)
+	}
+	if b.Options.Slot != nil {
+		for _, slot := range slots {
+			if slot == *b.Options.Slot {
+				return slot, nil
+			}
+		}
+		return 0, fmt.Errorf("no token present in PKCS#11 slot %d", *b.Options.Slot)
+	}
+	if b.Options.TokenLabel == "" {
+		if len(slots) == 1 {
+			return slots[0], nil
+		}
+		return 0, fmt.Errorf("%d PKCS#11 tokens found; configure a slot or token label", len(slots))
+	}
+	for _, slot := range slots {
+		info, err := b.module.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if strings.TrimRight(info.Label, " \x00") == b.Options.TokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no PKCS#11 token labelled %q", b.Options.TokenLabel)
+}
+
+func (b *PKCS11Backend) findKey(session pkcs11.SessionHandle, class uint, id []byte) (pkcs11.ObjectHandle, error) {
+	handles, err := b.findObjects(session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(handles) == 0 {
+		return 0, fmt.Errorf("key %x not found", id)
+	}
+	return handles[0], nil
+}
+
+func (b *PKCS11Backend) findObjects(session pkcs11.SessionHandle, template []*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+	if err := b.module.FindObjectsInit(session, template); err != nil {
+		return nil, err
+	}
+	defer b.module.FindObjectsFinal(session)
+	var handles []pkcs11.ObjectHandle
+	for {
+		found, _, err := b.module.FindObjects(session, 16)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return handles, nil
+		}
+		handles = append(handles, found...)
+	}
+}
+
+// readPublicKey decodes CKA_EC_POINT, which the standard wraps in a DER octet
+// string but some modules return bare
+func (b *PKCS11Backend) readPublicKey(session pkcs11.SessionHandle, handle pkcs11.ObjectHandle) (*ecdsa.PublicKey, error) {
+	attributes, err := b.module.GetAttributeValue(session, handle, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		return nil, err
+	}
+	if len(attributes) == 0 {
+		return nil, errors.New("public key has no EC point")
+	}
+	point := attributes[0].Value
+	var unwrapped []byte
+	if rest, err := asn1.Unmarshal(point, &unwrapped); err == nil && len(rest) == 0 {
+		point = unwrapped
+	}
+	if len(point) == 33 {
+		return crypto.DecompressPubkey(point)
+	}
+	return crypto.UnmarshalPubkey(point)
+}
+
+func isPKCS11Error(err error, codes ...uint) bool {
+	var p11 pkcs11.Error
+	if !errors.As(err, &p11) {
+		return false
+	}
+	for _, code := range codes {
+		if uint(p11) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// keyLabel names token objects after the wallet address they belong to
+func keyLabel(pub *ecdsa.PublicKey) string {
+	return "bloco-" + strings.ToLower(crypto.PubkeyToAddress(*pub).Hex())
+}
+
+func newKeyID() ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
@@ -0,0 +1,104 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestPKCS11BackendSoftHSM runs the backend against a SoftHSMv2 token. It needs
+// SOFTHSM2_CONF and an initialized token, for example:
+//
+//	softhsm2-util --init-token --free --label bloco-test --pin 1234 --so-pin 1234
+//
+// SOFTHSM2_LIB, SOFTHSM2_TOKEN and SOFTHSM2_PIN override the module path, the
+// token label and the user PIN.
+func TestPKCS11BackendSoftHSM(t *testing.T) {
+	if os.Getenv("SOFTHSM2_CONF") == "" {
+		t.Skip("SOFTHSM2_CONF is not set")
+	}
+	options := PKCS11Options{
+		Library:    envOr("SOFTHSM2_LIB", "/usr/lib/softhsm/libsofthsm2.so"),
+		TokenLabel: envOr("SOFTHSM2_TOKEN", "bloco-test"),
+		PIN:        envOr("SOFTHSM2_PIN", "1234"),
+		DeleteKeys: true,
+	}
+	backend, err := NewPKCS11Backend(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	ctx := context.Background()
+
+	imported, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	importedRef, err := backend.ImportKey(ctx, imported, "")
+	if err != nil {
+		t.Fatalf("ImportKey: %v", err)
+	}
+	defer backend.DeleteKey(ctx, importedRef)
+	createdRef, err := backend.CreateKey(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateKey: %v", err)
+	}
+	defer backend.DeleteKey(ctx, createdRef)
+
+	tests := []struct {
+		name string
+		ref  string
+		want []byte // Expected public key, nil when the token generated it
+	}{
+		{name: "generated", ref: createdRef},
+		{name: "imported", ref: importedRef, want: crypto.FromECDSAPub(&imported.PublicKey)},
+	}
+	for _, tt := range tests {
+		// Drop the cached public key so it is read back from the token
+		backend.mu.Lock()
+		delete(backend.keys, tt.ref)
+		backend.mu.Unlock()
+
+		pub, err := backend.PublicKey(ctx, tt.ref, "")
+		if err != nil {
+			t.Errorf("%s: PublicKey: %v", tt.name, err)
+			continue
+		}
+		if tt.want != nil && !bytes.Equal(crypto.FromECDSAPub(pub), tt.want) {
+			t.Errorf("%s: public key read from the token differs from the imported key", tt.name)
+		}
+
+		digest := crypto.Keccak256([]byte("bloco " + tt.name))
+		signature, err := backend.SignDigest(ctx, tt.ref, "", digest)
+		if err != nil {
+			t.Errorf("%s: SignDigest: %v", tt.name, err)
+			continue
+		}
+		recovered, err := crypto.SigToPub(digest, signature)
+		if err != nil {
+			t.Errorf("%s: SigToPub: %v", tt.name, err)
+			continue
+		}
+		if crypto.PubkeyToAddress(*recovered) != crypto.PubkeyToAddress(*pub) {
+			t.Errorf("%s: signature recovers to %s, want %s", tt.name,
+				crypto.PubkeyToAddress(*recovered).Hex(), crypto.PubkeyToAddress(*pub).Hex())
+		}
+	}
+
+	if err := backend.DeleteKey(ctx, importedRef); err != nil {
+		t.Fatalf("DeleteKey: %v", err)
+	}
+	if _, err := backend.PublicKey(ctx, importedRef, ""); err == nil {
+		t.Error("the deleted key is still on the token")
+	}
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
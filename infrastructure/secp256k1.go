@@ -0,0 +1,192 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// The standard library only knows the NIST curves, so secp256k1 keys exchanged
+// with remote backends are encoded and decoded with these structures
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type pkcs8PrivateKey struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+type ecPrivateKey struct {
+	Version    int
+	PrivateKey []byte
+	PublicKey  asn1.BitString `asn1:"optional,explicit,tag:1"`
+}
+
+type derSignature struct {
+	R, S *big.Int
+}
+
+// parseSecp256k1PublicKey decodes a SubjectPublicKeyInfo given as DER, PEM or
+// base64-encoded DER
+func parseSecp256k1PublicKey(encoded []byte) (*ecdsa.PublicKey, error) {
+	der := encoded
+	if block, _ := pem.Decode(encoded); block != nil {
+		der = block.Bytes
+	} else if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded))); err == nil {
+		der = decoded
+	}
+
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &curve); err != nil ||
+		!info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) || !curve.Equal(oidSecp256k1) {
+		return nil, errors.New("public key is not a secp256k1 key")
+	}
+	point := info.PublicKey.RightAlign()
+	if len(point) == 33 {
+		return crypto.DecompressPubkey(point)
+	}
+	return crypto.UnmarshalPubkey(point)
+}
+
+// marshalSecp256k1PKCS8 encodes key as an unencrypted PKCS #8 PrivateKeyInfo
+func marshalSecp256k1PKCS8(key *ecdsa.PrivateKey) ([]byte, error) {
+	curve, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	public := crypto.FromECDSAPub(&key.PublicKey)
+	inner, err := asn1.Marshal(ecPrivateKey{
+		Version:    1,
+		PrivateKey: crypto.FromECDSA(key),
+		PublicKey:  asn1.BitString{Bytes: public, BitLength: len(public) * 8},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8PrivateKey{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: curve},
+		},
+		PrivateKey: inner,
+	})
+}
+
+// ethSignature turns a DER signature over digest into the 65-byte [R || S || V]
+// form. S is moved to the lower half of the curve order, as Ethereum requires,
+// and V is the recovery id that yields the expected public key.
+func ethSignature(der, digest []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	var parsed derSignature
+	rest, err := asn1.Unmarshal(der, &parsed)
+	if err != nil || len(rest) > 0 || parsed.R == nil || parsed.S == nil {
+		return nil, errors.New("invalid DER signature")
+	}
+	return ethSignatureRS(parsed.R, parsed.S, digest, pub)
+}
+
+// ethSignatureRS is ethSignature for backends that return R and S directly
+func ethSignatureRS(r, s *big.Int, digest []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	n := crypto.S256().Params().N
+	if r.Sign() <= 0 || r.Cmp(n) >= 0 || s.Sign() <= 0 || s.Cmp(n) >= 0 {
+		return nil, errors.New("signature values out of range")
+	}
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s = new(big.Int).Sub(n, s)
+	}
+
+	signature := make([]byte, crypto.SignatureLength)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:64])
+	expected := crypto.FromECDSAPub(pub)
+	for v := byte(0); v < 2; v++ {
+		signature[64] = v
+		recovered, err := crypto.Ecrecover(digest, signature)
+		if err == nil && bytes.Equal(recovered, expected) {
+			return signature, nil
+		}
+	}
+	return nil, errors.New("signature does not recover to the key's public key")
+}
+
+// wrapSecp256k1Key wraps key for import into a remote backend with the
+// RSA_AES_KEY_WRAP_SHA_256 scheme shared by Vault and AWS KMS: the PKCS #8 key is
+// wrapped with a one-time AES key (RFC 5649), which is itself encrypted with the
+// backend's RSA wrapping key using OAEP and SHA-256
+func wrapSecp256k1Key(wrappingKey *rsa.PublicKey, key *ecdsa.PrivateKey) ([]byte, error) {
+	pkcs8, err := marshalSecp256k1PKCS8(key)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, ephemeral); err != nil {
+		return nil, err
+	}
+	wrappedEphemeral, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, wrappingKey, ephemeral, nil)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := aesKeyWrapPad(ephemeral, pkcs8)
+	if err != nil {
+		return nil, err
+	}
+	return append(wrappedEphemeral, wrappedKey...), nil
+}
+
+// aesKeyWrapPad implements AES key wrap with padding (RFC 5649), used to wrap
+// keys for import
+func aesKeyWrapPad(kek, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	var a [8]byte
+	copy(a[:4], []byte{0xA6, 0x59, 0x59, 0xA6})
+	binary.BigEndian.PutUint32(a[4:], uint32(len(plaintext)))
+	padded := make([]byte, (len(plaintext)+7)/8*8)
+	copy(padded, plaintext)
+
+	if len(padded) == 8 {
+		out := make([]byte, 16)
+		block.Encrypt(out, append(a[:], padded...))
+		return out, nil
+	}
+
+	n := len(padded) / 8
+	buf := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(buf[:8], a[:])
+			copy(buf[8:], padded[(i-1)*8:i*8])
+			block.Encrypt(buf, buf)
+			binary.BigEndian.PutUint64(a[:], binary.BigEndian.Uint64(buf[:8])^uint64(n*j+i))
+			copy(padded[(i-1)*8:i*8], buf[8:])
+		}
+	}
+	return append(a[:], padded...), nil
+}
@@ -0,0 +1,258 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestEthSignature(t *testing.T) {
+	key, err := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := crypto.S256().Params().N
+
+	// Sign a few digests until both recovery ids have been seen
+	seen := map[byte]bool{}
+	for i := 0; len(seen) < 2 && i < 64; i++ {
+		digest := crypto.Keccak256([]byte{byte(i)})
+		want, err := crypto.Sign(digest, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := want[64]
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		r := new(big.Int).SetBytes(want[:32])
+		s := new(big.Int).SetBytes(want[32:64])
+		der := func(r, s *big.Int) []byte {
+			out, err := asn1.Marshal(derSignature{R: r, S: s})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return out
+		}
+
+		tests := []struct {
+			name    string
+			der     []byte
+			wantErr bool
+		}{
+			{name: "low s", der: der(r, s)},
+			{name: "high s", der: der(r, new(big.Int).Sub(n, s))},
+			{name: "trailing data", der: append(der(r, s), 0), wantErr: true},
+			{name: "not DER", der: []byte{0x30, 0x01}, wantErr: true},
+			{name: "zero s", der: der(r, big.NewInt(0)), wantErr: true},
+			{name: "s equal to n", der: der(r, n), wantErr: true},
+		}
+		for _, tt := range tests {
+			got, err := ethSignature(tt.der, digest, &key.PublicKey)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("v=%d %s: expected an error", v, tt.name)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("v=%d %s: %v", v, tt.name, err)
+				continue
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("v=%d %s: got %x, want %x", v, tt.name, got, want)
+			}
+		}
+
+		if _, err := ethSignature(der(r, s), digest, &other.PublicKey); err == nil {
+			t.Errorf("v=%d: a signature by another key was accepted", v)
+		}
+	}
+	if len(seen) < 2 {
+		t.Fatalf("only saw recovery ids %v", seen)
+	}
+}
+
+// Vectors from RFC 5649, section 6
+func TestAESKeyWrapPad(t *testing.T) {
+	kek := mustHex(t, "5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8")
+	tests := []struct {
+		name      string
+		plaintext string
+		want      string
+	}{
+		{
+			name:      "20 octets",
+			plaintext: "c37b7e6492584340bed12207808941155068f738",
+			want:      "138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6a",
+		},
+		{
+			name:      "7 octets",
+			plaintext: "466f7250617369",
+			want:      "afbeb0f07dfbf5419200f2ccb50bb24f",
+		},
+	}
+	for _, tt := range tests {
+		got, err := aesKeyWrapPad(kek, mustHex(t, tt.plaintext))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("%s: got %x, want %s", tt.name, got, tt.want)
+		}
+		if plaintext, err := aesKeyUnwrapPad(kek, got); err != nil || hex.EncodeToString(plaintext) != tt.plaintext {
+			t.Errorf("%s: unwrap gave %x, %v", tt.name, plaintext, err)
+		}
+	}
+}
+
+// aesKeyUnwrapPad reverses aesKeyWrapPad, as the backends do on import
+func aesKeyUnwrapPad(kek, wrapped []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < 16 || len(wrapped)%8 != 0 {
+		return nil, errors.New("invalid wrapped key length")
+	}
+	var a [8]byte
+	padded := make([]byte, len(wrapped)-8)
+	if len(wrapped) == 16 {
+		buf := make([]byte, 16)
+		block.Decrypt(buf, wrapped)
+		copy(a[:], buf[:8])
+		copy(padded, buf[8:])
+	} else {
+		copy(a[:], wrapped[:8])
+		copy(padded, wrapped[8:])
+		n := len(padded) / 8
+		buf := make([]byte, 16)
+		for j := 5; j >= 0; j-- {
+			for i := n; i >= 1; i-- {
+				binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(a[:])^uint64(n*j+i))
+				copy(buf[8:], padded[(i-1)*8:i*8])
+				block.Decrypt(buf, buf)
+				copy(a[:], buf[:8])
+				copy(padded[(i-1)*8:i*8], buf[8:])
+			}
+		}
+	}
+	if !bytes.Equal(a[:4], []byte{0xA6, 0x59, 0x59, 0xA6}) {
+		return nil, errors.New("integrity check failed")
+	}
+	length := int(binary.BigEndian.Uint32(a[4:]))
+	if length > len(padded) || len(padded)-length >= 8 {
+		return nil, errors.New("invalid message length")
+	}
+	return padded[:length], nil
+}
+
+// unwrapSecp256k1Key reverses wrapSecp256k1Key with the private wrapping key
+func unwrapSecp256k1Key(wrappingKey *rsa.PrivateKey, wrapped []byte) (*ecdsa.PrivateKey, error) {
+	size := wrappingKey.Size()
+	if len(wrapped) <= size {
+		return nil, errors.New("wrapped key is too short")
+	}
+	ephemeral, err := rsa.DecryptOAEP(sha256.New(), nil, wrappingKey, wrapped[:size], nil)
+	if err != nil {
+		return nil, err
+	}
+	pkcs8, err := aesKeyUnwrapPad(ephemeral, wrapped[size:])
+	if err != nil {
+		return nil, err
+	}
+	var info pkcs8PrivateKey
+	if _, err := asn1.Unmarshal(pkcs8, &info); err != nil {
+		return nil, err
+	}
+	var inner ecPrivateKey
+	if _, err := asn1.Unmarshal(info.PrivateKey, &inner); err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(inner.PrivateKey)
+}
+
+// marshalSecp256k1PublicKey encodes pub as a PEM SubjectPublicKeyInfo, as the
+// backends return it
+func marshalSecp256k1PublicKey(pub *ecdsa.PublicKey) ([]byte, error) {
+	curve, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	point := crypto.FromECDSAPub(pub)
+	der, err := asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: curve}},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// highSDER signs digest and returns the DER signature with S in the upper half
+// of the curve order, as HSMs and Vault may return it
+func highSDER(t *testing.T, key *ecdsa.PrivateKey, digest []byte) []byte {
+	t.Helper()
+	signature, err := crypto.Sign(digest, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(signature[32:64]))
+	der, err := asn1.Marshal(derSignature{R: new(big.Int).SetBytes(signature[:32]), S: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestWrapSecp256k1KeyRoundTrip(t *testing.T) {
+	wrappingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := wrapSecp256k1Key(&wrappingKey.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwrapped, err := unwrapSecp256k1Key(wrappingKey, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(crypto.FromECDSA(unwrapped), crypto.FromECDSA(key)) {
+		t.Error("the unwrapped key differs from the wrapped one")
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	out, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
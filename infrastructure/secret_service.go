@@ -0,0 +1,55 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"errors"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// SecretServiceStore keeps wallet passwords in the freedesktop Secret Service
+// (GNOME Keyring, KWallet) over D-Bus, where they are unlocked with the desktop
+// session. On macOS and Windows the native keychain is used instead.
+type SecretServiceStore struct {
+	Service string // Collection attribute grouping the application's secrets
+}
+
+var _ domain.SecretStore = &SecretServiceStore{}
+
+func NewSecretServiceStore(service string) *SecretServiceStore {
+	return &SecretServiceStore{Service: service}
+}
+
+// Available looks up a probe item: a daemon that answers reports it as missing,
+// while a missing daemon or session bus fails with another error
+func (s *SecretServiceStore) Available() bool {
+	_, err := keyring.Get(s.Service, "availability-probe")
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+func (s *SecretServiceStore) GetSecret(address string) (string, error) {
+	secret, err := keyring.Get(s.Service, secretUser(address))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", domain.ErrSecretNotFound
+	}
+	return secret, err
+}
+
+func (s *SecretServiceStore) SetSecret(address, secret string) error {
+	return keyring.Set(s.Service, secretUser(address), secret)
+}
+
+func (s *SecretServiceStore) DeleteSecret(address string) error {
+	err := keyring.Delete(s.Service, secretUser(address))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// secretUser normalizes the checksum casing so lookups match whichever form of
+// the address was stored
+func secretUser(address string) string {
+	return strings.ToLower(address)
+}
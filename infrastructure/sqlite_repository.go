@@ -3,16 +3,46 @@ package infrastructure
 import (
 	"blocowallet/domain"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 type SQLiteRepository struct {
 	conn *sql.DB
+
+	mu     sync.RWMutex
+	cipher domain.FieldCipher // Metadata encryption, nil while the vault is locked
+}
+
+// encryptedPrefix marks metadata values sealed with the vault cipher
+const encryptedPrefix = "enc1:"
+
+// encryptedColumns lists the metadata fields kept encrypted once a master
+// password is set: mnemonics, the labels and ABIs of saved contracts and ledger
+// notes. Addresses stay in plaintext since lookups and filters match on them.
+var encryptedColumns = []struct{ table, column string }{
+	{"wallets", "mnemonic"},
+	{"contracts", "name"},
+	{"contracts", "abi"},
+	{"ledger", "memo"},
 }
 
 // Implement the WalletRepository interface from entities package
 var _ domain.WalletRepository = &SQLiteRepository{}
+var _ domain.HistoryRepository = &SQLiteRepository{}
+var _ domain.LedgerRepository = &SQLiteRepository{}
+var _ domain.ContractRepository = &SQLiteRepository{}
+var _ domain.SelectorRepository = &SQLiteRepository{}
+var _ domain.VaultRepository = &SQLiteRepository{}
+var _ domain.TwoFactorRepository = &SQLiteRepository{}
+var _ domain.UnlockRepository = &SQLiteRepository{}
 
 func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 	conn, err := sql.Open("sqlite3", dbPath)
@@ -25,7 +55,97 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		address TEXT UNIQUE NOT NULL,
 		keystore_path TEXT NOT NULL,
-		mnemonic TEXT NOT NULL
+		mnemonic TEXT NOT NULL,
+		backend TEXT NOT NULL DEFAULT 'keystore',
+		keyring INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS history_cache (
+		chain_id INTEGER NOT NULL,
+		address TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		page INTEGER NOT NULL,
+		page_size INTEGER NOT NULL,
+		entries TEXT NOT NULL,
+		fetched_at INTEGER NOT NULL,
+		PRIMARY KEY (chain_id, address, kind, page, page_size)
+	);
+
+	CREATE TABLE IF NOT EXISTS rpc_cache (
+		chain_id INTEGER NOT NULL,
+		request_key TEXT NOT NULL,
+		result BLOB NOT NULL,
+		cached_at INTEGER NOT NULL,
+		PRIMARY KEY (chain_id, request_key)
+	);
+
+	CREATE TABLE IF NOT EXISTS ledger (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		hash TEXT UNIQUE NOT NULL,
+		chain_id INTEGER NOT NULL,
+		from_address TEXT NOT NULL,
+		to_address TEXT NOT NULL,
+		value TEXT NOT NULL,
+		fee TEXT NOT NULL,
+		timestamp INTEGER NOT NULL,
+		memo TEXT NOT NULL,
+		status TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS contracts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		chain_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		address TEXT NOT NULL,
+		abi TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		UNIQUE (chain_id, address)
+	);
+
+	CREATE TABLE IF NOT EXISTS selectors (
+		selector TEXT NOT NULL,
+		signature TEXT NOT NULL,
+		PRIMARY KEY (selector, signature)
+	);
+
+	CREATE TABLE IF NOT EXISTS vault (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		kdf TEXT NOT NULL,
+		salt BLOB NOT NULL,
+		time INTEGER NOT NULL,
+		memory INTEGER NOT NULL,
+		threads INTEGER NOT NULL,
+		password_key BLOB NOT NULL,
+		recovery_key BLOB NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS two_factor (
+		wallet_id INTEGER PRIMARY KEY,
+		secret TEXT NOT NULL,
+		recovery_codes TEXT NOT NULL,
+		reveal_keys INTEGER NOT NULL,
+		export_ledger INTEGER NOT NULL,
+		delete_wallet INTEGER NOT NULL,
+		sign_threshold TEXT NOT NULL,
+		last_step INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS unlock_attempts (
+		wallet_id INTEGER NOT NULL,
+		secret TEXT NOT NULL,
+		failures INTEGER NOT NULL,
+		retry_at INTEGER NOT NULL,
+		locked_out INTEGER NOT NULL,
+		PRIMARY KEY (wallet_id, secret)
+	);
+
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp INTEGER NOT NULL,
+		wallet_id INTEGER NOT NULL,
+		address TEXT NOT NULL,
+		event TEXT NOT NULL,
+		detail TEXT NOT NULL
 	);
 	`
 	_, err = conn.Exec(createTableQuery)
@@ -33,21 +153,76 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 		return nil, err
 	}
 
+	// Colunas adicionadas depois da criação das tabelas em bancos existentes
+	if err := addColumnIfMissing(conn, "wallets", "backend", "TEXT NOT NULL DEFAULT 'keystore'"); err != nil {
+		return nil, err
+	}
+	if err := addColumnIfMissing(conn, "wallets", "keyring", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return nil, err
+	}
+
 	return &SQLiteRepository{conn: conn}, nil
 }
 
+// addColumnIfMissing migrates databases created by older versions
+func addColumnIfMissing(conn *sql.DB, table, column, definition string) error {
+	rows, err := conn.Query(fmt.Sprintf("PRAGMA table_info(%s);", table))
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, column, definition))
+	return err
+}
+
 func (repo *SQLiteRepository) AddWallet(wallet *domain.Wallet) error {
 	insertQuery := `
-	INSERT INTO wallets (address, keystore_path, mnemonic)
-	VALUES (?, ?, ?);
+	INSERT INTO wallets (address, keystore_path, mnemonic, backend)
+	VALUES (?, ?, ?, ?);
 	`
-	_, err := repo.conn.Exec(insertQuery, wallet.Address, wallet.KeyStorePath, wallet.Mnemonic)
-	return err
+	backend := wallet.Backend
+	if backend == "" {
+		backend = domain.DefaultKeyBackend
+	}
+	mnemonic, err := repo.sealField(wallet.Mnemonic)
+	if err != nil {
+		return err
+	}
+	result, err := repo.conn.Exec(insertQuery, wallet.Address, wallet.KeyRef, mnemonic, backend)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	wallet.ID = int(id)
+	return nil
 }
 
 func (repo *SQLiteRepository) GetAllWallets() ([]domain.Wallet, error) {
 	selectQuery := `
-	SELECT id, address, keystore_path, mnemonic FROM wallets;
+	SELECT id, address, keystore_path, mnemonic, backend, keyring FROM wallets;
 	`
 	rows, err := repo.conn.Query(selectQuery)
 	if err != nil {
@@ -63,10 +238,13 @@ func (repo *SQLiteRepository) GetAllWallets() ([]domain.Wallet, error) {
 	var wallets []domain.Wallet
 	for rows.Next() {
 		var w domain.Wallet
-		err := rows.Scan(&w.ID, &w.Address, &w.KeyStorePath, &w.Mnemonic)
+		err := rows.Scan(&w.ID, &w.Address, &w.KeyRef, &w.Mnemonic, &w.Backend, &w.Keyring)
 		if err != nil {
 			return nil, err
 		}
+		if w.Mnemonic, err = repo.openField(w.Mnemonic); err != nil {
+			return nil, err
+		}
 		wallets = append(wallets, w)
 	}
 
@@ -75,10 +253,549 @@ func (repo *SQLiteRepository) GetAllWallets() ([]domain.Wallet, error) {
 
 func (repo *SQLiteRepository) DeleteWallet(walletID int) error {
 	deleteQuery := `DELETE FROM wallets WHERE id = ?;`
-	_, err := repo.conn.Exec(deleteQuery, walletID)
+	if _, err := repo.conn.Exec(deleteQuery, walletID); err != nil {
+		return err
+	}
+	if _, err := repo.conn.Exec(`DELETE FROM unlock_attempts WHERE wallet_id = ?;`, walletID); err != nil {
+		return err
+	}
+	return repo.DeleteTwoFactor(walletID)
+}
+
+func (repo *SQLiteRepository) SetWalletKeyring(walletID int, enabled bool) error {
+	updateQuery := `UPDATE wallets SET keyring = ? WHERE id = ?;`
+	_, err := repo.conn.Exec(updateQuery, enabled, walletID)
+	return err
+}
+
+func (repo *SQLiteRepository) GetHistoryPage(chainID int64, address string, kind domain.HistoryKind, page, pageSize int) ([]domain.HistoryEntry, time.Time, error) {
+	selectQuery := `
+	SELECT entries, fetched_at FROM history_cache
+	WHERE chain_id = ? AND address = ? AND kind = ? AND page = ? AND page_size = ?;
+	`
+	var payload string
+	var fetchedAt int64
+	err := repo.conn.QueryRow(selectQuery, chainID, address, string(kind), page, pageSize).Scan(&payload, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var entries []domain.HistoryEntry
+	if err := json.Unmarshal([]byte(payload), &entries); err != nil {
+		return nil, time.Time{}, err
+	}
+	return entries, time.Unix(fetchedAt, 0), nil
+}
+
+func (repo *SQLiteRepository) SaveHistoryPage(chainID int64, address string, kind domain.HistoryKind, page, pageSize int, entries []domain.HistoryEntry) error {
+	payload, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	upsertQuery := `
+	INSERT OR REPLACE INTO history_cache (chain_id, address, kind, page, page_size, entries, fetched_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?);
+	`
+	_, err = repo.conn.Exec(upsertQuery, chainID, address, string(kind), page, pageSize, string(payload), time.Now().Unix())
+	return err
+}
+
+func (repo *SQLiteRepository) GetRPCResult(chainID int64, key string) ([]byte, bool, error) {
+	selectQuery := `SELECT result FROM rpc_cache WHERE chain_id = ? AND request_key = ?;`
+	var result []byte
+	err := repo.conn.QueryRow(selectQuery, chainID, key).Scan(&result)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return result, true, nil
+}
+
+func (repo *SQLiteRepository) SaveRPCResult(chainID int64, key string, result []byte) error {
+	upsertQuery := `
+	INSERT OR REPLACE INTO rpc_cache (chain_id, request_key, result, cached_at)
+	VALUES (?, ?, ?, ?);
+	`
+	_, err := repo.conn.Exec(upsertQuery, chainID, key, result, time.Now().Unix())
+	return err
+}
+
+// AddLedgerEntry records a signed transaction. Signing is deterministic, so the
+// same transaction signed again keeps its entry, with its status and time, and
+// only takes the new memo when one is given.
+func (repo *SQLiteRepository) AddLedgerEntry(entry *domain.LedgerEntry) error {
+	upsertQuery := `
+	INSERT INTO ledger (hash, chain_id, from_address, to_address, value, fee, timestamp, memo, status)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (hash) DO UPDATE SET memo = CASE WHEN excluded.memo = '' THEN memo ELSE excluded.memo END
+	RETURNING id, timestamp, status;
+	`
+	memo, err := repo.sealField(entry.Memo)
+	if err != nil {
+		return err
+	}
+	var timestamp int64
+	var status string
+	err = repo.conn.QueryRow(upsertQuery, entry.Hash, entry.ChainID, entry.From, entry.To, entry.Value,
+		entry.Fee, entry.Timestamp.Unix(), memo, string(entry.Status)).Scan(&entry.ID, &timestamp, &status)
+	if err != nil {
+		return err
+	}
+	entry.Timestamp = time.Unix(timestamp, 0)
+	entry.Status = domain.LedgerStatus(status)
+	return nil
+}
+
+func (repo *SQLiteRepository) UpdateLedgerStatus(hash string, status domain.LedgerStatus, fee string) error {
+	updateQuery := `UPDATE ledger SET status = ?, fee = COALESCE(NULLIF(?, ''), fee) WHERE hash = ?;`
+	_, err := repo.conn.Exec(updateQuery, string(status), fee, hash)
+	return err
+}
+
+func (repo *SQLiteRepository) GetLedgerEntries(filter domain.LedgerFilter) ([]domain.LedgerEntry, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Address != "" {
+		conditions = append(conditions, "(LOWER(from_address) = LOWER(?) OR LOWER(to_address) = LOWER(?))")
+		args = append(args, filter.Address, filter.Address)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.Since.Unix())
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, filter.Until.Unix())
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, string(filter.Status))
+	}
+
+	selectQuery := `SELECT id, hash, chain_id, from_address, to_address, value, fee, timestamp, memo, status FROM ledger`
+	if len(conditions) > 0 {
+		selectQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	selectQuery += " ORDER BY timestamp DESC, id DESC;"
+
+	rows, err := repo.conn.Query(selectQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(rows)
+
+	var entries []domain.LedgerEntry
+	for rows.Next() {
+		var e domain.LedgerEntry
+		var timestamp int64
+		var status string
+		err := rows.Scan(&e.ID, &e.Hash, &e.ChainID, &e.From, &e.To, &e.Value, &e.Fee, &timestamp, &e.Memo, &status)
+		if err != nil {
+			return nil, err
+		}
+		if e.Memo, err = repo.openField(e.Memo); err != nil {
+			return nil, err
+		}
+		e.Timestamp = time.Unix(timestamp, 0)
+		e.Status = domain.LedgerStatus(status)
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (repo *SQLiteRepository) SaveContract(contract *domain.SavedContract) error {
+	if contract.CreatedAt.IsZero() {
+		contract.CreatedAt = time.Now()
+	}
+	upsertQuery := `
+	INSERT INTO contracts (chain_id, name, address, abi, created_at)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (chain_id, address) DO UPDATE SET name = excluded.name, abi = excluded.abi;
+	`
+	name, err := repo.sealField(contract.Name)
+	if err != nil {
+		return err
+	}
+	contractABI, err := repo.sealField(contract.ABI)
+	if err != nil {
+		return err
+	}
+	_, err = repo.conn.Exec(upsertQuery, contract.ChainID, name, strings.ToLower(contract.Address), contractABI, contract.CreatedAt.Unix())
+	if err != nil {
+		return err
+	}
+	return repo.conn.QueryRow(`SELECT id FROM contracts WHERE chain_id = ? AND address = ?;`,
+		contract.ChainID, strings.ToLower(contract.Address)).Scan(&contract.ID)
+}
+
+func (repo *SQLiteRepository) GetContracts(chainID int64) ([]domain.SavedContract, error) {
+	selectQuery := `
+	SELECT id, chain_id, name, address, abi, created_at FROM contracts
+	WHERE chain_id = ? ORDER BY id;
+	`
+	rows, err := repo.conn.Query(selectQuery, chainID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(rows)
+
+	var contracts []domain.SavedContract
+	for rows.Next() {
+		var contract domain.SavedContract
+		var createdAt int64
+		if err := rows.Scan(&contract.ID, &contract.ChainID, &contract.Name, &contract.Address, &contract.ABI, &createdAt); err != nil {
+			return nil, err
+		}
+		name, err := repo.openField(contract.Name)
+		if err != nil {
+			return nil, err
+		}
+		contract.Name = name
+		if contract.ABI, err = repo.openField(contract.ABI); err != nil {
+			return nil, err
+		}
+		contract.CreatedAt = time.Unix(createdAt, 0)
+		contracts = append(contracts, contract)
+	}
+
+	// Sorted after decrypting, since names may be sealed in the database
+	sort.SliceStable(contracts, func(i, j int) bool {
+		return contracts[i].Name < contracts[j].Name
+	})
+	return contracts, nil
+}
+
+func (repo *SQLiteRepository) DeleteContract(id int) error {
+	_, err := repo.conn.Exec(`DELETE FROM contracts WHERE id = ?;`, id)
+	return err
+}
+
+func (repo *SQLiteRepository) GetSignatures(selector string) ([]string, error) {
+	rows, err := repo.conn.Query(`SELECT signature FROM selectors WHERE selector = ? ORDER BY signature;`, strings.ToLower(selector))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(rows)
+
+	var signatures []string
+	for rows.Next() {
+		var signature string
+		if err := rows.Scan(&signature); err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, signature)
+	}
+
+	return signatures, nil
+}
+
+// AddSignatures imports selector signatures in a single transaction, ignoring duplicates
+func (repo *SQLiteRepository) AddSignatures(signatures map[string][]string) error {
+	tx, err := repo.conn.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO selectors (selector, signature) VALUES (?, ?);`)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	for selector, list := range signatures {
+		for _, signature := range list {
+			if _, err := stmt.Exec(strings.ToLower(selector), signature); err != nil {
+				_ = stmt.Close()
+				_ = tx.Rollback()
+				return err
+			}
+		}
+	}
+	if err := stmt.Close(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *SQLiteRepository) GetVault() (*domain.VaultRecord, error) {
+	var record domain.VaultRecord
+	err := repo.conn.QueryRow(`SELECT kdf, salt, time, memory, threads, password_key, recovery_key FROM vault WHERE id = 1;`).
+		Scan(&record.KDF, &record.Salt, &record.Time, &record.Memory, &record.Threads, &record.PasswordKey, &record.RecoveryKey)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (repo *SQLiteRepository) SaveVault(record *domain.VaultRecord) error {
+	upsertQuery := `
+	INSERT INTO vault (id, kdf, salt, time, memory, threads, password_key, recovery_key)
+	VALUES (1, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (id) DO UPDATE SET kdf = excluded.kdf, salt = excluded.salt, time = excluded.time,
+		memory = excluded.memory, threads = excluded.threads,
+		password_key = excluded.password_key, recovery_key = excluded.recovery_key;
+	`
+	_, err := repo.conn.Exec(upsertQuery, record.KDF, record.Salt, record.Time, record.Memory, record.Threads,
+		record.PasswordKey, record.RecoveryKey)
+	return err
+}
+
+func (repo *SQLiteRepository) GetTwoFactor(walletID int) (*domain.TwoFactor, error) {
+	selectQuery := `
+	SELECT secret, recovery_codes, reveal_keys, export_ledger, delete_wallet, sign_threshold, last_step
+	FROM two_factor WHERE wallet_id = ?;
+	`
+	tf := domain.TwoFactor{WalletID: walletID}
+	var secret, recoveryCodes, threshold string
+	err := repo.conn.QueryRow(selectQuery, walletID).Scan(&secret, &recoveryCodes, &tf.Policy.Reveal,
+		&tf.Policy.Export, &tf.Policy.Delete, &threshold, &tf.LastStep)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.Secret, err = repo.openField(secret); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(recoveryCodes), &tf.RecoveryCodes); err != nil {
+		return nil, fmt.Errorf("invalid recovery codes for wallet %d: %v", walletID, err)
+	}
+	if threshold != "" {
+		value, ok := new(big.Int).SetString(threshold, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid signing threshold for wallet %d: %s", walletID, threshold)
+		}
+		tf.Policy.SignThreshold = value
+	}
+	return &tf, nil
+}
+
+// SaveTwoFactor refuses to run while the vault is locked, so the TOTP secret
+// never reaches the database in plaintext
+func (repo *SQLiteRepository) SaveTwoFactor(tf *domain.TwoFactor) error {
+	repo.mu.RLock()
+	locked := repo.cipher == nil
+	repo.mu.RUnlock()
+	if locked {
+		return domain.ErrVaultLocked
+	}
+	secret, err := repo.sealField(tf.Secret)
+	if err != nil {
+		return err
+	}
+	recoveryCodes, err := json.Marshal(tf.RecoveryCodes)
+	if err != nil {
+		return err
+	}
+	threshold := ""
+	if tf.Policy.SignThreshold != nil {
+		threshold = tf.Policy.SignThreshold.String()
+	}
+	upsertQuery := `
+	INSERT INTO two_factor (wallet_id, secret, recovery_codes, reveal_keys, export_ledger, delete_wallet, sign_threshold, last_step)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (wallet_id) DO UPDATE SET secret = excluded.secret, recovery_codes = excluded.recovery_codes,
+		reveal_keys = excluded.reveal_keys, export_ledger = excluded.export_ledger, delete_wallet = excluded.delete_wallet,
+		sign_threshold = excluded.sign_threshold, last_step = excluded.last_step;
+	`
+	_, err = repo.conn.Exec(upsertQuery, tf.WalletID, secret, string(recoveryCodes), tf.Policy.Reveal,
+		tf.Policy.Export, tf.Policy.Delete, threshold, tf.LastStep)
+	return err
+}
+
+func (repo *SQLiteRepository) DeleteTwoFactor(walletID int) error {
+	_, err := repo.conn.Exec(`DELETE FROM two_factor WHERE wallet_id = ?;`, walletID)
+	return err
+}
+
+func (repo *SQLiteRepository) GetUnlockAttempts(walletID int, secret string) (*domain.UnlockAttempts, error) {
+	attempts := domain.UnlockAttempts{WalletID: walletID, Secret: secret}
+	var retryAt int64
+	selectQuery := `SELECT failures, retry_at, locked_out FROM unlock_attempts WHERE wallet_id = ? AND secret = ?;`
+	err := repo.conn.QueryRow(selectQuery, walletID, secret).
+		Scan(&attempts.Failures, &retryAt, &attempts.LockedOut)
+	if err == sql.ErrNoRows {
+		return &attempts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	attempts.RetryAt = time.Unix(retryAt, 0)
+	return &attempts, nil
+}
+
+func (repo *SQLiteRepository) SaveUnlockAttempts(attempts *domain.UnlockAttempts) error {
+	upsertQuery := `
+	INSERT INTO unlock_attempts (wallet_id, secret, failures, retry_at, locked_out)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (wallet_id, secret) DO UPDATE SET failures = excluded.failures, retry_at = excluded.retry_at,
+		locked_out = excluded.locked_out;
+	`
+	// Rounded up, so the wait never ends early
+	retryAt := attempts.RetryAt.Unix()
+	if attempts.RetryAt.Nanosecond() > 0 {
+		retryAt++
+	}
+	_, err := repo.conn.Exec(upsertQuery, attempts.WalletID, attempts.Secret, attempts.Failures, retryAt, attempts.LockedOut)
 	return err
 }
 
+func (repo *SQLiteRepository) ResetUnlockAttempts(walletID int, secret string) error {
+	_, err := repo.conn.Exec(`DELETE FROM unlock_attempts WHERE wallet_id = ? AND secret = ?;`, walletID, secret)
+	return err
+}
+
+func (repo *SQLiteRepository) AddAuditEntry(entry *domain.AuditEntry) error {
+	insertQuery := `
+	INSERT INTO audit_log (timestamp, wallet_id, address, event, detail)
+	VALUES (?, ?, ?, ?, ?);
+	`
+	result, err := repo.conn.Exec(insertQuery, entry.Time.Unix(), entry.WalletID, entry.Address, entry.Event, entry.Detail)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.ID = int(id)
+	return nil
+}
+
+func (repo *SQLiteRepository) SetFieldCipher(cipher domain.FieldCipher) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.cipher = cipher
+}
+
+// EncryptMetadata seals the plaintext metadata left by older versions or
+// written before the master password was set, in a single transaction
+func (repo *SQLiteRepository) EncryptMetadata() error {
+	repo.mu.RLock()
+	cipher := repo.cipher
+	repo.mu.RUnlock()
+	if cipher == nil {
+		return domain.ErrVaultLocked
+	}
+
+	tx, err := repo.conn.Begin()
+	if err != nil {
+		return err
+	}
+	for _, field := range encryptedColumns {
+		plaintext, err := plaintextFields(tx, field.table, field.column)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		updateQuery := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?;", field.table, field.column)
+		for id, value := range plaintext {
+			sealed, err := cipher.Encrypt(value)
+			if err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+			if _, err := tx.Exec(updateQuery, encryptedPrefix+sealed, id); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func plaintextFields(tx *sql.Tx, table, column string) (map[int]string, error) {
+	selectQuery := fmt.Sprintf("SELECT id, %s FROM %s WHERE %s != '' AND %s NOT LIKE '%s%%';",
+		column, table, column, column, encryptedPrefix)
+	rows, err := tx.Query(selectQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(rows)
+
+	values := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var value string
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, err
+		}
+		values[id] = value
+	}
+	return values, rows.Err()
+}
+
+// sealField encrypts a metadata value when the vault is unlocked; without a
+// master password values are stored as they are, and with a locked one they are
+// refused rather than written in plaintext
+func (repo *SQLiteRepository) sealField(value string) (string, error) {
+	repo.mu.RLock()
+	cipher := repo.cipher
+	repo.mu.RUnlock()
+	if value == "" {
+		return value, nil
+	}
+	if cipher == nil {
+		record, err := repo.GetVault()
+		if err != nil {
+			return "", err
+		}
+		if record != nil {
+			return "", domain.ErrVaultLocked
+		}
+		return value, nil
+	}
+	sealed, err := cipher.Encrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return encryptedPrefix + sealed, nil
+}
+
+// openField decrypts a metadata value; sealed values read as empty while the
+// vault is locked
+func (repo *SQLiteRepository) openField(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	repo.mu.RLock()
+	cipher := repo.cipher
+	repo.mu.RUnlock()
+	if cipher == nil {
+		return "", nil
+	}
+	return cipher.Decrypt(strings.TrimPrefix(value, encryptedPrefix))
+}
+
 func (repo *SQLiteRepository) Close() error {
 	return repo.conn.Close()
 }
@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// base64Cipher is a reversible domain.FieldCipher that keeps values unreadable
+// to a plain text search
+type base64Cipher struct{}
+
+func (base64Cipher) Encrypt(plaintext string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(plaintext)), nil
+}
+
+func (base64Cipher) Decrypt(ciphertext string) (string, error) {
+	plaintext, err := base64.StdEncoding.DecodeString(ciphertext)
+	return string(plaintext), err
+}
+
+func TestEncryptMetadataSealsEveryUserColumn(t *testing.T) {
+	repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "wallets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	// Metadata written before a master password was set
+	const contractABI = `[{"type":"function","name":"ping","inputs":[],"outputs":[]}]`
+	wallet := &domain.Wallet{Address: "0x00000000000000000000000000000000000ca5e5", Backend: "keystore", KeyRef: "key", Mnemonic: "secret words"}
+	if err := repo.AddWallet(wallet); err != nil {
+		t.Fatal(err)
+	}
+	contract := &domain.SavedContract{ChainID: 1, Name: "Treasury", Address: "0x00000000000000000000000000000000000b10c0", ABI: contractABI}
+	if err := repo.SaveContract(contract); err != nil {
+		t.Fatal(err)
+	}
+	entry := &domain.LedgerEntry{Hash: "0x01", ChainID: 1, From: wallet.Address, To: contract.Address,
+		Value: "1", Fee: "1", Timestamp: time.Now(), Memo: "rent", Status: domain.LedgerSigned}
+	if err := repo.AddLedgerEntry(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	repo.SetFieldCipher(base64Cipher{})
+	if err := repo.EncryptMetadata(); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range encryptedColumns {
+		var value string
+		query := fmt.Sprintf("SELECT %s FROM %s;", field.column, field.table)
+		if err := repo.conn.QueryRow(query).Scan(&value); err != nil {
+			t.Fatalf("%s.%s: %v", field.table, field.column, err)
+		}
+		if !strings.HasPrefix(value, encryptedPrefix) {
+			t.Errorf("%s.%s is stored in plaintext: %q", field.table, field.column, value)
+		}
+	}
+
+	contracts, err := repo.GetContracts(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contracts) != 1 || contracts[0].Name != "Treasury" || contracts[0].ABI != contractABI {
+		t.Errorf("got %+v, want the saved contract decrypted", contracts)
+	}
+
+	// A locked vault hides the sealed values instead of failing
+	repo.SetFieldCipher(nil)
+	contracts, err = repo.GetContracts(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contracts) != 1 || contracts[0].ABI != "" {
+		t.Errorf("got %+v, want the ABI hidden while locked", contracts)
+	}
+}
@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// StaticPriceProvider reads fixed quotes from a YAML file, useful offline or
+// for accounting snapshots. The file modification time is reported as the
+// update time of every price.
+//
+//	currency: usd
+//	prices:
+//	  ETH: 3150.25
+//	  USDC: 1
+type StaticPriceProvider struct {
+	Path string
+}
+
+var _ domain.PriceProvider = &StaticPriceProvider{}
+
+type staticPrices struct {
+	Currency string             `yaml:"currency"`
+	Prices   map[string]float64 `yaml:"prices"`
+}
+
+func NewStaticPriceProvider(path string) *StaticPriceProvider {
+	return &StaticPriceProvider{Path: path}
+}
+
+func (p *StaticPriceProvider) GetPrices(_ context.Context, assets []domain.Asset, currency string) (map[string]domain.Price, error) {
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the prices file: %v", err)
+	}
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the prices file: %v", err)
+	}
+	var file staticPrices
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error decoding the prices file: %v", err)
+	}
+	if file.Currency != "" && !strings.EqualFold(file.Currency, currency) {
+		return nil, fmt.Errorf("prices file is quoted in %s, expected %s", file.Currency, currency)
+	}
+
+	bySymbol := map[string]float64{}
+	for symbol, value := range file.Prices {
+		bySymbol[strings.ToUpper(symbol)] = value
+	}
+
+	prices := map[string]domain.Price{}
+	for _, asset := range assets {
+		if value, ok := bySymbol[strings.ToUpper(asset.Symbol)]; ok {
+			prices[asset.ID()] = domain.Price{Value: value, UpdatedAt: info.ModTime()}
+		}
+	}
+	return prices, nil
+}
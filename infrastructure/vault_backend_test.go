@@ -0,0 +1,391 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// transitStandIn serves the parts of Vault's Transit and AppRole APIs used by
+// VaultBackend, keeping secp256k1 keys in memory
+type transitStandIn struct {
+	t           *testing.T
+	wrappingKey *rsa.PrivateKey
+
+	mu          sync.Mutex
+	keys        map[string]*ecdsa.PrivateKey
+	tokens      map[string]bool
+	logins      int
+	loginStatus int    // Status of AppRole logins, 0 for success
+	signature   string // Replaces the signature returned by sign when set
+}
+
+func newTransitStandIn(t *testing.T) (*transitStandIn, *httptest.Server) {
+	t.Helper()
+	wrappingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stand := &transitStandIn{
+		t:           t,
+		wrappingKey: wrappingKey,
+		keys:        map[string]*ecdsa.PrivateKey{},
+		tokens:      map[string]bool{"static-token": true},
+	}
+	server := httptest.NewServer(http.HandlerFunc(stand.serve))
+	t.Cleanup(server.Close)
+	return stand, server
+}
+
+// revokeTokens makes every issued token fail with 403, as when a lease ends early
+func (s *transitStandIn) revokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]bool{}
+}
+
+func (s *transitStandIn) reply(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			s.t.Error(err)
+		}
+	}
+}
+
+func (s *transitStandIn) fail(w http.ResponseWriter, status int, message string) {
+	s.reply(w, status, map[string]interface{}{"errors": []string{message}})
+}
+
+func (s *transitStandIn) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var body map[string]interface{}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		s.logins++
+		if s.loginStatus != 0 {
+			s.fail(w, s.loginStatus, "invalid role or secret ID")
+			return
+		}
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			s.fail(w, http.StatusBadRequest, "invalid role or secret ID")
+			return
+		}
+		token := fmt.Sprintf("approle-token-%d", s.logins)
+		s.tokens[token] = true
+		s.reply(w, http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{"client_token": token, "lease_duration": 3600}})
+		return
+	}
+	if !s.tokens[r.Header.Get("X-Vault-Token")] {
+		s.fail(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/transit/")
+	switch {
+	case r.Method == http.MethodGet && path == "wrapping_key":
+		der, err := x509.MarshalPKIXPublicKey(&s.wrappingKey.PublicKey)
+		if err != nil {
+			s.t.Error(err)
+		}
+		encoded := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+		s.reply(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"public_key": string(encoded)}})
+
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "keys/") && strings.HasSuffix(path, "/import"):
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "keys/"), "/import")
+		wrapped, err := base64.StdEncoding.DecodeString(fmt.Sprint(body["ciphertext"]))
+		if err != nil {
+			s.fail(w, http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		key, err := unwrapSecp256k1Key(s.wrappingKey, wrapped)
+		if err != nil {
+			s.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.keys[name] = key
+		s.reply(w, http.StatusNoContent, nil)
+
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "keys/") && strings.HasSuffix(path, "/config"):
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "keys/"), "/config")
+		if s.keys[name] == nil {
+			s.fail(w, http.StatusNotFound, "no such key")
+			return
+		}
+		s.reply(w, http.StatusNoContent, nil)
+
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "keys/"):
+		if body["type"] != "ecdsa-secp256k1" {
+			s.fail(w, http.StatusBadRequest, fmt.Sprintf("unknown key type %v", body["type"]))
+			return
+		}
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			s.t.Error(err)
+		}
+		s.keys[strings.TrimPrefix(path, "keys/")] = key
+		s.reply(w, http.StatusNoContent, nil)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "keys/"):
+		key := s.keys[strings.TrimPrefix(path, "keys/")]
+		if key == nil {
+			s.fail(w, http.StatusNotFound, "no such key")
+			return
+		}
+		encoded, err := marshalSecp256k1PublicKey(&key.PublicKey)
+		if err != nil {
+			s.t.Error(err)
+		}
+		keys := map[string]interface{}{"1": map[string]string{"public_key": string(encoded)}}
+		s.reply(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "keys/"):
+		name := strings.TrimPrefix(path, "keys/")
+		if s.keys[name] == nil {
+			s.fail(w, http.StatusNotFound, "no such key")
+			return
+		}
+		delete(s.keys, name)
+		s.reply(w, http.StatusNoContent, nil)
+
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "sign/"):
+		key := s.keys[strings.TrimPrefix(path, "sign/")]
+		if key == nil {
+			s.fail(w, http.StatusNotFound, "no such key")
+			return
+		}
+		digest, err := base64.StdEncoding.DecodeString(fmt.Sprint(body["input"]))
+		if err != nil || body["prehashed"] != true {
+			s.fail(w, http.StatusBadRequest, "invalid input")
+			return
+		}
+		signature := s.signature
+		if signature == "" {
+			signature = "vault:v1:" + base64.StdEncoding.EncodeToString(highSDER(s.t, key, digest))
+		}
+		s.reply(w, http.StatusOK, map[string]interface{}{"data": map[string]string{"signature": signature}})
+
+	default:
+		s.fail(w, http.StatusNotFound, "unsupported path "+r.URL.Path)
+	}
+}
+
+func newTestVaultBackend(address string, appRole bool) *VaultBackend {
+	options := VaultOptions{
+		Address:      address,
+		Mount:        "transit",
+		AppRoleMount: "approle",
+		KeyType:      "ecdsa-secp256k1",
+		DeleteKeys:   true,
+	}
+	if appRole {
+		options.RoleID, options.SecretID = "role", "secret"
+	} else {
+		options.Token = "static-token"
+	}
+	return NewVaultBackend(options)
+}
+
+// checkVaultSignature signs through the backend and checks the signature
+// recovers to the key's address
+func checkVaultSignature(t *testing.T, backend *VaultBackend, ref string, want *ecdsa.PublicKey) {
+	t.Helper()
+	ctx := context.Background()
+	pub, err := backend.PublicKey(ctx, ref, "")
+	if err != nil {
+		t.Fatalf("PublicKey: %v", err)
+	}
+	if want != nil && !bytes.Equal(crypto.FromECDSAPub(pub), crypto.FromECDSAPub(want)) {
+		t.Fatal("the public key read from Vault differs from the imported key")
+	}
+	digest := crypto.Keccak256([]byte(ref))
+	signature, err := backend.SignDigest(ctx, ref, "", digest)
+	if err != nil {
+		t.Fatalf("SignDigest: %v", err)
+	}
+	recovered, err := crypto.SigToPub(digest, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*recovered) != crypto.PubkeyToAddress(*pub) {
+		t.Errorf("signature recovers to %s, want %s", crypto.PubkeyToAddress(*recovered).Hex(), crypto.PubkeyToAddress(*pub).Hex())
+	}
+}
+
+func TestVaultBackendCreateSignAndDelete(t *testing.T) {
+	stand, server := newTransitStandIn(t)
+	backend := newTestVaultBackend(server.URL, false)
+	ctx := context.Background()
+
+	ref, err := backend.CreateKey(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateKey: %v", err)
+	}
+	checkVaultSignature(t, backend, ref, nil)
+
+	if err := backend.DeleteKey(ctx, ref); err != nil {
+		t.Fatalf("DeleteKey: %v", err)
+	}
+	if len(stand.keys) != 0 {
+		t.Error("the key is still in Vault")
+	}
+	if _, err := backend.PublicKey(ctx, ref, ""); err == nil {
+		t.Error("a deleted key still has a public key")
+	}
+	// Deleting a key that is already gone is not an error
+	if err := backend.DeleteKey(ctx, ref); err != nil {
+		t.Errorf("DeleteKey of a missing key: %v", err)
+	}
+}
+
+func TestVaultBackendDiscardIgnoresDeleteKeys(t *testing.T) {
+	stand, server := newTransitStandIn(t)
+	backend := newTestVaultBackend(server.URL, false)
+	backend.Options.DeleteKeys = false
+	ctx := context.Background()
+	ref, err := backend.CreateKey(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.DeleteKey(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+	if stand.keys[ref] == nil {
+		t.Fatal("DeleteKey removed a key although DeleteKeys is off")
+	}
+	if err := backend.DiscardKey(ctx, ref); err != nil {
+		t.Fatalf("DiscardKey: %v", err)
+	}
+	if stand.keys[ref] != nil {
+		t.Error("DiscardKey left the key in Vault")
+	}
+}
+
+func TestVaultBackendImport(t *testing.T) {
+	stand, server := newTransitStandIn(t)
+	backend := newTestVaultBackend(server.URL, false)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := backend.ImportKey(context.Background(), key, "")
+	if err != nil {
+		t.Fatalf("ImportKey: %v", err)
+	}
+	if want := "bloco-" + strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex()[2:]); ref != want {
+		t.Errorf("key name = %s, want %s", ref, want)
+	}
+	if stored := stand.keys[ref]; stored == nil || !bytes.Equal(crypto.FromECDSA(stored), crypto.FromECDSA(key)) {
+		t.Fatal("Vault did not receive the imported key")
+	}
+	checkVaultSignature(t, backend, ref, &key.PublicKey)
+}
+
+func TestVaultBackendSignatureParsing(t *testing.T) {
+	stand, server := newTransitStandIn(t)
+	backend := newTestVaultBackend(server.URL, false)
+	ctx := context.Background()
+	ref, err := backend.CreateKey(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := crypto.Keccak256([]byte("parse"))
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   string
+	}{
+		{name: "not base64", signature: "vault:v1:not base64!", wantErr: "invalid Vault signature"},
+		{name: "not DER", signature: "vault:v1:" + base64.StdEncoding.EncodeToString([]byte{0x30, 0x01}), wantErr: "invalid DER signature"},
+		{name: "another key", signature: "vault:v1:" + base64.StdEncoding.EncodeToString(highSDER(t, other, digest)), wantErr: "does not recover"},
+	}
+	for _, tt := range tests {
+		stand.mu.Lock()
+		stand.signature = tt.signature
+		stand.mu.Unlock()
+		_, err := backend.SignDigest(ctx, ref, "", digest)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestVaultBackendAppRoleRelogin(t *testing.T) {
+	stand, server := newTransitStandIn(t)
+	backend := newTestVaultBackend(server.URL, true)
+	ctx := context.Background()
+
+	ref, err := backend.CreateKey(ctx, "")
+	if err != nil {
+		t.Fatalf("CreateKey: %v", err)
+	}
+	if stand.logins != 1 {
+		t.Fatalf("logins = %d, want 1", stand.logins)
+	}
+
+	// A rejected token is replaced by a new login and the request retried
+	stand.revokeTokens()
+	backend.mu.Lock()
+	delete(backend.keys, ref)
+	backend.mu.Unlock()
+	checkVaultSignature(t, backend, ref, nil)
+	if stand.logins != 2 {
+		t.Errorf("logins = %d, want 2 after the token was rejected", stand.logins)
+	}
+
+	// A failed login is reported instead of retrying with the rejected token
+	stand.revokeTokens()
+	stand.mu.Lock()
+	stand.loginStatus = http.StatusBadRequest
+	stand.mu.Unlock()
+	backend.mu.Lock()
+	delete(backend.keys, ref)
+	backend.mu.Unlock()
+	_, err = backend.PublicKey(ctx, ref, "")
+	if err == nil || !strings.Contains(err.Error(), "AppRole login failed") {
+		t.Errorf("got %v, want the failed login", err)
+	}
+	if stand.logins != 3 {
+		t.Errorf("logins = %d, want 3", stand.logins)
+	}
+}
+
+func TestVaultBackendStaticTokenRejected(t *testing.T) {
+	stand, server := newTransitStandIn(t)
+	backend := newTestVaultBackend(server.URL, false)
+	stand.revokeTokens()
+
+	_, err := backend.CreateKey(context.Background(), "")
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("got %v, want the permission error", err)
+	}
+	if stand.logins != 0 {
+		t.Errorf("a static token triggered %d AppRole login(s)", stand.logins)
+	}
+}
@@ -0,0 +1,321 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type approvalsScannedMsg struct {
+	items []usecases.TokenApproval
+	err   error
+}
+
+type revokesBuiltMsg struct {
+	txs     []*types.Transaction
+	indexes []int // Posição em approvalItems de cada transação
+	err     error
+}
+
+type revokeResult struct {
+	index int
+	hash  string
+	err   error
+}
+
+type revokesSentMsg struct {
+	results []revokeResult
+}
+
+func scanApprovalsCmd(service *usecases.ApprovalService, owner common.Address) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		items, err := service.Scan(ctx, owner)
+		return approvalsScannedMsg{items: items, err: err}
+	}
+}
+
+func buildRevokesCmd(transactions *usecases.TransactionService, from common.Address, requests []usecases.TxRequest, indexes []int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		txs, err := transactions.BuildBatch(ctx, from, requests)
+		return revokesBuiltMsg{txs: txs, indexes: indexes, err: err}
+	}
+}
+
+// sendRevokesCmd assina e transmite as revogações em ordem de nonce.
+// A primeira falha interrompe o lote, pois os nonces seguintes ficariam pendentes.
+func sendRevokesCmd(transactions *usecases.TransactionService, details *usecases.WalletDetails, txs []*types.Transaction, indexes []int, memos []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		var results []revokeResult
+		for i, tx := range txs {
+			signed, err := transactions.SignTransaction(ctx, details, tx, memos[i])
+			if err == nil {
+				err = transactions.SendTransaction(ctx, signed)
+			}
+			result := revokeResult{index: indexes[i], err: err}
+			if err == nil {
+				result.hash = signed.Hash().Hex()
+			}
+			results = append(results, result)
+			if err != nil {
+				break
+			}
+		}
+		return revokesSentMsg{results: results}
+	}
+}
+
+// initApprovals abre a auditoria de aprovações da wallet desbloqueada
+func (m *CLIModel) initApprovals() tea.Cmd {
+	m.approvalItems = nil
+	m.approvalSelected = map[int]bool{}
+	m.approvalStatus = map[int]string{}
+	m.approvalBatch = nil
+	m.approvalErr = nil
+	m.approvalMessage = ""
+	m.approvalBusy = false
+	m.approvalLoading = true
+	m.approvalTable = newDataTable(m.approvalColumns(), nil, m.tableHeight())
+	m.currentView = constants.ApprovalsView
+	return scanApprovalsCmd(m.Approvals, common.HexToAddress(m.walletDetails.Wallet.Address))
+}
+
+// approvalsBack cancela a confirmação do lote ou volta para os detalhes da wallet
+func (m *CLIModel) approvalsBack() {
+	if m.approvalBatch != nil && !m.approvalBusy {
+		m.approvalBatch = nil
+		m.approvalMessage = ""
+		return
+	}
+	m.currentView = constants.WalletDetailsView
+}
+
+func (m *CLIModel) approvalColumns() []table.Column {
+	return []table.Column{
+		{Title: "", Width: 3},
+		{Title: localization.Labels["approval_token"], Width: 16},
+		{Title: localization.Labels["approval_spender"], Width: 14},
+		{Title: localization.Labels["approval_kind"], Width: 10},
+		{Title: localization.Labels["approval_allowance"], Width: 24},
+		{Title: localization.Labels["approval_risk"], Width: 10},
+		{Title: localization.Labels["approval_status"], Width: 20},
+	}
+}
+
+func (m *CLIModel) refreshApprovalRows() {
+	rows := make([]table.Row, 0, len(m.approvalItems))
+	for i, item := range m.approvalItems {
+		selected := "[ ]"
+		if m.approvalSelected[i] {
+			selected = "[x]"
+		}
+		token := item.Symbol
+		if token == "" {
+			token = shortHex(item.Token)
+		}
+		allowance := "-"
+		if item.Kind == usecases.ApprovalOperator {
+			allowance = localization.Labels["approval_all_tokens"]
+		} else if item.Risky() {
+			allowance = localization.Labels["approval_unlimited"]
+		} else if item.Allowance != nil {
+			allowance = usecases.FormatUnits(item.Allowance, item.Decimals)
+		}
+		risk := localization.Labels["approval_limited"]
+		if item.Risky() {
+			risk = localization.Labels["approval_high"]
+		}
+		rows = append(rows, table.Row{selected, token, shortHex(item.Spender), string(item.Kind), allowance, risk, m.approvalStatus[i]})
+	}
+	m.approvalTable.SetRows(rows)
+}
+
+func (m *CLIModel) applyApprovalsMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case approvalsScannedMsg:
+		m.approvalLoading = false
+		m.approvalErr = msg.err
+		if msg.err != nil {
+			log.Println("Erro ao buscar as aprovações:", msg.err)
+			return nil
+		}
+		m.approvalItems = msg.items
+		m.approvalSelected = map[int]bool{}
+		m.approvalStatus = map[int]string{}
+		m.refreshApprovalRows()
+		m.approvalTable.GotoTop()
+	case revokesBuiltMsg:
+		m.approvalBusy = false
+		if msg.err != nil {
+			log.Println("Erro ao construir as revogações:", msg.err)
+			m.approvalMessage = fmt.Sprintf(localization.Labels["tx_error"], msg.err)
+			return nil
+		}
+		m.approvalBatch = msg.txs
+		m.approvalBatchIndexes = msg.indexes
+		m.approvalMessage = ""
+	case revokesSentMsg:
+		m.approvalBusy = false
+		m.approvalBatch = nil
+		sent := 0
+		for _, result := range msg.results {
+			if result.err != nil {
+				log.Println("Erro ao revogar a aprovação:", result.err)
+				m.approvalStatus[result.index] = localization.Labels["approval_failed"]
+				m.approvalMessage = fmt.Sprintf(localization.Labels["tx_error"], result.err)
+				continue
+			}
+			sent++
+			m.approvalStatus[result.index] = shortHex(result.hash)
+			delete(m.approvalSelected, result.index)
+		}
+		if m.approvalMessage == "" {
+			m.approvalMessage = fmt.Sprintf(localization.Labels["approval_revokes_sent"], sent)
+		}
+		m.refreshApprovalRows()
+	}
+	return nil
+}
+
+func (m *CLIModel) updateApprovals(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && m.approvalBusy {
+		return m, nil
+	}
+
+	// Confirmação do lote de revogações
+	if ok && m.approvalBatch != nil {
+		switch keyMsg.String() {
+		case "enter", "s":
+			memos := make([]string, len(m.approvalBatch))
+			for i, index := range m.approvalBatchIndexes {
+				item := m.approvalItems[index]
+				memos[i] = fmt.Sprintf("revoke %s → %s", item.Token, item.Spender)
+			}
+			m.approvalBusy = true
+			m.approvalMessage = localization.Labels["approval_revoking"]
+			return m, sendRevokesCmd(m.Transactions, m.walletDetails, m.approvalBatch, m.approvalBatchIndexes, memos)
+		}
+		return m, nil
+	}
+
+	if ok {
+		switch keyMsg.String() {
+		case "r":
+			return m, m.initApprovals()
+		case " ":
+			cursor := m.approvalTable.Cursor()
+			if cursor < len(m.approvalItems) {
+				m.approvalSelected[cursor] = !m.approvalSelected[cursor]
+				if !m.approvalSelected[cursor] {
+					delete(m.approvalSelected, cursor)
+				}
+				m.refreshApprovalRows()
+			}
+			return m, nil
+		case "A":
+			for i, item := range m.approvalItems {
+				if item.Risky() {
+					m.approvalSelected[i] = true
+				}
+			}
+			m.refreshApprovalRows()
+			return m, nil
+		case "v":
+			return m, m.buildRevokes()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.approvalTable, cmd = m.approvalTable.Update(msg)
+	return m, cmd
+}
+
+// buildRevokes prepara uma transação de revogação por aprovação selecionada
+func (m *CLIModel) buildRevokes() tea.Cmd {
+	var requests []usecases.TxRequest
+	var indexes []int
+	for i, item := range m.approvalItems {
+		if !m.approvalSelected[i] {
+			continue
+		}
+		request, err := m.Approvals.RevokeRequest(item)
+		if err != nil {
+			m.approvalMessage = fmt.Sprintf(localization.Labels["tx_error"], err)
+			return nil
+		}
+		requests = append(requests, request)
+		indexes = append(indexes, i)
+	}
+	if len(requests) == 0 {
+		m.approvalMessage = localization.Labels["approval_none_selected"]
+		return nil
+	}
+	m.approvalBusy = true
+	m.approvalMessage = localization.Labels["tx_building"]
+	return buildRevokesCmd(m.Transactions, common.HexToAddress(m.walletDetails.Wallet.Address), requests, indexes)
+}
+
+// viewApprovals renderiza a tabela de aprovações ativas e a confirmação do lote
+func (m *CLIModel) viewApprovals() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Render(fmt.Sprintf(localization.Labels["approval_title"], m.walletDetails.Wallet.Address))
+
+	var body string
+	switch {
+	case m.approvalLoading:
+		body = localization.Labels["approval_loading"]
+	case m.approvalErr != nil:
+		body = m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["approval_error"], m.approvalErr))
+	case len(m.approvalItems) == 0:
+		body = localization.Labels["approval_empty"]
+	default:
+		body = m.approvalTable.View()
+	}
+	parts := []string{title, "", body}
+
+	if m.approvalBatch != nil {
+		parts = append(parts, "", m.viewRevokeBatch())
+	}
+	if m.approvalMessage != "" {
+		parts = append(parts, "", m.approvalMessage)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (m *CLIModel) viewRevokeBatch() string {
+	var view strings.Builder
+	view.WriteString(fmt.Sprintf(localization.Labels["approval_confirm_title"], len(m.approvalBatch)) + "\n")
+	total := new(big.Int)
+	for i, tx := range m.approvalBatch {
+		item := m.approvalItems[m.approvalBatchIndexes[i]]
+		call := fmt.Sprintf("approve(%s, 0)", shortHex(item.Spender))
+		if item.Kind == usecases.ApprovalOperator {
+			call = fmt.Sprintf("setApprovalForAll(%s, false)", shortHex(item.Spender))
+		}
+		maxFee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+		total.Add(total, maxFee)
+		view.WriteString(fmt.Sprintf("  #%d %s %s\n", tx.Nonce(), shortHex(item.Token), call))
+	}
+	view.WriteString(fmt.Sprintf(localization.Labels["approval_confirm_fee"], usecases.FormatUnits(total, constants.NativeDecimals), constants.DefaultNativeSymbol) + "\n")
+	view.WriteString(localization.Labels["approval_confirm_prompt"])
+	return view.String()
+}
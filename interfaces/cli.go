@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"blocowallet/constants"
+	"blocowallet/domain"
 	"blocowallet/localization"
 	"blocowallet/usecases"
 	"bytes"
@@ -251,9 +252,16 @@ func selectRandomFont(fonts []string) (string, error) {
 }
 
 func (m *CLIModel) Init() tea.Cmd {
+	// Com a senha mestra pendente, nada do banco é carregado antes do desbloqueio
+	if m.vaultLocked() {
+		return tea.Batch(splashCmd(), m.refreshPortfolio(), m.watchRPC(), m.watchSession())
+	}
 	return tea.Batch(
 		splashCmd(),
 		walletCountCmd(m.Service),
+		m.refreshPortfolio(),
+		m.watchRPC(),
+		m.watchSession(),
 	)
 }
 
@@ -268,8 +276,16 @@ func (m *CLIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Uma tecla após o tempo de inatividade apenas bloqueia; as demais adiam o bloqueio
+	if _, ok := msg.(tea.KeyMsg); ok && m.Service.Session != nil {
+		if m.checkSession() {
+			return m, nil
+		}
+		m.Service.Session.Touch()
+	}
+
 	// Tratar as teclas de navegação global (esc/backspace) antes de qualquer outro processamento
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !m.editingText() {
 		switch keyMsg.String() {
 		case "esc", "backspace":
 			// Se estiver na tela de lista de wallets e tiver um diálogo de exclusão aberto,
@@ -282,9 +298,27 @@ func (m *CLIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Comportamento específico para tela de detalhes: voltar para lista de wallets
 					m.walletDetails = nil
 					m.currentView = constants.ListWalletsView
+				} else if m.currentView == constants.HistoryView {
+					// Histórico também pertence à lista de wallets
+					m.historyWallet = nil
+					m.currentView = constants.ListWalletsView
+				} else if m.currentView == constants.NFTView {
+					m.currentView = constants.WalletDetailsView
+				} else if m.currentView == constants.TxReviewView {
+					m.closeTxReview()
+				} else if m.currentView == constants.ContractsView {
+					m.contractBack()
+				} else if m.currentView == constants.ApprovalsView {
+					m.approvalsBack()
+				} else if m.currentView == constants.DiscoveryView {
+					m.discoveryBack()
+				} else if m.currentView == constants.SweepView {
+					m.sweepBack()
+				} else if m.currentView == constants.PaymentsView {
+					m.paymentsBack()
 				} else {
 					// Comportamento padrão: voltar ao menu principal
-					m.menuItems = NewMenu()
+					m.menuItems = m.mainMenu()
 					m.selectedMenu = 0
 					m.currentView = constants.DefaultView
 				}
@@ -316,11 +350,46 @@ func (m *CLIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case walletsRefreshedMsg:
 		// Apenas retornar o modelo sem fazer nada, pois a atualização já foi feita
 		// Isso evita que a tela inteira seja redesenhada
+		var cmds []tea.Cmd
+		if m.Portfolio != nil {
+			// Reavaliar o portfólio com a nova lista de wallets
+			cmds = append(cmds, fetchPortfolioCmd(m.Service, m.Portfolio))
+		}
+		cmds = append(cmds, m.lookupWalletNames())
+		return m, tea.Batch(cmds...)
+
+	case devBlockMinedMsg:
+		return m, m.applyDevBlock(msg)
+
+	case walletNamesMsg:
+		m.applyWalletNames(msg)
+		return m, nil
+
+	case recipientResolvedMsg:
+		return m, m.applyRecipientResolved(msg)
+
+	case portfolioMsg:
+		if msg.err != nil {
+			log.Println("Erro ao avaliar o portfólio:", msg.err)
+		}
+		m.applyPortfolio(msg)
 		return m, nil
+	case rpcStatusTickMsg:
+		return m, rpcStatusTickCmd()
+	case sessionTickMsg:
+		m.checkSession()
+		return m, sessionTickCmd()
+	case portfolioTickMsg:
+		return m, m.refreshPortfolio()
 
 	case splashMsg:
+		if m.vaultLocked() {
+			m.initVault()
+			return m, nil
+		}
 		// Transitar para o menu principal após a splash screen
 		m.currentView = constants.DefaultView
+		m.menuItems = m.mainMenu()
 		// Iniciar o comando para buscar a quantidade de wallets
 		return m, walletCountCmd(m.Service)
 	case walletCountMsg:
@@ -331,6 +400,47 @@ func (m *CLIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.walletCount = msg.count
 		}
 		return m, nil
+	case historyLoadedMsg:
+		if msg.err != nil {
+			log.Println("Erro ao buscar o histórico de transações:", msg.err)
+		}
+		m.applyHistoryPage(msg)
+		return m, nil
+	case nftInventoryMsg:
+		if msg.err != nil {
+			log.Println("Erro ao buscar os NFTs da wallet:", msg.err)
+		}
+		m.applyNFTInventory(msg)
+		return m, nil
+	case txBuiltMsg, txSignedMsg, txSentMsg, txDecodedMsg, txSimulatedMsg:
+		return m, m.applyTxMsg(msg)
+	case approvalsScannedMsg, revokesBuiltMsg, revokesSentMsg:
+		return m, m.applyApprovalsMsg(msg)
+	case paymentsLoadedMsg, paymentsSignedMsg, paymentSentMsg:
+		return m, m.applyPaymentsMsg(msg)
+	case sweepPlannedMsg, sweepUnlockedMsg, sweepSentMsg:
+		return m, m.applySweepMsg(msg)
+	case accountsDiscoveredMsg, accountsRegisteredMsg:
+		return m, m.applyDiscoveryMsg(msg)
+	case contractCallMsg:
+		if msg.err != nil {
+			log.Println("Erro ao chamar o contrato:", msg.err)
+		}
+		m.applyContractCall(msg)
+		return m, nil
+	case vaultDoneMsg:
+		return m, m.applyVaultMsg(msg)
+	case unlockTickMsg:
+		return m, m.applyUnlockTick()
+	case unlockReleasedMsg:
+		return m, m.applyUnlockReleased(msg)
+	}
+
+	// Nenhuma tela além do desbloqueio é acessível com a senha mestra pendente
+	if m.vaultLocked() && m.currentView != constants.SplashView && m.currentView != constants.VaultView {
+		m.err = nil
+		m.initVault()
+		return m, nil
 	}
 
 	if m.err != nil {
@@ -364,12 +474,54 @@ func (m *CLIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateWalletPassword(msg)
 	case constants.WalletDetailsView:
 		return m.updateWalletDetails(msg)
+	case constants.HistoryView:
+		return m.updateHistory(msg)
+	case constants.LedgerView:
+		return m.updateLedger(msg)
+	case constants.NFTView:
+		return m.updateNFTs(msg)
+	case constants.TxReviewView:
+		return m.updateTxReview(msg)
+	case constants.ContractsView:
+		return m.updateContracts(msg)
+	case constants.ApprovalsView:
+		return m.updateApprovals(msg)
+	case constants.DiscoveryView:
+		return m.updateDiscovery(msg)
+	case constants.SweepView:
+		return m.updateSweep(msg)
+	case constants.PaymentsView:
+		return m.updatePayments(msg)
+	case constants.VaultView:
+		return m.updateVault(msg)
+	case constants.TwoFactorView:
+		return m.updateTwoFactor(msg)
 	default:
 		m.currentView = constants.DefaultView
 		return m, nil
 	}
 }
 
+// editingText indica se a visualização atual está capturando texto digitado,
+// caso em que as teclas de navegação global não devem ser interceptadas
+func (m *CLIModel) editingText() bool {
+	switch m.currentView {
+	case constants.LedgerView:
+		return m.ledgerEditing
+	case constants.NFTView:
+		return m.nftEditing
+	case constants.ContractsView:
+		return m.contractStep == contractStepLoad || m.contractStep == contractStepDeploy || m.contractStep == contractStepInputs
+	case constants.SweepView:
+		return m.sweepStep == sweepStepSetup || m.sweepStep == sweepStepPassword
+	case constants.PaymentsView:
+		return m.paymentStep == paymentStepFile
+	case constants.VaultView, constants.TwoFactorView:
+		return true
+	}
+	return false
+}
+
 func (m *CLIModel) View() string {
 	if m.err != nil {
 		return m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["error_message"], m.err))
@@ -404,7 +556,7 @@ func (m *CLIModel) renderListWalletsWithLayout() string {
 	headerLeft := lipgloss.JoinVertical(
 		lipgloss.Left,
 		renderedLogo,
-		fmt.Sprintf("Wallets: %d", walletCount),
+		fmt.Sprintf("Wallets: %d", walletCount)+m.portfolioHeaderLine(),
 		fmt.Sprintf("Date: %s", currentTime),
 		fmt.Sprintf("Version: %s", localization.Labels["version"]),
 	)
@@ -539,7 +691,14 @@ func (m *CLIModel) renderImportMenuItems() string {
 func (m *CLIModel) getContentView() string {
 	switch m.currentView {
 	case constants.DefaultView:
-		return localization.Labels["welcome_message"]
+		content := localization.Labels["welcome_message"]
+		if m.DevChain != nil {
+			content += "\n\n" + m.viewDevChain()
+		}
+		if m.Portfolio != nil {
+			content += "\n\n" + m.viewPortfolioSummary()
+		}
+		return content
 	case constants.CreateWalletView:
 		return m.viewCreateWalletPassword()
 	case constants.ImportMethodSelectionView:
@@ -556,6 +715,28 @@ func (m *CLIModel) getContentView() string {
 		return m.viewWalletPassword()
 	case constants.WalletDetailsView:
 		return m.viewWalletDetails()
+	case constants.HistoryView:
+		return m.viewHistory()
+	case constants.LedgerView:
+		return m.viewLedger()
+	case constants.NFTView:
+		return m.viewNFTs()
+	case constants.ContractsView:
+		return m.viewContracts()
+	case constants.ApprovalsView:
+		return m.viewApprovals()
+	case constants.DiscoveryView:
+		return m.viewDiscovery()
+	case constants.SweepView:
+		return m.viewSweep()
+	case constants.PaymentsView:
+		return m.viewPayments()
+	case constants.VaultView:
+		return m.viewVault()
+	case constants.TwoFactorView:
+		return m.viewTwoFactor()
+	case constants.TxReviewView:
+		return m.viewTxReview()
 	default:
 		return localization.Labels["unknown_state"]
 	}
@@ -589,6 +770,14 @@ func (m *CLIModel) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.initImportWallet()
 			case localization.Labels["list_wallets"]:
 				m.initListWallets()
+				return m, m.lookupWalletNames()
+			case localization.Labels["ledger"]:
+				m.initLedger()
+			case localization.Labels["dev_mine_block"]:
+				return m, m.mineDevBlock()
+			case localization.Labels["master_password"]:
+				m.setVaultStep(vaultStepChange)
+				m.currentView = constants.VaultView
 			case tea.KeyCtrlX.String(), "q", localization.Labels["exit"]:
 				return m, tea.Quit
 			}
@@ -596,8 +785,8 @@ func (m *CLIModel) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "esc", "backspace":
 			// Voltar para o menu principal
-			m.menuItems = NewMenu() // Recarregar o menu principal
-			m.selectedMenu = 0      // Resetar a seleção
+			m.menuItems = m.mainMenu() // Recarregar o menu principal
+			m.selectedMenu = 0         // Resetar a seleção
 			m.currentView = constants.DefaultView
 		}
 	}
@@ -623,8 +812,7 @@ func (m *CLIModel) updateCreateWalletPassword(msg tea.Msg) (tea.Model, tea.Cmd)
 				m.currentView = constants.DefaultView
 				return m, nil
 			}
-			m.walletDetails = walletDetails
-			m.currentView = constants.WalletDetailsView
+			m.showWalletDetails(walletDetails)
 
 			// Atualizar a contagem de wallets
 			return m, m.refreshWalletsTable()
@@ -712,8 +900,12 @@ func (m *CLIModel) updateImportWalletPassword(msg tea.Msg) (tea.Model, tea.Cmd)
 				return m, nil
 			}
 
-			m.walletDetails = walletDetails
-			m.currentView = constants.WalletDetailsView
+			m.showWalletDetails(walletDetails)
+
+			// Procurar outras contas usadas da mesma frase mnemônica
+			if len(m.privateKeyInput.Value()) == 0 && m.Discovery != nil {
+				return m, tea.Batch(m.refreshWalletsTable(), m.initDiscovery(walletDetails.Mnemonic, password))
+			}
 
 			// Atualizar a contagem de wallets
 			return m, m.refreshWalletsTable()
@@ -840,25 +1032,11 @@ func (m *CLIModel) updateListWallets(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.dialogButtonIndex = 0
 
 				if shouldDelete {
-					// Executar a exclusão
-					err := m.Service.DeleteWallet(walletToDelete)
-					if err != nil {
-						m.err = errors.Wrap(err, 0)
-					}
-
-					// Recarregar a lista de wallets
-					wallets, err := m.Service.GetAllWallets()
-					if err == nil {
-						m.wallets = wallets
-						m.walletCount = len(wallets)
-
-						// Reconstruir linhas da tabela
-						rows := make([]table.Row, len(wallets))
-						for i, w := range wallets {
-							rows[i] = table.Row{fmt.Sprintf("%d", w.ID), w.Address}
-						}
-						m.walletTable.SetRows(rows)
-					}
+					// A exclusão pode exigir o código de dois fatores da wallet
+					wallet := *walletToDelete
+					return m, m.requireTwoFactor(domain.TwoFactorDelete, []twoFactorTarget{{wallet: wallet}}, func() tea.Cmd {
+						return m.deleteWallet(&wallet)
+					})
 				}
 
 				// Forçar uma atualização da tela
@@ -881,7 +1059,7 @@ func (m *CLIModel) updateListWallets(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "d", "delete":
 			selectedRow := m.walletTable.SelectedRow()
 			if len(selectedRow) > 1 {
-				address := selectedRow[1]
+				address := walletRowAddress(selectedRow)
 				for i, w := range m.wallets {
 					if w.Address == address {
 						m.deletingWallet = &m.wallets[i]
@@ -889,19 +1067,39 @@ func (m *CLIModel) updateListWallets(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+		case "h":
+			if m.History == nil {
+				return m, nil
+			}
+			selectedRow := m.walletTable.SelectedRow()
+			if len(selectedRow) > 1 {
+				address := walletRowAddress(selectedRow)
+				for i, w := range m.wallets {
+					if w.Address == address {
+						return m, m.initHistory(&m.wallets[i])
+					}
+				}
+			}
 		case "enter":
 			selectedRow := m.walletTable.SelectedRow()
 			if len(selectedRow) > 1 {
-				address := selectedRow[1]
+				address := walletRowAddress(selectedRow)
 				// Buscar wallet pela address
 				for _, w := range m.wallets {
 					if w.Address == address {
 						m.selectedWallet = &w
-						m.initWalletPassword()
-						return m, nil
+						return m, m.openSelectedWallet()
 					}
 				}
 			}
+		case " ":
+			m.toggleSweepSelection()
+			return m, nil
+		case "s":
+			if m.Sweeps != nil && m.Transactions != nil {
+				m.initSweep()
+			}
+			return m, nil
 		case "esc", "backspace":
 			m.currentView = constants.DefaultView
 			return m, nil
@@ -914,9 +1112,37 @@ func (m *CLIModel) updateListWallets(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// deleteWallet exclui a wallet e recarrega a lista
+func (m *CLIModel) deleteWallet(wallet *domain.Wallet) tea.Cmd {
+	err := m.Service.DeleteWallet(wallet)
+	if err != nil {
+		m.err = errors.Wrap(err, 0)
+	}
+
+	// Recarregar a lista de wallets
+	wallets, err := m.Service.GetAllWallets()
+	if err == nil {
+		m.wallets = wallets
+		m.walletCount = len(wallets)
+
+		// Reconstruir linhas da tabela
+		rows := make([]table.Row, len(wallets))
+		for i, w := range wallets {
+			rows[i] = m.walletRow(w)
+		}
+		m.walletTable.SetRows(rows)
+	}
+
+	// Forçar uma atualização da tela
+	return m.refreshWalletsTable()
+}
+
 func (m *CLIModel) updateWalletPassword(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.unlockRelease {
+			return m.updateLockoutRelease(msg)
+		}
 		switch msg.String() {
 		case "enter":
 			password := strings.TrimSpace(m.passwordInput.Value())
@@ -926,15 +1152,26 @@ func (m *CLIModel) updateWalletPassword(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.currentView = constants.DefaultView
 				return m, nil
 			}
-			walletDetails, err := m.Service.LoadWallet(m.selectedWallet, password)
+			walletDetails, err := m.Service.UnlockWithPassword(m.selectedWallet, password, "password")
+			if cmd, ok := m.applyUnlockError(err); ok {
+				return m, cmd
+			}
 			if err != nil {
 				m.err = errors.Wrap(err, 0)
 				log.Println(m.err.(*errors.Error).ErrorStack())
 				m.currentView = constants.DefaultView
 				return m, nil
 			}
-			m.walletDetails = walletDetails
-			m.currentView = constants.WalletDetailsView
+			m.showWalletDetails(walletDetails)
+			if m.syncKeyring(password) {
+				return m, m.refreshWalletsTable()
+			}
+		case "tab":
+			if m.keyringAvailable {
+				m.rememberPassword = !m.rememberPassword
+			}
+		case "ctrl+u":
+			m.startLockoutRelease()
 		case "esc", "backspace":
 			m.currentView = constants.DefaultView
 		default:
@@ -952,8 +1189,40 @@ func (m *CLIModel) updateWalletDetails(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "esc", "backspace":
 			m.walletDetails = nil
+			m.keyringNotice = ""
 			m.currentView = constants.ListWalletsView
 			return m, nil // Return explícito para consumir o evento de teclado
+		case "f":
+			if m.walletDetails != nil && m.walletDetails.Wallet.Keyring {
+				return m, m.forgetKeyringPassword()
+			}
+		case "n":
+			if m.walletDetails != nil && m.NFTs != nil {
+				return m, m.initNFTs()
+			}
+		case "c":
+			if m.walletDetails != nil && m.Contracts != nil && m.Transactions != nil {
+				m.initContracts()
+				return m, nil
+			}
+		case "a":
+			if m.walletDetails != nil && m.Approvals != nil && m.Transactions != nil {
+				return m, m.initApprovals()
+			}
+		case "p":
+			if m.walletDetails != nil && m.Payments != nil {
+				m.initPayments()
+				return m, nil
+			}
+		case "r":
+			if m.walletDetails != nil && m.detailsHidden {
+				return m, m.revealWalletKeys()
+			}
+		case "t":
+			if m.walletDetails != nil && m.TwoFactor != nil {
+				m.initTwoFactor()
+				return m, nil
+			}
 		}
 	}
 	return m, nil
@@ -983,19 +1252,43 @@ func (m *CLIModel) updateTableDimensions() {
 	m.walletTable.SetWidth(m.width - 4)
 	m.walletTable.SetHeight(contentAreaHeight)
 
-	// Calcular larguras das colunas
+	// Atualizar colunas
+	m.walletTable.SetColumns(m.walletColumns())
+}
+
+// walletColumns calcula as colunas da tabela de wallets para a largura atual
+func (m *CLIModel) walletColumns() []table.Column {
 	idColWidth := 10
-	addressColWidth := m.width - idColWidth - 8 // Subtrai 8 para padding e margens
+	valueColWidth := 0
+	if m.Portfolio != nil {
+		valueColWidth = 28
+	}
+	addressColWidth := m.width - idColWidth - valueColWidth - 8 // Subtrai 8 para padding e margens
 
 	if addressColWidth < 20 {
 		addressColWidth = 20
 	}
 
-	// Atualizar colunas
-	m.walletTable.SetColumns([]table.Column{
+	columns := []table.Column{
 		{Title: localization.Labels["id"], Width: idColWidth},
 		{Title: localization.Labels["ethereum_address"], Width: addressColWidth},
-	})
+	}
+	if m.Portfolio != nil {
+		columns = append(columns, table.Column{
+			Title: fmt.Sprintf(localization.Labels["portfolio_value_column"], strings.ToUpper(m.Portfolio.Currency)),
+			Width: valueColWidth,
+		})
+	}
+	return columns
+}
+
+// walletRow monta a linha da tabela de wallets, incluindo o valor em moeda fiduciária quando disponível
+func (m *CLIModel) walletRow(w domain.Wallet) table.Row {
+	row := table.Row{m.walletIDLabel(w), m.walletAddressLabel(w.Address)}
+	if m.Portfolio != nil {
+		row = append(row, m.walletValue(w.Address))
+	}
+	return row
 }
 
 // Funções de inicialização
@@ -1046,21 +1339,11 @@ func (m *CLIModel) initListWallets() {
 	m.wallets = wallets
 
 	// Inicialize as colunas com larguras adequadas
-	idColWidth := 10
-	addressColWidth := m.width - idColWidth - 8 // Subtrai 8 para padding e margens
-
-	if addressColWidth < 20 {
-		addressColWidth = 20
-	}
-
-	columns := []table.Column{
-		{Title: localization.Labels["id"], Width: idColWidth},
-		{Title: localization.Labels["ethereum_address"], Width: addressColWidth},
-	}
+	columns := m.walletColumns()
 
 	var rows []table.Row
 	for _, w := range m.wallets {
-		rows = append(rows, table.Row{fmt.Sprintf("%d", w.ID), w.Address})
+		rows = append(rows, m.walletRow(w))
 	}
 
 	m.walletTable = table.New(
@@ -1096,7 +1379,7 @@ func (m *CLIModel) initListWallets() {
 	m.currentView = constants.ListWalletsView
 }
 
-func (m *CLIModel) initWalletPassword() {
+func (m *CLIModel) initWalletPassword() tea.Cmd {
 	m.passwordInput = textinput.New()
 	m.passwordInput.Placeholder = localization.Labels["enter_wallet_password"]
 	m.passwordInput.CharLimit = constants.PasswordCharLimit
@@ -1104,7 +1387,10 @@ func (m *CLIModel) initWalletPassword() {
 	m.passwordInput.EchoMode = textinput.EchoPassword
 	m.passwordInput.EchoCharacter = '•'
 	m.passwordInput.Focus()
+	m.keyringAvailable = m.Service.KeyringAvailable()
+	m.rememberPassword = m.selectedWallet != nil && m.selectedWallet.Keyring
 	m.currentView = constants.WalletPasswordView
+	return m.loadUnlockStatus()
 }
 
 // walletsRefreshedMsg é uma mensagem personalizada para indicar que a lista de wallets foi atualizada
@@ -1128,7 +1414,7 @@ func (m *CLIModel) refreshWalletsTable() tea.Cmd {
 		// Reconstruir as linhas da tabela
 		var rows []table.Row
 		for _, w := range m.wallets {
-			rows = append(rows, table.Row{fmt.Sprintf("%d", w.ID), w.Address})
+			rows = append(rows, m.walletRow(w))
 		}
 
 		// Atualizar a tabela com as novas linhas
@@ -1141,21 +1427,11 @@ func (m *CLIModel) refreshWalletsTable() tea.Cmd {
 
 func (m *CLIModel) rebuildWalletsTable() {
 	// Inicialize as colunas com larguras adequadas
-	idColWidth := 10
-	addressColWidth := m.width - idColWidth - 8 // Subtrai 8 para padding e margens
-
-	if addressColWidth < 20 {
-		addressColWidth = 20
-	}
-
-	columns := []table.Column{
-		{Title: localization.Labels["id"], Width: idColWidth},
-		{Title: localization.Labels["ethereum_address"], Width: addressColWidth},
-	}
+	columns := m.walletColumns()
 
 	var rows []table.Row
 	for _, w := range m.wallets {
-		rows = append(rows, table.Row{fmt.Sprintf("%d", w.ID), w.Address})
+		rows = append(rows, m.walletRow(w))
 	}
 
 	m.walletTable = table.New(
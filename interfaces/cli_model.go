@@ -5,7 +5,11 @@ import (
 	"blocowallet/usecases"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/digitallyserviced/tdfgo/tdf"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+	"time"
 )
 
 type CLIModel struct {
@@ -33,4 +37,189 @@ type CLIModel struct {
 	selectedFont      *tdf.TheDrawFont // Fonte selecionada aleatoriamente
 	fontInfo          *tdf.FontInfo    // Informação da fonte selecionada
 	dialogButtonIndex int              // 0 = Confirmar, 1 = Cancelar
+
+	// Senha mestra que protege a aplicação e os metadados do banco
+	Vault                 *usecases.VaultService
+	vaultStep             int
+	vaultInputs           []textinput.Model
+	vaultFocus            int
+	vaultRecoveryKey      string // Exibida uma única vez
+	vaultUnlockOnContinue bool   // A chave exibida veio da primeira definição da senha
+	vaultBusy             bool
+	vaultMessage          string
+
+	// Autenticação em dois fatores (TOTP) por wallet
+	TwoFactor           *usecases.TwoFactorService
+	twoFactorMode       int
+	twoFactorWallet     domain.Wallet
+	twoFactorEnrollment *usecases.TwoFactorEnrollment // Segredo e códigos de recuperação exibidos uma única vez
+	twoFactorQR         string
+	twoFactorPolicy     domain.TwoFactorPolicy
+	twoFactorThreshold  textinput.Model
+	twoFactorCode       textinput.Model
+	twoFactorFocus      int // Linha selecionada nas configurações
+	twoFactorMessage    string
+	twoFactorNotice     string                    // Aviso exibido nos detalhes da wallet
+	twoFactorQueue      []domain.Wallet           // Wallets que ainda precisam do código na operação pendente
+	twoFactorTotal      int                       // Wallets que a operação pendente envolve
+	twoFactorOperation  domain.TwoFactorOperation // Operação aguardando os códigos
+	twoFactorReturn     string                    // Tela de origem da operação
+	twoFactorDone       func() tea.Cmd            // Continuação executada após todos os códigos
+	detailsHidden       bool                      // Chave privada e mnemônico ocultos até a confirmação do código
+
+	// Senhas guardadas no chaveiro do sistema
+	keyringAvailable bool
+	rememberPassword bool // Opção marcada na tela de senha
+	keyringNotice    string
+
+	// Tentativas de senha malsucedidas da wallet selecionada
+	unlockStatus  usecases.UnlockStatus
+	unlockMessage string
+	unlockRelease bool // O campo de senha recebe a senha mestra para encerrar o bloqueio
+	unlockBusy    bool
+	unlockTicking bool // Contagem regressiva da espera em andamento
+
+	// Histórico de transações
+	History           *usecases.HistoryService
+	historyWallet     *domain.Wallet
+	historyKindIndex  int // Índice em domain.HistoryKinds
+	historyPageNumber int
+	historyPage       *usecases.HistoryPage
+	historyLoading    bool
+	historyErr        error
+	historyTable      table.Model
+
+	// Livro-razão local de transações
+	Ledger            *usecases.LedgerService
+	ledgerEntries     []domain.LedgerEntry
+	ledgerTable       table.Model
+	ledgerWalletIndex int // -1 = todas as wallets
+	ledgerStatusIndex int // -1 = todos os status
+	ledgerDateInputs  []textinput.Model
+	ledgerDateFocus   int
+	ledgerEditing     bool
+	ledgerMessage     string
+
+	// Construção, revisão e assinatura de transações
+	Transactions  *usecases.TransactionService
+	reviewTx      *types.Transaction // Transação ainda não assinada
+	reviewSigned  *types.Transaction
+	reviewMemo    string
+	reviewReturn  string // Visualização de origem
+	reviewBusy    bool
+	reviewErr     error
+	reviewMessage string
+	txNotice      string // Aviso exibido na visualização de origem
+
+	// Decodificação legível dos dados da chamada na revisão
+	Decoder         *usecases.CalldataDecoder
+	reviewDecoded   *usecases.DecodedCall
+	reviewDecodeErr error
+	reviewDecoding  bool
+
+	// Simulação antes da assinatura
+	Simulator        *usecases.SimulationService
+	reviewSimulation *usecases.Simulation
+	reviewSimErr     error
+	reviewSimulating bool
+
+	// NFTs da wallet desbloqueada
+	NFTs         *usecases.NFTService
+	nftItems     []domain.NFT
+	nftTable     table.Model
+	nftLoading   bool
+	nftErr       error
+	nftEditing   bool
+	nftRecipient textinput.Model
+
+	// Avaliação do portfólio em moeda fiduciária
+	Portfolio        *usecases.PortfolioService
+	PortfolioRefresh time.Duration
+	portfolio        *usecases.Portfolio
+	portfolioErr     error
+
+	// Workbench de contratos a partir de arquivos ABI
+	Contracts             *usecases.ContractService
+	contractStep          int
+	contractList          []domain.SavedContract
+	contractTable         table.Model
+	contractSelected      *domain.SavedContract
+	contractABI           abi.ABI
+	contractFunctions     []abi.Method
+	contractMethod        abi.Method
+	contractArtifact      *usecases.ContractArtifact // Definido durante a implantação
+	contractDeployName    string
+	contractPendingDeploy *domain.SavedContract // Salvo com o endereço após a transmissão
+	contractInputs        []textinput.Model
+	contractFocus         int
+	contractResult        []string
+	contractBusy          bool
+	contractMessage       string
+
+	// Auditoria e revogação de aprovações de tokens
+	Approvals            *usecases.ApprovalService
+	approvalItems        []usecases.TokenApproval
+	approvalTable        table.Model
+	approvalSelected     map[int]bool
+	approvalStatus       map[int]string // Hash ou falha da revogação por item
+	approvalBatch        []*types.Transaction
+	approvalBatchIndexes []int
+	approvalLoading      bool
+	approvalBusy         bool
+	approvalErr          error
+	approvalMessage      string
+
+	// Descoberta de contas HD após a importação de uma frase mnemônica
+	Discovery         *usecases.AccountDiscovery
+	discoveryMnemonic string // Mantidos apenas até o registro ou a saída da tela
+	discoveryPassword string
+	discoveryAccounts []usecases.DiscoveredAccount
+	discoverySelected map[int]bool
+	discoveryTable    table.Model
+	discoveryLoading  bool
+	discoveryBusy     bool
+	discoveryErr      error
+	discoveryMessage  string
+
+	// Varredura de saldos de várias wallets para um destino
+	Sweeps           *usecases.SweepService
+	sweepSelected    map[string]bool // Endereços marcados na lista de wallets, em minúsculas
+	sweepWallets     []domain.Wallet
+	sweepStep        int
+	sweepDestination textinput.Model
+	sweepAsset       int
+	sweepTo          string
+	sweepItems       []usecases.SweepItem
+	sweepTable       table.Model
+	sweepUnlocked    map[int]*usecases.WalletDetails
+	sweepStatus      map[int]string // Hash ou falha da transmissão por item
+	sweepPassword    textinput.Model
+	sweepBusy        bool
+	sweepMessage     string
+
+	// Pagamentos em lote a partir de um arquivo CSV
+	Payments        *usecases.PaymentService
+	paymentStep     int
+	paymentPath     textinput.Model
+	paymentFile     string
+	paymentDisperse bool
+	paymentItems    []usecases.Payment
+	paymentTotals   []usecases.AssetTotal
+	paymentTable    table.Model
+	paymentBatch    *usecases.PaymentBatch
+	paymentNext     int // Próxima transação do lote a transmitir
+	paymentBusy     bool
+	paymentMessage  string
+
+	// Cadeia local do modo dev
+	DevChain    domain.BlockProducer
+	DevEndpoint string
+	devMessage  string
+
+	// Endpoint RPC ativo quando a rede usa failover
+	RPC domain.EndpointMonitor
+
+	// Resolução de nomes ENS
+	Names       domain.NameResolver
+	walletNames map[string]string // Nome primário verificado por endereço em minúsculas
 }
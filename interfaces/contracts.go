@@ -0,0 +1,428 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Etapas do workbench de contratos
+const (
+	contractStepList = iota
+	contractStepLoad
+	contractStepDeploy
+	contractStepFunctions
+	contractStepInputs
+)
+
+type contractCallMsg struct {
+	outputs []string
+	err     error
+}
+
+func callContractCmd(service *usecases.ContractService, from, contract common.Address, contractABI abi.ABI, method abi.Method, args []interface{}) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		values, err := service.Call(ctx, from, contract, contractABI, method, args)
+		if err != nil {
+			return contractCallMsg{err: err}
+		}
+		outputs := make([]string, len(values))
+		for i, value := range values {
+			name := method.Outputs[i].Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			outputs[i] = fmt.Sprintf("%s (%s) = %s", name, method.Outputs[i].Type.String(), usecases.FormatValue(value))
+		}
+		return contractCallMsg{outputs: outputs}
+	}
+}
+
+// initContracts abre o workbench com os contratos salvos da rede ativa
+func (m *CLIModel) initContracts() {
+	m.contractMessage = ""
+	m.txNotice = ""
+	m.currentView = constants.ContractsView
+	m.showContractList()
+}
+
+func (m *CLIModel) showContractList() {
+	m.contractStep = contractStepList
+	m.contractSelected = nil
+	m.contractArtifact = nil
+	contracts, err := m.Contracts.SavedContracts()
+	if err != nil {
+		log.Println("Erro ao carregar os contratos salvos:", err)
+		m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+	}
+	m.contractList = contracts
+	rows := make([]table.Row, 0, len(contracts))
+	for _, c := range contracts {
+		rows = append(rows, table.Row{c.Name, common.HexToAddress(c.Address).Hex()})
+	}
+	m.contractTable = newDataTable([]table.Column{
+		{Title: localization.Labels["contract_name"], Width: 30},
+		{Title: localization.Labels["contract_address"], Width: 44},
+	}, rows, m.tableHeight())
+}
+
+// showContractForm abre o formulário de carregamento de ABI ou de implantação
+func (m *CLIModel) showContractForm(step int) {
+	m.contractStep = step
+	m.contractMessage = ""
+	placeholders := []string{"contract_abi_path", "contract_address", "contract_name"}
+	if step == contractStepDeploy {
+		placeholders = []string{"contract_artifact_path", "contract_bytecode_path", "contract_name"}
+	}
+	m.contractInputs = make([]textinput.Model, len(placeholders))
+	for i, label := range placeholders {
+		ti := textinput.New()
+		ti.Placeholder = localization.Labels[label]
+		ti.CharLimit = 512
+		ti.Width = 60
+		m.contractInputs[i] = ti
+	}
+	m.contractFocus = 0
+	m.contractInputs[0].Focus()
+}
+
+func (m *CLIModel) openContract(contract *domain.SavedContract) {
+	parsed, err := usecases.ParseABI(contract.ABI)
+	if err != nil {
+		m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+		return
+	}
+	m.contractSelected = contract
+	m.contractABI = parsed
+	m.contractFunctions = usecases.Functions(parsed)
+	m.showContractFunctions()
+}
+
+func (m *CLIModel) showContractFunctions() {
+	m.contractStep = contractStepFunctions
+	m.contractResult = nil
+	rows := make([]table.Row, 0, len(m.contractFunctions))
+	for _, method := range m.contractFunctions {
+		rows = append(rows, table.Row{method.Sig, method.StateMutability})
+	}
+	m.contractTable = newDataTable([]table.Column{
+		{Title: localization.Labels["contract_function"], Width: 60},
+		{Title: localization.Labels["contract_mutability"], Width: 12},
+	}, rows, m.tableHeight())
+}
+
+// showContractInputs cria um campo por argumento e, se for payable, o campo de valor
+func (m *CLIModel) showContractInputs(method abi.Method) {
+	m.contractStep = contractStepInputs
+	m.contractMethod = method
+	m.contractResult = nil
+	m.contractMessage = ""
+	m.contractInputs = nil
+	for i, input := range method.Inputs {
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		ti := textinput.New()
+		ti.Placeholder = fmt.Sprintf("%s (%s)", name, input.Type.String())
+		ti.CharLimit = 4096
+		ti.Width = 60
+		m.contractInputs = append(m.contractInputs, ti)
+	}
+	if method.IsPayable() {
+		ti := textinput.New()
+		ti.Placeholder = fmt.Sprintf(localization.Labels["contract_value"], constants.DefaultNativeSymbol)
+		ti.CharLimit = 78
+		ti.Width = 60
+		m.contractInputs = append(m.contractInputs, ti)
+	}
+	m.contractFocus = 0
+	if len(m.contractInputs) > 0 {
+		m.contractInputs[0].Focus()
+	}
+}
+
+// contractBack volta uma etapa no workbench
+func (m *CLIModel) contractBack() {
+	m.contractMessage = ""
+	m.txNotice = ""
+	switch m.contractStep {
+	case contractStepInputs:
+		if m.contractArtifact != nil {
+			m.showContractList()
+			return
+		}
+		m.showContractFunctions()
+	case contractStepFunctions, contractStepLoad, contractStepDeploy:
+		m.showContractList()
+	default:
+		m.currentView = constants.WalletDetailsView
+	}
+}
+
+func (m *CLIModel) updateContracts(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.contractBusy {
+		return m, nil
+	}
+
+	if m.contractStep == contractStepLoad || m.contractStep == contractStepDeploy || m.contractStep == contractStepInputs {
+		switch keyMsg.String() {
+		case "esc":
+			m.contractBack()
+			return m, nil
+		case "tab", "down":
+			return m, m.focusContractInput(m.contractFocus + 1)
+		case "shift+tab", "up":
+			return m, m.focusContractInput(m.contractFocus - 1)
+		case "enter":
+			return m, m.submitContractStep()
+		}
+		if len(m.contractInputs) == 0 {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.contractInputs[m.contractFocus], cmd = m.contractInputs[m.contractFocus].Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "enter":
+		cursor := m.contractTable.Cursor()
+		if m.contractStep == contractStepList && cursor < len(m.contractList) {
+			m.openContract(&m.contractList[cursor])
+		} else if m.contractStep == contractStepFunctions && cursor < len(m.contractFunctions) {
+			m.showContractInputs(m.contractFunctions[cursor])
+		}
+		return m, nil
+	case "l":
+		if m.contractStep == contractStepList {
+			m.showContractForm(contractStepLoad)
+			return m, nil
+		}
+	case "d":
+		if m.contractStep == contractStepList {
+			m.showContractForm(contractStepDeploy)
+			return m, nil
+		}
+	case "x":
+		cursor := m.contractTable.Cursor()
+		if m.contractStep == contractStepList && cursor < len(m.contractList) {
+			if err := m.Contracts.DeleteContract(m.contractList[cursor].ID); err != nil {
+				m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+				return m, nil
+			}
+			m.showContractList()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.contractTable, cmd = m.contractTable.Update(msg)
+	return m, cmd
+}
+
+func (m *CLIModel) focusContractInput(index int) tea.Cmd {
+	if len(m.contractInputs) == 0 {
+		return nil
+	}
+	m.contractInputs[m.contractFocus].Blur()
+	m.contractFocus = (index + len(m.contractInputs)) % len(m.contractInputs)
+	return m.contractInputs[m.contractFocus].Focus()
+}
+
+func (m *CLIModel) contractInputValue(index int) string {
+	return strings.TrimSpace(m.contractInputs[index].Value())
+}
+
+func (m *CLIModel) submitContractStep() tea.Cmd {
+	m.contractMessage = ""
+	switch m.contractStep {
+	case contractStepLoad:
+		artifact, err := usecases.LoadArtifact(m.contractInputValue(0), "")
+		if err != nil {
+			m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+			return nil
+		}
+		contract, err := m.Contracts.SaveContract(m.contractInputValue(2), m.contractInputValue(1), artifact.ABIJSON)
+		if err != nil {
+			m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+			return nil
+		}
+		m.openContract(contract)
+	case contractStepDeploy:
+		artifact, err := usecases.LoadArtifact(m.contractInputValue(0), m.contractInputValue(1))
+		if err != nil {
+			m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+			return nil
+		}
+		if len(artifact.Bytecode) == 0 {
+			m.contractMessage = localization.Labels["contract_no_bytecode"]
+			return nil
+		}
+		m.contractArtifact = artifact
+		m.contractDeployName = m.contractInputValue(2)
+		m.showContractInputs(artifact.ABI.Constructor)
+	case contractStepInputs:
+		return m.executeContractMethod()
+	}
+	return nil
+}
+
+// executeContractMethod valida os argumentos e executa a chamada, a escrita ou a implantação
+func (m *CLIModel) executeContractMethod() tea.Cmd {
+	method := m.contractMethod
+	values := make([]string, len(method.Inputs))
+	for i := range method.Inputs {
+		values[i] = m.contractInputValue(i)
+	}
+	args, err := usecases.ParseArguments(method.Inputs, values)
+	if err != nil {
+		m.contractMessage = fmt.Sprintf(localization.Labels["contract_invalid_input"], err)
+		return nil
+	}
+	var value *big.Int
+	if method.IsPayable() {
+		if amount := m.contractInputValue(len(method.Inputs)); amount != "" {
+			value, err = usecases.ParseUnits(amount, constants.NativeDecimals)
+			if err != nil || value.Sign() < 0 {
+				m.contractMessage = fmt.Sprintf(localization.Labels["contract_invalid_input"], fmt.Sprintf("%s: %s", constants.DefaultNativeSymbol, amount))
+				return nil
+			}
+		}
+	}
+	from := common.HexToAddress(m.walletDetails.Wallet.Address)
+
+	if m.contractArtifact != nil {
+		data, err := m.Contracts.DeployData(m.contractArtifact, args)
+		if err != nil {
+			m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+			return nil
+		}
+		m.contractPendingDeploy = &domain.SavedContract{Name: m.contractDeployName, ABI: m.contractArtifact.ABIJSON}
+		m.txNotice = localization.Labels["tx_building"]
+		m.reviewMemo = fmt.Sprintf("deploy %s", m.contractDeployName)
+		return buildTxCmd(m.Transactions, from, nil, value, data)
+	}
+
+	contract := common.HexToAddress(m.contractSelected.Address)
+	if usecases.IsReadOnly(method) {
+		m.contractBusy = true
+		m.contractMessage = localization.Labels["contract_calling"]
+		return callContractCmd(m.Contracts, from, contract, m.contractABI, method, args)
+	}
+
+	data, err := m.Contracts.Calldata(m.contractABI, method, args)
+	if err != nil {
+		m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], err)
+		return nil
+	}
+	m.contractPendingDeploy = nil
+	m.txNotice = localization.Labels["tx_building"]
+	m.reviewMemo = fmt.Sprintf("%s.%s", m.contractSelected.Name, method.RawName)
+	return buildTxCmd(m.Transactions, from, &contract, value, data)
+}
+
+func (m *CLIModel) applyContractCall(msg contractCallMsg) {
+	m.contractBusy = false
+	m.contractMessage = ""
+	if msg.err != nil {
+		m.contractMessage = fmt.Sprintf(localization.Labels["contract_error"], msg.err)
+		return
+	}
+	m.contractResult = msg.outputs
+	if len(msg.outputs) == 0 {
+		m.contractResult = []string{localization.Labels["contract_no_outputs"]}
+	}
+}
+
+// saveDeployedContract registra o contrato implantado após a transmissão
+func (m *CLIModel) saveDeployedContract(tx *types.Transaction) {
+	pending := m.contractPendingDeploy
+	m.contractPendingDeploy = nil
+	if pending == nil || tx.To() != nil || m.walletDetails == nil {
+		return
+	}
+	address := crypto.CreateAddress(common.HexToAddress(m.walletDetails.Wallet.Address), tx.Nonce())
+	if _, err := m.Contracts.SaveContract(pending.Name, address.Hex(), pending.ABI); err != nil {
+		log.Println("Erro ao salvar o contrato implantado:", err)
+		return
+	}
+	m.reviewMessage += "\n" + fmt.Sprintf(localization.Labels["contract_deployed"], address.Hex())
+}
+
+// viewContracts renderiza a etapa atual do workbench
+func (m *CLIModel) viewContracts() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
+	parts := []string{titleStyle.Render(fmt.Sprintf(localization.Labels["contract_title"], m.walletDetails.Wallet.Address)), ""}
+
+	switch m.contractStep {
+	case contractStepList:
+		if len(m.contractList) == 0 {
+			parts = append(parts, localization.Labels["contract_empty"])
+		} else {
+			parts = append(parts, m.contractTable.View())
+		}
+		parts = append(parts, "", localization.Labels["contract_list_help"])
+	case contractStepLoad, contractStepDeploy:
+		title := "contract_load_title"
+		if m.contractStep == contractStepDeploy {
+			title = "contract_deploy_title"
+		}
+		parts = append(parts, localization.Labels[title])
+		for _, input := range m.contractInputs {
+			parts = append(parts, input.View())
+		}
+		parts = append(parts, "", localization.Labels["contract_form_help"])
+	case contractStepFunctions:
+		parts = append(parts, fmt.Sprintf("%s  %s", m.contractSelected.Name, common.HexToAddress(m.contractSelected.Address).Hex()), "")
+		if len(m.contractFunctions) == 0 {
+			parts = append(parts, localization.Labels["contract_no_functions"])
+		} else {
+			parts = append(parts, m.contractTable.View())
+		}
+		parts = append(parts, "", localization.Labels["contract_functions_help"])
+	case contractStepInputs:
+		signature := m.contractMethod.Sig
+		if m.contractArtifact != nil {
+			signature = fmt.Sprintf(localization.Labels["contract_constructor"], m.contractDeployName)
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s]", signature, m.contractMethod.StateMutability))
+		if len(m.contractInputs) == 0 {
+			parts = append(parts, localization.Labels["contract_no_inputs"])
+		}
+		for _, input := range m.contractInputs {
+			parts = append(parts, input.View())
+		}
+		if len(m.contractResult) > 0 {
+			parts = append(parts, "", localization.Labels["contract_result"])
+			parts = append(parts, m.contractResult...)
+		}
+		parts = append(parts, "", localization.Labels["contract_form_help"])
+	}
+
+	if m.contractMessage != "" {
+		parts = append(parts, "", m.contractMessage)
+	}
+	if m.txNotice != "" {
+		parts = append(parts, "", m.txNotice)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
@@ -0,0 +1,70 @@
+package interfaces
+
+import (
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"context"
+	"fmt"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Mensagem com o número do bloco minerado na cadeia local
+type devBlockMinedMsg struct {
+	number uint64
+	err    error
+}
+
+func mineDevBlockCmd(chain domain.BlockProducer) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		number, err := chain.Mine(ctx)
+		return devBlockMinedMsg{number: number, err: err}
+	}
+}
+
+// mainMenu retorna o menu principal, com as ações do modo dev quando ativo
+func (m *CLIModel) mainMenu() []menuItem {
+	items := NewMenu()
+	if m.DevChain != nil {
+		items = NewDevMenu()
+	}
+	if m.Vault != nil {
+		items = withMasterPasswordItem(items)
+	}
+	return items
+}
+
+func (m *CLIModel) mineDevBlock() tea.Cmd {
+	if m.DevChain == nil {
+		return nil
+	}
+	m.devMessage = localization.Labels["dev_mining"]
+	return mineDevBlockCmd(m.DevChain)
+}
+
+func (m *CLIModel) applyDevBlock(msg devBlockMinedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Println("Erro ao minerar o bloco:", msg.err)
+		m.devMessage = fmt.Sprintf(localization.Labels["dev_mine_error"], msg.err)
+		return nil
+	}
+	m.devMessage = fmt.Sprintf(localization.Labels["dev_block_mined"], msg.number)
+
+	// Os saldos mudam com as transações incluídas no bloco
+	if m.Portfolio != nil {
+		return fetchPortfolioCmd(m.Service, m.Portfolio)
+	}
+	return nil
+}
+
+// viewDevChain mostra o endpoint da cadeia local e o resultado da última mineração
+func (m *CLIModel) viewDevChain() string {
+	view := fmt.Sprintf(localization.Labels["dev_running"], m.DevEndpoint)
+	if m.devMessage != "" {
+		view += "\n" + m.devMessage
+	}
+	return view
+}
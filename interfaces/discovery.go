@@ -0,0 +1,237 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type accountsDiscoveredMsg struct {
+	accounts []usecases.DiscoveredAccount
+	err      error
+}
+
+type accountsRegisteredMsg struct {
+	wallets []domain.Wallet
+	err     error
+}
+
+func discoverAccountsCmd(discovery *usecases.AccountDiscovery, wallets *usecases.WalletService, mnemonic string) tea.Cmd {
+	return func() tea.Msg {
+		registered, err := wallets.GetAllWallets()
+		if err != nil {
+			return accountsDiscoveredMsg{err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		accounts, err := discovery.Discover(ctx, mnemonic, registered)
+		return accountsDiscoveredMsg{accounts: accounts, err: err}
+	}
+}
+
+func registerAccountsCmd(wallets *usecases.WalletService, mnemonic, password string, indices []uint32) tea.Cmd {
+	return func() tea.Msg {
+		imported, err := wallets.ImportDerivedWallets(mnemonic, password, indices)
+		return accountsRegisteredMsg{wallets: imported, err: err}
+	}
+}
+
+// initDiscovery procura contas usadas da frase mnemônica recém-importada
+func (m *CLIModel) initDiscovery(mnemonic, password string) tea.Cmd {
+	m.discoveryMnemonic = mnemonic
+	m.discoveryPassword = password
+	m.discoveryAccounts = nil
+	m.discoverySelected = map[int]bool{}
+	m.discoveryErr = nil
+	m.discoveryMessage = ""
+	m.discoveryBusy = false
+	m.discoveryLoading = true
+	m.discoveryTable = newDataTable(m.discoveryColumns(), nil, m.tableHeight())
+	m.currentView = constants.DiscoveryView
+	return discoverAccountsCmd(m.Discovery, m.Service, mnemonic)
+}
+
+// discoveryBack descarta a frase e a senha e segue para os detalhes da wallet importada
+func (m *CLIModel) discoveryBack() {
+	if m.discoveryBusy {
+		return
+	}
+	m.discoveryMnemonic = ""
+	m.discoveryPassword = ""
+	m.currentView = constants.WalletDetailsView
+}
+
+func (m *CLIModel) discoveryColumns() []table.Column {
+	return []table.Column{
+		{Title: "", Width: 3},
+		{Title: localization.Labels["discovery_path"], Width: 18},
+		{Title: localization.Labels["ethereum_address"], Width: 44},
+		{Title: localization.Labels["discovery_nonce"], Width: 8},
+		{Title: localization.Labels["discovery_balance"], Width: 24},
+		{Title: localization.Labels["discovery_status"], Width: 14},
+	}
+}
+
+func (m *CLIModel) refreshDiscoveryRows() {
+	rows := make([]table.Row, 0, len(m.discoveryAccounts))
+	for i, account := range m.discoveryAccounts {
+		selected := "[ ]"
+		if m.discoverySelected[i] {
+			selected = "[x]"
+		}
+		status := ""
+		if account.Registered {
+			selected = "   "
+			status = localization.Labels["discovery_registered"]
+		}
+		rows = append(rows, table.Row{
+			selected,
+			fmt.Sprintf("m/44'/60'/0'/0/%d", account.Index),
+			account.Address,
+			strconv.FormatUint(account.Nonce, 10),
+			usecases.FormatUnits(account.Balance, constants.NativeDecimals) + " " + constants.DefaultNativeSymbol,
+			status,
+		})
+	}
+	m.discoveryTable.SetRows(rows)
+}
+
+// pendingDiscovery indica se alguma conta encontrada ainda não foi registrada
+func (m *CLIModel) pendingDiscovery() bool {
+	for _, account := range m.discoveryAccounts {
+		if !account.Registered {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *CLIModel) applyDiscoveryMsg(msg tea.Msg) tea.Cmd {
+	if m.currentView != constants.DiscoveryView {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case accountsDiscoveredMsg:
+		m.discoveryLoading = false
+		m.discoveryErr = msg.err
+		if msg.err != nil {
+			log.Println("Erro ao descobrir as contas:", msg.err)
+			return nil
+		}
+		m.discoveryAccounts = msg.accounts
+		if !m.pendingDiscovery() {
+			// Nenhuma conta adicional: seguir direto para os detalhes
+			m.discoveryBack()
+			return nil
+		}
+		m.discoverySelected = map[int]bool{}
+		for i, account := range m.discoveryAccounts {
+			if !account.Registered {
+				m.discoverySelected[i] = true
+			}
+		}
+		m.refreshDiscoveryRows()
+		m.discoveryTable.GotoTop()
+	case accountsRegisteredMsg:
+		m.discoveryBusy = false
+		registered := make(map[string]bool, len(msg.wallets))
+		for _, w := range msg.wallets {
+			registered[w.Address] = true
+		}
+		for i := range m.discoveryAccounts {
+			if registered[m.discoveryAccounts[i].Address] {
+				m.discoveryAccounts[i].Registered = true
+				delete(m.discoverySelected, i)
+			}
+		}
+		m.refreshDiscoveryRows()
+		if msg.err != nil {
+			log.Println("Erro ao registrar as contas:", msg.err)
+			m.discoveryMessage = fmt.Sprintf(localization.Labels["discovery_register_error"], msg.err)
+		} else {
+			m.discoveryMessage = fmt.Sprintf(localization.Labels["discovery_registered_count"], len(msg.wallets))
+		}
+		return m.refreshWalletsTable()
+	}
+	return nil
+}
+
+func (m *CLIModel) updateDiscovery(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && (m.discoveryBusy || m.discoveryLoading) {
+		return m, nil
+	}
+
+	if ok {
+		switch keyMsg.String() {
+		case " ":
+			cursor := m.discoveryTable.Cursor()
+			if cursor < len(m.discoveryAccounts) && !m.discoveryAccounts[cursor].Registered {
+				m.discoverySelected[cursor] = !m.discoverySelected[cursor]
+				if !m.discoverySelected[cursor] {
+					delete(m.discoverySelected, cursor)
+				}
+				m.refreshDiscoveryRows()
+			}
+			return m, nil
+		case "A":
+			for i, account := range m.discoveryAccounts {
+				if !account.Registered {
+					m.discoverySelected[i] = true
+				}
+			}
+			m.refreshDiscoveryRows()
+			return m, nil
+		case "enter", "s":
+			var indices []uint32
+			for i, account := range m.discoveryAccounts {
+				if m.discoverySelected[i] {
+					indices = append(indices, account.Index)
+				}
+			}
+			if len(indices) == 0 {
+				m.discoveryMessage = localization.Labels["discovery_none_selected"]
+				return m, nil
+			}
+			m.discoveryBusy = true
+			m.discoveryMessage = localization.Labels["discovery_registering"]
+			return m, registerAccountsCmd(m.Service, m.discoveryMnemonic, m.discoveryPassword, indices)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.discoveryTable, cmd = m.discoveryTable.Update(msg)
+	return m, cmd
+}
+
+// viewDiscovery renderiza as contas encontradas para registro em lote
+func (m *CLIModel) viewDiscovery() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Render(localization.Labels["discovery_title"])
+
+	var body string
+	switch {
+	case m.discoveryLoading:
+		body = fmt.Sprintf(localization.Labels["discovery_loading"], m.Discovery.GapLimit)
+	case m.discoveryErr != nil:
+		body = m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["discovery_error"], m.discoveryErr))
+	default:
+		body = m.discoveryTable.View()
+	}
+	parts := []string{title, "", body}
+	if m.discoveryMessage != "" {
+		parts = append(parts, "", m.discoveryMessage)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
@@ -1,10 +1,15 @@
 package interfaces
 
 import (
+	"blocowallet/domain"
 	"blocowallet/usecases"
+	"context"
 	tea "github.com/charmbracelet/bubbletea"
+	"time"
 )
 
+const fetchTimeout = 30 * time.Second
+
 // Define uma mensagem que contém a contagem de wallets
 type walletCountMsg struct {
 	count int
@@ -20,4 +25,20 @@ func walletCountCmd(service *usecases.WalletService) tea.Cmd {
 		}
 		return walletCountMsg{count: len(wallets)}
 	}
-}
\ No newline at end of file
+}
+
+// Mensagem com uma página do histórico de transações
+type historyLoadedMsg struct {
+	page *usecases.HistoryPage
+	err  error
+}
+
+// Comando para buscar uma página do histórico sem bloquear a interface
+func fetchHistoryCmd(service *usecases.HistoryService, address string, kind domain.HistoryKind, page int, refresh bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		result, err := service.GetHistory(ctx, address, kind, page, refresh)
+		return historyLoadedMsg{page: result, err: err}
+	}
+}
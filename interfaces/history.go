@@ -0,0 +1,196 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// initHistory abre o histórico de transações da wallet selecionada
+func (m *CLIModel) initHistory(wallet *domain.Wallet) tea.Cmd {
+	m.historyWallet = wallet
+	m.historyKindIndex = 0
+	m.historyPageNumber = 1
+	m.historyPage = nil
+	m.historyErr = nil
+	m.historyTable = newDataTable(m.historyColumns(), nil, m.tableHeight())
+	m.currentView = constants.HistoryView
+	return m.loadHistory(false)
+}
+
+func (m *CLIModel) loadHistory(refresh bool) tea.Cmd {
+	m.historyLoading = true
+	m.historyErr = nil
+	kind := domain.HistoryKinds[m.historyKindIndex]
+	return fetchHistoryCmd(m.History, m.historyWallet.Address, kind, m.historyPageNumber, refresh)
+}
+
+func (m *CLIModel) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab":
+			m.historyKindIndex = (m.historyKindIndex + 1) % len(domain.HistoryKinds)
+			m.historyPageNumber = 1
+			return m, m.loadHistory(false)
+		case "shift+tab":
+			m.historyKindIndex = (m.historyKindIndex + len(domain.HistoryKinds) - 1) % len(domain.HistoryKinds)
+			m.historyPageNumber = 1
+			return m, m.loadHistory(false)
+		case "n", "right":
+			if m.historyPage != nil && m.historyPage.HasMore && !m.historyLoading {
+				m.historyPageNumber++
+				return m, m.loadHistory(false)
+			}
+			return m, nil
+		case "p", "left":
+			if m.historyPageNumber > 1 && !m.historyLoading {
+				m.historyPageNumber--
+				return m, m.loadHistory(false)
+			}
+			return m, nil
+		case "r":
+			return m, m.loadHistory(true)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.historyTable, cmd = m.historyTable.Update(msg)
+	return m, cmd
+}
+
+// applyHistoryPage atualiza a tabela quando a página solicitada chega
+func (m *CLIModel) applyHistoryPage(msg historyLoadedMsg) {
+	if m.historyWallet == nil {
+		return
+	}
+	// Ignorar respostas de páginas ou tipos que não estão mais selecionados
+	if msg.page != nil && (msg.page.Kind != domain.HistoryKinds[m.historyKindIndex] || msg.page.Page != m.historyPageNumber) {
+		return
+	}
+
+	m.historyLoading = false
+	m.historyErr = msg.err
+	m.historyPage = msg.page
+	if msg.err != nil {
+		m.historyTable.SetRows(nil)
+		return
+	}
+
+	rows := make([]table.Row, 0, len(msg.page.Entries))
+	for _, entry := range msg.page.Entries {
+		rows = append(rows, m.historyRow(entry))
+	}
+	m.historyTable.SetRows(rows)
+	m.historyTable.GotoTop()
+}
+
+func (m *CLIModel) historyColumns() []table.Column {
+	counterpartyWidth := 16
+	amountWidth := m.width - 16 - 16 - 6 - counterpartyWidth - 8 - 20
+	if amountWidth < 20 {
+		amountWidth = 20
+	}
+	return []table.Column{
+		{Title: localization.Labels["history_date"], Width: 16},
+		{Title: localization.Labels["history_hash"], Width: 16},
+		{Title: localization.Labels["history_direction"], Width: 6},
+		{Title: localization.Labels["history_counterparty"], Width: counterpartyWidth},
+		{Title: localization.Labels["history_amount"], Width: amountWidth},
+		{Title: localization.Labels["history_status"], Width: 8},
+	}
+}
+
+func (m *CLIModel) historyRow(entry domain.HistoryEntry) table.Row {
+	own := strings.ToLower(m.historyWallet.Address)
+	from := strings.ToLower(entry.From)
+	to := strings.ToLower(entry.To)
+
+	direction := localization.Labels["history_out"]
+	counterparty := entry.To
+	switch {
+	case from == own && to == own:
+		direction = localization.Labels["history_self"]
+	case to == own:
+		direction = localization.Labels["history_in"]
+		counterparty = entry.From
+	}
+	if counterparty == "" {
+		// Criação de contrato não possui destinatário
+		counterparty = entry.ContractAddress
+	}
+
+	symbol := constants.DefaultNativeSymbol
+	decimals := constants.NativeDecimals
+	if entry.Kind == domain.HistoryToken {
+		symbol = entry.TokenSymbol
+		decimals = entry.TokenDecimals
+	}
+
+	status := localization.Labels["history_ok"]
+	if entry.Failed {
+		status = localization.Labels["history_failed"]
+	}
+
+	return table.Row{
+		entry.Timestamp.Format("02-01-2006 15:04"),
+		shortHex(entry.Hash),
+		direction,
+		shortHex(counterparty),
+		fmt.Sprintf("%s %s", usecases.FormatUnitsString(entry.Value, decimals), symbol),
+		status,
+	}
+}
+
+// viewHistory renderiza a tabela de histórico com as abas de tipo de transferência
+func (m *CLIModel) viewHistory() string {
+	if m.historyWallet == nil {
+		return localization.Labels["select_wallet_prompt"]
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Render(fmt.Sprintf(localization.Labels["history_title"], m.historyWallet.Address))
+
+	var tabs []string
+	for i, kind := range domain.HistoryKinds {
+		label := localization.Labels["history_kind_"+string(kind)]
+		if i == m.historyKindIndex {
+			tabs = append(tabs, m.styles.DialogButtonActive.Render(label))
+		} else {
+			tabs = append(tabs, m.styles.DialogButton.Render(label))
+		}
+	}
+	tabBar := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+
+	var body string
+	switch {
+	case m.historyLoading:
+		body = localization.Labels["history_loading"]
+	case m.historyErr != nil:
+		body = m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["history_error"], m.historyErr))
+	case m.historyPage == nil || len(m.historyPage.Entries) == 0:
+		body = localization.Labels["history_empty"]
+	default:
+		body = m.historyTable.View()
+	}
+
+	var info string
+	if m.historyPage != nil && !m.historyLoading {
+		info = fmt.Sprintf(localization.Labels["history_page_info"], m.historyPage.Page, m.historyPage.FetchedAt.Format("02-01-2006 15:04:05"))
+		if m.historyPage.Stale {
+			info += " " + localization.Labels["history_stale"]
+		}
+		info = lipgloss.NewStyle().Foreground(lipgloss.Color("#5C5C5C")).Render(info)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, tabBar, body, info)
+}
@@ -0,0 +1,99 @@
+package interfaces
+
+import (
+	"blocowallet/localization"
+	"fmt"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openSelectedWallet abre a wallet com a senha do chaveiro quando ela optou por
+// isso; sem chaveiro disponível ou com a senha recusada, volta a pedir a senha
+func (m *CLIModel) openSelectedWallet() tea.Cmd {
+	m.keyringNotice = ""
+	if walletDetails, ok := m.Service.CachedWallet(m.selectedWallet); ok {
+		m.showWalletDetails(walletDetails)
+		return nil
+	}
+	if m.selectedWallet.Keyring {
+		walletDetails, err := m.Service.UnlockWithKeyring(m.selectedWallet)
+		if err == nil {
+			m.showWalletDetails(walletDetails)
+			return nil
+		}
+		log.Printf("Senha do chaveiro indisponível para %s: %v\n", m.selectedWallet.Address, err)
+		m.keyringNotice = fmt.Sprintf(localization.Labels["keyring_unlock_failed"], err)
+	}
+	return m.initWalletPassword()
+}
+
+// syncKeyring guarda ou esquece a senha conforme a opção marcada na tela de
+// senha e informa se a wallet mudou
+func (m *CLIModel) syncKeyring(password string) bool {
+	if !m.keyringAvailable {
+		return false
+	}
+	var err error
+	switch {
+	case m.rememberPassword:
+		err = m.Service.RememberPassword(m.selectedWallet, password)
+		m.keyringNotice = localization.Labels["keyring_saved"]
+	case m.selectedWallet.Keyring:
+		err = m.Service.ForgetPassword(m.selectedWallet)
+		m.keyringNotice = localization.Labels["keyring_forgotten"]
+	default:
+		return false
+	}
+	if err != nil {
+		log.Printf("Erro ao atualizar o chaveiro: %v\n", err)
+		m.keyringNotice = fmt.Sprintf(localization.Labels["keyring_error"], err)
+	}
+	return true
+}
+
+// forgetKeyringPassword remove a senha da wallet aberta do chaveiro
+func (m *CLIModel) forgetKeyringPassword() tea.Cmd {
+	if err := m.Service.ForgetPassword(m.walletDetails.Wallet); err != nil {
+		log.Printf("Erro ao remover a senha do chaveiro: %v\n", err)
+		m.keyringNotice = fmt.Sprintf(localization.Labels["keyring_error"], err)
+		return nil
+	}
+	m.keyringNotice = localization.Labels["keyring_forgotten"]
+	return m.refreshWalletsTable()
+}
+
+// renderRememberPassword mostra a opção de guardar a senha no chaveiro
+func (m *CLIModel) renderRememberPassword() string {
+	if !m.keyringAvailable {
+		return ""
+	}
+	mark := " "
+	if m.rememberPassword {
+		mark = "x"
+	}
+	return fmt.Sprintf(localization.Labels["keyring_remember"], mark) + "\n\n"
+}
+
+// keyringPromptNotice explica por que a senha foi pedida apesar do chaveiro
+func (m *CLIModel) keyringPromptNotice() string {
+	if m.keyringNotice == "" {
+		return ""
+	}
+	return m.keyringNotice + "\n\n"
+}
+
+// renderKeyringStatus mostra o aviso do chaveiro nos detalhes da wallet
+func (m *CLIModel) renderKeyringStatus() string {
+	var status string
+	if m.keyringNotice != "" {
+		status += m.keyringNotice + "\n"
+	}
+	if m.walletDetails.Wallet.Keyring {
+		status += localization.Labels["keyring_forget_hint"] + "\n"
+	}
+	if status == "" {
+		return ""
+	}
+	return status + "\n"
+}
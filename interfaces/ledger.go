@@ -0,0 +1,265 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-errors/errors"
+)
+
+const ledgerDateLayout = "2006-01-02"
+
+// initLedger abre o livro-razão local sem filtros
+func (m *CLIModel) initLedger() {
+	wallets, err := m.Service.GetAllWallets()
+	if err != nil {
+		m.err = errors.Wrap(fmt.Errorf(localization.Labels["error_loading_wallets"], err), 0)
+		log.Println(m.err.(*errors.Error).ErrorStack())
+		m.currentView = constants.DefaultView
+		return
+	}
+	m.wallets = wallets
+	m.ledgerWalletIndex = -1
+	m.ledgerStatusIndex = -1
+	m.ledgerEditing = false
+	m.ledgerMessage = ""
+	m.ledgerDateInputs = make([]textinput.Model, 2)
+	for i, placeholder := range []string{localization.Labels["ledger_since"], localization.Labels["ledger_until"]} {
+		ti := textinput.New()
+		ti.Placeholder = placeholder + " (" + ledgerDateLayout + ")"
+		ti.CharLimit = len(ledgerDateLayout)
+		ti.Width = 24
+		m.ledgerDateInputs[i] = ti
+	}
+	m.ledgerTable = newDataTable(m.ledgerColumns(), nil, m.tableHeight())
+	m.currentView = constants.LedgerView
+	m.reloadLedger()
+}
+
+// ledgerFilter monta o filtro a partir das seleções atuais
+func (m *CLIModel) ledgerFilter() (domain.LedgerFilter, error) {
+	var filter domain.LedgerFilter
+	if m.ledgerWalletIndex >= 0 && m.ledgerWalletIndex < len(m.wallets) {
+		filter.Address = m.wallets[m.ledgerWalletIndex].Address
+	}
+	if m.ledgerStatusIndex >= 0 {
+		filter.Status = domain.LedgerStatuses[m.ledgerStatusIndex]
+	}
+	if value := strings.TrimSpace(m.ledgerDateInputs[0].Value()); value != "" {
+		since, err := time.ParseInLocation(ledgerDateLayout, value, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf(localization.Labels["ledger_invalid_date"], value)
+		}
+		filter.Since = since
+	}
+	if value := strings.TrimSpace(m.ledgerDateInputs[1].Value()); value != "" {
+		until, err := time.ParseInLocation(ledgerDateLayout, value, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf(localization.Labels["ledger_invalid_date"], value)
+		}
+		// A data final é inclusiva
+		filter.Until = until.Add(24*time.Hour - time.Second)
+	}
+	return filter, nil
+}
+
+func (m *CLIModel) reloadLedger() {
+	filter, err := m.ledgerFilter()
+	if err != nil {
+		m.ledgerMessage = err.Error()
+		return
+	}
+	entries, err := m.Ledger.GetEntries(filter)
+	if err != nil {
+		log.Println("Erro ao carregar o livro-razão:", err)
+		m.ledgerMessage = fmt.Sprintf(localization.Labels["ledger_error"], err)
+		return
+	}
+
+	rows := make([]table.Row, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, table.Row{
+			e.Timestamp.Format("02-01-2006 15:04"),
+			shortHex(e.Hash),
+			shortHex(e.From),
+			shortHex(e.To),
+			usecases.FormatUnitsString(e.Value, constants.NativeDecimals),
+			string(e.Status),
+			e.Memo,
+		})
+	}
+	m.ledgerEntries = entries
+	m.ledgerTable.SetRows(rows)
+	m.ledgerTable.GotoTop()
+}
+
+func (m *CLIModel) updateLedger(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && m.ledgerEditing {
+		switch keyMsg.String() {
+		case "esc":
+			m.ledgerEditing = false
+			m.ledgerDateInputs[m.ledgerDateFocus].Blur()
+			return m, nil
+		case "tab", "shift+tab", "up", "down":
+			m.ledgerDateInputs[m.ledgerDateFocus].Blur()
+			m.ledgerDateFocus = (m.ledgerDateFocus + 1) % len(m.ledgerDateInputs)
+			return m, m.ledgerDateInputs[m.ledgerDateFocus].Focus()
+		case "enter":
+			m.ledgerEditing = false
+			m.ledgerDateInputs[m.ledgerDateFocus].Blur()
+			m.ledgerMessage = ""
+			m.reloadLedger()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.ledgerDateInputs[m.ledgerDateFocus], cmd = m.ledgerDateInputs[m.ledgerDateFocus].Update(msg)
+		return m, cmd
+	}
+
+	if ok {
+		switch keyMsg.String() {
+		case "w":
+			// Alterna entre todas as wallets e cada wallet individualmente
+			m.ledgerWalletIndex++
+			if m.ledgerWalletIndex >= len(m.wallets) {
+				m.ledgerWalletIndex = -1
+			}
+			m.reloadLedger()
+			return m, nil
+		case "s":
+			m.ledgerStatusIndex++
+			if m.ledgerStatusIndex >= len(domain.LedgerStatuses) {
+				m.ledgerStatusIndex = -1
+			}
+			m.reloadLedger()
+			return m, nil
+		case "f":
+			m.ledgerEditing = true
+			m.ledgerDateFocus = 0
+			return m, m.ledgerDateInputs[0].Focus()
+		case "x":
+			for i := range m.ledgerDateInputs {
+				m.ledgerDateInputs[i].SetValue("")
+			}
+			m.ledgerWalletIndex = -1
+			m.ledgerStatusIndex = -1
+			m.ledgerMessage = ""
+			m.reloadLedger()
+			return m, nil
+		case "c":
+			return m, m.confirmLedgerExport(usecases.ExportCSV)
+		case "J":
+			return m, m.confirmLedgerExport(usecases.ExportJSON)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.ledgerTable, cmd = m.ledgerTable.Update(msg)
+	return m, cmd
+}
+
+// confirmLedgerExport pede os códigos de dois fatores das wallets exportadas:
+// a wallet filtrada ou, sem filtro, todas
+func (m *CLIModel) confirmLedgerExport(format usecases.ExportFormat) tea.Cmd {
+	var targets []twoFactorTarget
+	if m.ledgerWalletIndex >= 0 && m.ledgerWalletIndex < len(m.wallets) {
+		targets = append(targets, twoFactorTarget{wallet: m.wallets[m.ledgerWalletIndex]})
+	} else {
+		wallets, err := m.Service.GetAllWallets()
+		if err != nil {
+			log.Println("Erro ao buscar as wallets:", err)
+			m.ledgerMessage = fmt.Sprintf(localization.Labels["ledger_error"], err)
+			return nil
+		}
+		for _, wallet := range wallets {
+			targets = append(targets, twoFactorTarget{wallet: wallet})
+		}
+	}
+	return m.requireTwoFactor(domain.TwoFactorExport, targets, func() tea.Cmd {
+		m.exportLedger(format)
+		return nil
+	})
+}
+
+func (m *CLIModel) exportLedger(format usecases.ExportFormat) {
+	filter, err := m.ledgerFilter()
+	if err != nil {
+		m.ledgerMessage = err.Error()
+		return
+	}
+	path, err := m.Ledger.Export(filter, format)
+	if err != nil {
+		log.Println("Erro ao exportar o livro-razão:", err)
+		m.ledgerMessage = fmt.Sprintf(localization.Labels["ledger_error"], err)
+		return
+	}
+	m.ledgerMessage = fmt.Sprintf(localization.Labels["ledger_exported"], path)
+}
+
+func (m *CLIModel) ledgerColumns() []table.Column {
+	memoWidth := m.width - 16 - 16 - 16 - 16 - 20 - 10 - 20
+	if memoWidth < 12 {
+		memoWidth = 12
+	}
+	return []table.Column{
+		{Title: localization.Labels["history_date"], Width: 16},
+		{Title: localization.Labels["history_hash"], Width: 16},
+		{Title: localization.Labels["ledger_from"], Width: 16},
+		{Title: localization.Labels["ledger_to"], Width: 16},
+		{Title: localization.Labels["history_amount"], Width: 20},
+		{Title: localization.Labels["history_status"], Width: 10},
+		{Title: localization.Labels["ledger_memo"], Width: memoWidth},
+	}
+}
+
+// viewLedger renderiza os filtros, a tabela e o resultado da última exportação
+func (m *CLIModel) viewLedger() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Render(localization.Labels["ledger_title"])
+
+	walletFilter := localization.Labels["ledger_all"]
+	if m.ledgerWalletIndex >= 0 && m.ledgerWalletIndex < len(m.wallets) {
+		walletFilter = m.wallets[m.ledgerWalletIndex].Address
+	}
+	statusFilter := localization.Labels["ledger_all"]
+	if m.ledgerStatusIndex >= 0 {
+		statusFilter = string(domain.LedgerStatuses[m.ledgerStatusIndex])
+	}
+	filters := fmt.Sprintf(localization.Labels["ledger_filters"], walletFilter, statusFilter)
+
+	var dates string
+	if m.ledgerEditing {
+		dates = lipgloss.JoinHorizontal(lipgloss.Top, m.ledgerDateInputs[0].View(), "  ", m.ledgerDateInputs[1].View())
+	} else {
+		since, until := m.ledgerDateInputs[0].Value(), m.ledgerDateInputs[1].Value()
+		if since == "" {
+			since = "…"
+		}
+		if until == "" {
+			until = "…"
+		}
+		dates = fmt.Sprintf(localization.Labels["ledger_period"], since, until)
+	}
+
+	body := m.ledgerTable.View()
+	if len(m.ledgerEntries) == 0 {
+		body = localization.Labels["ledger_empty"]
+	}
+
+	message := lipgloss.NewStyle().Foreground(lipgloss.Color("#5C5C5C")).Render(m.ledgerMessage)
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, filters, dates, "", body, message)
+}
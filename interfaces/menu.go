@@ -31,10 +31,31 @@ func NewMenu() []menuItem {
 		{title: localization.Labels["create_new_wallet"], description: localization.Labels["create_new_wallet_desc"]},
 		{title: localization.Labels["import_wallet"], description: localization.Labels["import_wallet_desc"]},
 		{title: localization.Labels["list_wallets"], description: localization.Labels["list_wallets_desc"]},
+		{title: localization.Labels["ledger"], description: localization.Labels["ledger_desc"]},
 		{title: localization.Labels["exit"], description: localization.Labels["exit_desc"]},
 	}
 }
 
+// NewDevMenu acrescenta ao menu principal a mineração de blocos da cadeia local
+func NewDevMenu() []menuItem {
+	items := NewMenu()
+	exit := items[len(items)-1]
+	items = append(items[:len(items)-1],
+		menuItem{title: localization.Labels["dev_mine_block"], description: localization.Labels["dev_mine_block_desc"]},
+		exit,
+	)
+	return items
+}
+
+// withMasterPasswordItem acrescenta a troca da senha mestra antes da saída
+func withMasterPasswordItem(items []menuItem) []menuItem {
+	exit := items[len(items)-1]
+	return append(items[:len(items)-1],
+		menuItem{title: localization.Labels["master_password"], description: localization.Labels["master_password_desc"]},
+		exit,
+	)
+}
+
 // NewImportMenu cria e retorna uma lista de itens do menu de importação
 func NewImportMenu() []menuItem {
 	return []menuItem{
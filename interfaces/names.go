@@ -0,0 +1,119 @@
+package interfaces
+
+import (
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Mensagem com o endereço resolvido de um nome digitado no campo de destinatário
+type recipientResolvedMsg struct {
+	name    string
+	address common.Address
+	err     error
+	next    func(address common.Address, label string) tea.Cmd
+}
+
+// Mensagem com os nomes primários verificados das wallets
+type walletNamesMsg struct {
+	names map[string]string
+}
+
+func resolveRecipientCmd(names domain.NameResolver, name string, next func(common.Address, string) tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		address, err := names.Resolve(ctx, name)
+		return recipientResolvedMsg{name: name, address: common.HexToAddress(address), err: err, next: next}
+	}
+}
+
+func lookupWalletNamesCmd(names domain.NameResolver, wallets []domain.Wallet) tea.Cmd {
+	addresses := make([]string, 0, len(wallets))
+	for _, w := range wallets {
+		addresses = append(addresses, w.Address)
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		result := make(map[string]string)
+		for _, address := range addresses {
+			name, err := names.LookupAddress(ctx, address)
+			if err != nil {
+				// Uma falha não impede a resolução das demais wallets
+				continue
+			}
+			if name != "" {
+				result[strings.ToLower(address)] = name
+			}
+		}
+		return walletNamesMsg{names: result}
+	}
+}
+
+// isNameInput indica se o texto digitado parece um nome ENS em vez de um endereço
+func isNameInput(input string) bool {
+	return strings.Contains(input, ".") && !strings.HasPrefix(input, "0x") && !strings.ContainsAny(input, " /")
+}
+
+// withRecipient valida o destinatário digitado. Endereços seguem direto para next;
+// nomes são resolvidos de forma assíncrona antes de continuar.
+func (m *CLIModel) withRecipient(input string, next func(address common.Address, label string) tea.Cmd) tea.Cmd {
+	input = strings.TrimSpace(input)
+	if common.IsHexAddress(input) {
+		return next(common.HexToAddress(input), input)
+	}
+	if m.Names == nil || !isNameInput(input) {
+		m.txNotice = localization.Labels["invalid_address"]
+		return nil
+	}
+	m.txNotice = fmt.Sprintf(localization.Labels["ens_resolving"], input)
+	return resolveRecipientCmd(m.Names, input, next)
+}
+
+func (m *CLIModel) applyRecipientResolved(msg recipientResolvedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.txNotice = fmt.Sprintf(localization.Labels["ens_resolve_error"], msg.name, msg.err)
+		return nil
+	}
+	return msg.next(msg.address, fmt.Sprintf("%s (%s)", msg.name, msg.address.Hex()))
+}
+
+// lookupWalletNames busca os nomes primários das wallets listadas
+func (m *CLIModel) lookupWalletNames() tea.Cmd {
+	if m.Names == nil || len(m.wallets) == 0 {
+		return nil
+	}
+	return lookupWalletNamesCmd(m.Names, m.wallets)
+}
+
+func (m *CLIModel) applyWalletNames(msg walletNamesMsg) {
+	m.walletNames = msg.names
+	if len(m.wallets) > 0 {
+		rows := make([]table.Row, 0, len(m.wallets))
+		for _, w := range m.wallets {
+			rows = append(rows, m.walletRow(w))
+		}
+		m.walletTable.SetRows(rows)
+	}
+}
+
+// walletAddressLabel acrescenta o nome primário verificado ao endereço, quando existir
+func (m *CLIModel) walletAddressLabel(address string) string {
+	if name, ok := m.walletNames[strings.ToLower(address)]; ok {
+		return fmt.Sprintf("%s  %s", address, name)
+	}
+	return address
+}
+
+// walletRowAddress extrai o endereço da coluna que também pode exibir o nome ENS
+func walletRowAddress(row table.Row) string {
+	address, _, _ := strings.Cut(row[1], " ")
+	return address
+}
@@ -0,0 +1,153 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type nftInventoryMsg struct {
+	items []domain.NFT
+	err   error
+}
+
+func fetchNFTsCmd(service *usecases.NFTService, owner common.Address) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		items, err := service.Inventory(ctx, owner)
+		return nftInventoryMsg{items: items, err: err}
+	}
+}
+
+// initNFTs abre a sub-visualização de NFTs da wallet desbloqueada
+func (m *CLIModel) initNFTs() tea.Cmd {
+	m.nftItems = nil
+	m.nftErr = nil
+	m.txNotice = ""
+	m.nftEditing = false
+	m.nftLoading = true
+	m.nftTable = newDataTable(m.nftColumns(), nil, m.tableHeight())
+	m.currentView = constants.NFTView
+	return fetchNFTsCmd(m.NFTs, common.HexToAddress(m.walletDetails.Wallet.Address))
+}
+
+func (m *CLIModel) applyNFTInventory(msg nftInventoryMsg) {
+	m.nftLoading = false
+	m.nftErr = msg.err
+	m.nftItems = msg.items
+	rows := make([]table.Row, 0, len(msg.items))
+	for _, nft := range msg.items {
+		collection := nft.Collection
+		if collection == "" {
+			collection = shortHex(nft.Contract)
+		}
+		rows = append(rows, table.Row{collection, string(nft.Standard), nft.TokenID, nft.Balance, nft.MetadataURI})
+	}
+	m.nftTable.SetRows(rows)
+	m.nftTable.GotoTop()
+}
+
+func (m *CLIModel) updateNFTs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && m.nftEditing {
+		switch keyMsg.String() {
+		case "esc":
+			m.nftEditing = false
+			return m, nil
+		case "enter":
+			return m, m.buildNFTTransfer()
+		}
+		var cmd tea.Cmd
+		m.nftRecipient, cmd = m.nftRecipient.Update(msg)
+		return m, cmd
+	}
+
+	if ok {
+		switch keyMsg.String() {
+		case "r":
+			return m, m.initNFTs()
+		case "t":
+			if len(m.nftItems) > 0 && m.Transactions != nil {
+				m.nftEditing = true
+				m.txNotice = ""
+				m.nftRecipient = newAddressInput(localization.Labels["nft_recipient"])
+				return m, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.nftTable, cmd = m.nftTable.Update(msg)
+	return m, cmd
+}
+
+// buildNFTTransfer codifica safeTransferFrom para o token selecionado e segue para a revisão
+func (m *CLIModel) buildNFTTransfer() tea.Cmd {
+	nft := m.nftItems[m.nftTable.Cursor()]
+	from := common.HexToAddress(m.walletDetails.Wallet.Address)
+	return m.withRecipient(m.nftRecipient.Value(), func(recipient common.Address, label string) tea.Cmd {
+		data, err := m.NFTs.TransferCalldata(nft, from, recipient, nil)
+		if err != nil {
+			m.txNotice = fmt.Sprintf(localization.Labels["tx_error"], err)
+			return nil
+		}
+
+		m.nftEditing = false
+		m.txNotice = localization.Labels["tx_building"]
+		m.reviewMemo = fmt.Sprintf("%s #%s → %s", nft.Standard, nft.TokenID, label)
+		contract := common.HexToAddress(nft.Contract)
+		return buildTxCmd(m.Transactions, from, &contract, nil, data)
+	})
+}
+
+func (m *CLIModel) nftColumns() []table.Column {
+	uriWidth := m.width - 20 - 10 - 20 - 8 - 20
+	if uriWidth < 20 {
+		uriWidth = 20
+	}
+	return []table.Column{
+		{Title: localization.Labels["nft_collection"], Width: 20},
+		{Title: localization.Labels["nft_standard"], Width: 10},
+		{Title: localization.Labels["nft_token_id"], Width: 20},
+		{Title: localization.Labels["nft_balance"], Width: 8},
+		{Title: localization.Labels["nft_metadata"], Width: uriWidth},
+	}
+}
+
+// viewNFTs renderiza os NFTs da wallet e o campo de destinatário durante a transferência
+func (m *CLIModel) viewNFTs() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Render(fmt.Sprintf(localization.Labels["nft_title"], m.walletDetails.Wallet.Address))
+
+	var body string
+	switch {
+	case m.nftLoading:
+		body = localization.Labels["nft_loading"]
+	case m.nftErr != nil:
+		body = m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["nft_error"], m.nftErr))
+	case len(m.nftItems) == 0:
+		body = localization.Labels["nft_empty"]
+	default:
+		body = m.nftTable.View()
+	}
+
+	parts := []string{title, "", body}
+	if m.nftEditing {
+		parts = append(parts, "", localization.Labels["nft_recipient"], m.nftRecipient.View())
+	}
+	if m.txNotice != "" {
+		parts = append(parts, "", m.txNotice)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
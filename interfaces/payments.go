@@ -0,0 +1,333 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Etapas do pagamento em lote
+const (
+	paymentStepFile = iota
+	paymentStepReview
+	paymentStepSend
+)
+
+type paymentsLoadedMsg struct {
+	payments []usecases.Payment
+	totals   []usecases.AssetTotal
+	err      error
+}
+
+type paymentsSignedMsg struct {
+	batch *usecases.PaymentBatch
+	err   error
+}
+
+type paymentSentMsg struct {
+	index int
+	err   error
+}
+
+func loadPaymentsCmd(service *usecases.PaymentService, path string, from common.Address) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sweepTimeout)
+		defer cancel()
+		payments, err := service.LoadPayments(ctx, path)
+		if err != nil {
+			return paymentsLoadedMsg{err: err}
+		}
+		totals, err := service.Totals(ctx, from, payments)
+		return paymentsLoadedMsg{payments: payments, totals: totals, err: err}
+	}
+}
+
+// buildPaymentsCmd monta e assina todas as transações antes de qualquer transmissão
+func buildPaymentsCmd(service *usecases.PaymentService, details *usecases.WalletDetails, payments []usecases.Payment, disperse bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sweepTimeout)
+		defer cancel()
+		from := common.HexToAddress(details.Wallet.Address)
+		batch, err := service.Build(ctx, from, payments, disperse)
+		if err != nil {
+			return paymentsSignedMsg{err: err}
+		}
+		if err := service.Sign(ctx, details, batch, payments); err != nil {
+			return paymentsSignedMsg{err: err}
+		}
+		return paymentsSignedMsg{batch: batch}
+	}
+}
+
+func sendPaymentCmd(service *usecases.PaymentService, tx *types.Transaction, index int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		return paymentSentMsg{index: index, err: service.Broadcast(ctx, tx)}
+	}
+}
+
+// initPayments abre o pagamento em lote a partir da wallet desbloqueada
+func (m *CLIModel) initPayments() {
+	m.paymentPath = textinput.New()
+	m.paymentPath.Placeholder = localization.Labels["payment_file_placeholder"]
+	m.paymentPath.CharLimit = 512
+	m.paymentPath.Width = 60
+	m.paymentPath.Focus()
+	m.paymentDisperse = false
+	m.paymentItems = nil
+	m.paymentTotals = nil
+	m.paymentBatch = nil
+	m.paymentNext = 0
+	m.paymentBusy = false
+	m.paymentMessage = ""
+	m.paymentTable = newDataTable(m.paymentColumns(), nil, m.tableHeight()-4)
+	m.paymentStep = paymentStepFile
+	m.currentView = constants.PaymentsView
+}
+
+// paymentsBack volta uma etapa ou retorna aos detalhes da wallet
+func (m *CLIModel) paymentsBack() {
+	if m.paymentBusy {
+		return
+	}
+	if m.paymentStep == paymentStepReview {
+		m.paymentStep = paymentStepFile
+		m.paymentMessage = ""
+		m.paymentPath.Focus()
+		return
+	}
+	m.paymentBatch = nil
+	m.currentView = constants.WalletDetailsView
+}
+
+func (m *CLIModel) paymentColumns() []table.Column {
+	return []table.Column{
+		{Title: localization.Labels["payment_line"], Width: 6},
+		{Title: localization.Labels["payment_recipient"], Width: 44},
+		{Title: localization.Labels["payment_amount"], Width: 20},
+		{Title: localization.Labels["payment_token"], Width: 10},
+		{Title: localization.Labels["payment_status"], Width: 20},
+	}
+}
+
+func (m *CLIModel) refreshPaymentRows() {
+	rows := make([]table.Row, 0, len(m.paymentItems))
+	for _, p := range m.paymentItems {
+		status := localization.Labels["payment_status_"+p.Status]
+		if p.Hash != "" {
+			status = shortHex(p.Hash)
+		}
+		rows = append(rows, table.Row{strconv.Itoa(p.Line), p.Recipient.Hex(), p.AmountText, p.Asset.Symbol, status})
+	}
+	m.paymentTable.SetRows(rows)
+}
+
+func (m *CLIModel) pendingPayments() int {
+	pending := 0
+	for _, p := range m.paymentItems {
+		if p.Status != usecases.PaymentSent {
+			pending++
+		}
+	}
+	return pending
+}
+
+// writePaymentResults grava o arquivo de resultados ao lado do arquivo importado
+func (m *CLIModel) writePaymentResults() {
+	path := usecases.ResultsPath(m.paymentFile)
+	if err := usecases.WriteResults(path, m.paymentItems); err != nil {
+		log.Println("Erro ao gravar os resultados do pagamento:", err)
+		m.paymentMessage = fmt.Sprintf(localization.Labels["payment_results_error"], err)
+	}
+}
+
+func (m *CLIModel) applyPaymentsMsg(msg tea.Msg) tea.Cmd {
+	if m.currentView != constants.PaymentsView {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case paymentsLoadedMsg:
+		m.paymentBusy = false
+		if msg.err != nil {
+			log.Println("Erro ao carregar o arquivo de pagamentos:", msg.err)
+			m.paymentMessage = fmt.Sprintf(localization.Labels["payment_load_error"], msg.err)
+			return nil
+		}
+		m.paymentItems = msg.payments
+		m.paymentTotals = msg.totals
+		m.paymentStep = paymentStepReview
+		m.paymentPath.Blur()
+		m.refreshPaymentRows()
+		m.paymentTable.GotoTop()
+		m.paymentMessage = m.paymentReviewPrompt()
+	case paymentsSignedMsg:
+		m.paymentBusy = false
+		if msg.err != nil {
+			log.Println("Erro ao preparar os pagamentos:", msg.err)
+			m.paymentMessage = fmt.Sprintf(localization.Labels["tx_error"], msg.err)
+			return nil
+		}
+		m.paymentBatch = msg.batch
+		m.paymentNext = 0
+		m.paymentStep = paymentStepSend
+		m.paymentMessage = fmt.Sprintf(localization.Labels["payment_signed"], len(msg.batch.Txs),
+			usecases.FormatUnits(msg.batch.Fees, constants.NativeDecimals), constants.DefaultNativeSymbol)
+	case paymentSentMsg:
+		m.paymentBatch.RecordBroadcast(m.paymentItems, msg.index, msg.err)
+		m.refreshPaymentRows()
+		if msg.err != nil {
+			log.Println("Erro ao transmitir o pagamento:", msg.err)
+			m.paymentBusy = false
+			m.paymentMessage = fmt.Sprintf(localization.Labels["payment_stopped"], msg.index+1, len(m.paymentBatch.Txs), msg.err)
+			m.writePaymentResults()
+			return nil
+		}
+		m.paymentNext = msg.index + 1
+		m.writePaymentResults()
+		if m.paymentNext < len(m.paymentBatch.Txs) {
+			return sendPaymentCmd(m.Payments, m.paymentBatch.Txs[m.paymentNext], m.paymentNext)
+		}
+		m.paymentBusy = false
+		m.paymentMessage = fmt.Sprintf(localization.Labels["payment_done"], len(m.paymentBatch.Txs), usecases.ResultsPath(m.paymentFile))
+	}
+	return nil
+}
+
+func (m *CLIModel) paymentReviewPrompt() string {
+	for _, total := range m.paymentTotals {
+		if total.Short() {
+			return localization.Labels["payment_insufficient"]
+		}
+	}
+	pending := m.pendingPayments()
+	if pending == 0 {
+		return localization.Labels["payment_all_sent"]
+	}
+	mode := localization.Labels["payment_mode_direct"]
+	if m.paymentDisperse {
+		mode = localization.Labels["payment_mode_disperse"]
+	}
+	return fmt.Sprintf(localization.Labels["payment_review_prompt"], pending, mode)
+}
+
+func (m *CLIModel) updatePayments(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && m.paymentBusy {
+		return m, nil
+	}
+
+	switch m.paymentStep {
+	case paymentStepFile:
+		if ok {
+			switch keyMsg.String() {
+			case "tab":
+				if m.Payments.Disperse != nil {
+					m.paymentDisperse = !m.paymentDisperse
+				}
+				return m, nil
+			case "enter":
+				path := strings.TrimSpace(m.paymentPath.Value())
+				if path == "" {
+					return m, nil
+				}
+				m.paymentFile = path
+				m.paymentBusy = true
+				m.paymentMessage = localization.Labels["payment_loading"]
+				return m, loadPaymentsCmd(m.Payments, path, common.HexToAddress(m.walletDetails.Wallet.Address))
+			}
+		}
+		var cmd tea.Cmd
+		m.paymentPath, cmd = m.paymentPath.Update(msg)
+		return m, cmd
+
+	case paymentStepReview:
+		if ok && keyMsg.String() == "enter" {
+			if m.pendingPayments() == 0 {
+				return m, nil
+			}
+			for _, total := range m.paymentTotals {
+				if total.Short() {
+					return m, nil
+				}
+			}
+			// O limite de dois fatores vale para o total nativo do lote; lotes com tokens sempre o excedem
+			targets := []twoFactorTarget{{wallet: *m.walletDetails.Wallet, value: usecases.SignedTotal(m.paymentTotals)}}
+			return m, m.requireTwoFactor(domain.TwoFactorSign, targets, func() tea.Cmd {
+				m.paymentBusy = true
+				m.paymentMessage = localization.Labels["payment_signing"]
+				return buildPaymentsCmd(m.Payments, m.walletDetails, m.paymentItems, m.paymentDisperse)
+			})
+		}
+
+	case paymentStepSend:
+		if ok && m.paymentNext < len(m.paymentBatch.Txs) {
+			switch keyMsg.String() {
+			case "enter", "s", "r":
+				// Retomar a partir da primeira transação não transmitida
+				m.paymentBusy = true
+				m.paymentMessage = localization.Labels["payment_broadcasting"]
+				return m, sendPaymentCmd(m.Payments, m.paymentBatch.Txs[m.paymentNext], m.paymentNext)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.paymentTable, cmd = m.paymentTable.Update(msg)
+	return m, cmd
+}
+
+// viewPayments renderiza o arquivo importado, os totais por ativo e o progresso
+func (m *CLIModel) viewPayments() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Render(fmt.Sprintf(localization.Labels["payment_title"], m.walletDetails.Wallet.Address))
+
+	parts := []string{title, ""}
+	if m.paymentStep == paymentStepFile {
+		mode := localization.Labels["payment_mode_direct"]
+		if m.paymentDisperse {
+			mode = localization.Labels["payment_mode_disperse"]
+		}
+		parts = append(parts, localization.Labels["payment_file"], m.paymentPath.View(), "")
+		if m.Payments.Disperse != nil {
+			parts = append(parts, fmt.Sprintf(localization.Labels["payment_mode_toggle"], mode))
+		} else {
+			parts = append(parts, fmt.Sprintf(localization.Labels["payment_mode"], mode))
+		}
+	} else {
+		parts = append(parts, m.paymentTable.View(), "", m.viewPaymentTotals())
+	}
+	if m.paymentMessage != "" {
+		parts = append(parts, "", m.paymentMessage)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (m *CLIModel) viewPaymentTotals() string {
+	var lines []string
+	for _, total := range m.paymentTotals {
+		line := fmt.Sprintf(localization.Labels["payment_total"], total.Asset.Symbol,
+			usecases.FormatUnits(total.Total, total.Asset.Decimals),
+			usecases.FormatUnits(total.Balance, total.Asset.Decimals))
+		if total.Short() {
+			line = m.styles.ErrorStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
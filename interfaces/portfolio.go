@@ -0,0 +1,211 @@
+package interfaces
+
+import (
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Mensagem com a avaliação mais recente do portfólio
+type portfolioMsg struct {
+	portfolio *usecases.Portfolio
+	err       error
+}
+
+// Mensagem periódica para atualizar as cotações
+type portfolioTickMsg struct{}
+
+func fetchPortfolioCmd(wallets *usecases.WalletService, service *usecases.PortfolioService) tea.Cmd {
+	return func() tea.Msg {
+		list, err := wallets.GetAllWallets()
+		if err != nil {
+			return portfolioMsg{err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		portfolio, err := service.Valuate(ctx, list)
+		return portfolioMsg{portfolio: portfolio, err: err}
+	}
+}
+
+func portfolioTickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return portfolioTickMsg{}
+	})
+}
+
+// refreshPortfolio dispara a avaliação e agenda a próxima atualização
+func (m *CLIModel) refreshPortfolio() tea.Cmd {
+	if m.Portfolio == nil {
+		return nil
+	}
+	// Bloqueada pela senha mestra, apenas o agendamento continua
+	var cmds []tea.Cmd
+	if !m.vaultLocked() {
+		cmds = append(cmds, fetchPortfolioCmd(m.Service, m.Portfolio))
+	}
+	if m.PortfolioRefresh > 0 {
+		cmds = append(cmds, portfolioTickCmd(m.PortfolioRefresh))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *CLIModel) applyPortfolio(msg portfolioMsg) {
+	m.portfolioErr = msg.err
+	if msg.err != nil {
+		return
+	}
+	m.portfolio = msg.portfolio
+
+	// Atualizar a coluna de valor da tabela de wallets
+	if len(m.wallets) > 0 {
+		rows := make([]table.Row, 0, len(m.wallets))
+		for _, w := range m.wallets {
+			rows = append(rows, m.walletRow(w))
+		}
+		m.walletTable.SetRows(rows)
+	}
+}
+
+// walletValue formata o valor da wallet e o horário da avaliação para a tabela
+func (m *CLIModel) walletValue(address string) string {
+	if m.portfolio == nil {
+		return "…"
+	}
+	valuation, ok := m.portfolio.Wallets[strings.ToLower(address)]
+	if !ok {
+		return "…"
+	}
+	value := fmt.Sprintf("%s (%s)", formatFiat(valuation.Total), m.portfolio.UpdatedAt.Format("15:04"))
+	if !m.portfolio.Verified {
+		return value
+	}
+	// No modo verificado, sinalizar valores que não puderam ser provados
+	if len(valuation.Unverified) > 0 {
+		return "⚠ " + value
+	}
+	return "✓ " + value
+}
+
+// unverifiedSymbols lista os ativos com algum saldo não provado no portfólio
+func (m *CLIModel) unverifiedSymbols() map[string]bool {
+	symbols := map[string]bool{}
+	for _, valuation := range m.portfolio.Wallets {
+		for _, symbol := range valuation.Unverified {
+			symbols[symbol] = true
+		}
+	}
+	return symbols
+}
+
+// portfolioVerificationLine descreve o checkpoint usado na verificação dos saldos
+func (m *CLIModel) portfolioVerificationLine() string {
+	if !m.portfolio.Verified {
+		return ""
+	}
+	if m.portfolio.VerifyError != nil {
+		return m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["portfolio_verify_error"], m.portfolio.VerifyError))
+	}
+	line := fmt.Sprintf(localization.Labels["portfolio_verified"], m.portfolio.Checkpoint.Number, shortHex(m.portfolio.Checkpoint.Hash.Hex()))
+	if len(m.unverifiedSymbols()) > 0 {
+		line += " | " + localization.Labels["portfolio_unverified"]
+	}
+	return line
+}
+
+// portfolioHeaderLine é exibida no cabeçalho ao lado da contagem de wallets
+func (m *CLIModel) portfolioHeaderLine() string {
+	if m.Portfolio == nil {
+		return ""
+	}
+	currency := strings.ToUpper(m.Portfolio.Currency)
+	if m.portfolio == nil {
+		return fmt.Sprintf(localization.Labels["portfolio_header"], "…", currency)
+	}
+	return fmt.Sprintf(localization.Labels["portfolio_header"], formatFiat(m.portfolio.Total), currency)
+}
+
+// viewPortfolioSummary renderiza o painel de resumo do portfólio na tela inicial
+func (m *CLIModel) viewPortfolioSummary() string {
+	if m.Portfolio == nil {
+		return ""
+	}
+	title := m.styles.MenuTitle.Render(localization.Labels["portfolio_title"])
+	if m.portfolioErr != nil && m.portfolio == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, title, fmt.Sprintf(localization.Labels["portfolio_error"], m.portfolioErr))
+	}
+	if m.portfolio == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, title, localization.Labels["portfolio_loading"])
+	}
+
+	currency := strings.ToUpper(m.portfolio.Currency)
+	unverified := m.unverifiedSymbols()
+	var lines []string
+	for _, holding := range m.portfolio.Totals {
+		value := localization.Labels["portfolio_no_price"]
+		if holding.Price != nil {
+			value = fmt.Sprintf("%s %s", formatFiat(holding.Value), currency)
+		}
+		if unverified[holding.Asset.Symbol] {
+			value += " ⚠"
+		}
+		lines = append(lines, fmt.Sprintf("%-8s %24s  %s",
+			holding.Asset.Symbol,
+			usecases.FormatUnits(holding.Balance, holding.Asset.Decimals),
+			value))
+	}
+	lines = append(lines, fmt.Sprintf("%-8s %24s  %s %s", localization.Labels["portfolio_total"], "", formatFiat(m.portfolio.Total), currency))
+
+	updated := fmt.Sprintf(localization.Labels["portfolio_updated"], m.portfolio.UpdatedAt.Format("02-01-2006 15:04:05"))
+	if !m.portfolio.PricedAt.IsZero() {
+		updated += " | " + fmt.Sprintf(localization.Labels["portfolio_priced"], m.portfolio.PricedAt.Format("02-01-2006 15:04:05"))
+	}
+	if m.portfolioErr != nil {
+		updated += " | " + fmt.Sprintf(localization.Labels["portfolio_error"], m.portfolioErr)
+	}
+	if verification := m.portfolioVerificationLine(); verification != "" {
+		lines = append(lines, "", verification)
+	}
+
+	panel := lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		strings.Join(lines, "\n"),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#5C5C5C")).Render(updated),
+	)
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(0, 1).
+		Render(panel)
+}
+
+// formatFiat formata valores monetários com separador de milhar e duas casas decimais
+func formatFiat(value float64) string {
+	cents := int64(math.Round(value * 100))
+	negative := cents < 0
+	if negative {
+		cents = -cents
+	}
+	integer := strconv.FormatInt(cents/100, 10)
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	result := fmt.Sprintf("%s.%02d", grouped.String(), cents%100)
+	if negative {
+		return "-" + result
+	}
+	return result
+}
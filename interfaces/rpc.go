@@ -0,0 +1,56 @@
+package interfaces
+
+import (
+	"blocowallet/localization"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	rpcStatusRefresh = 5 * time.Second // Intervalo de atualização do estado do endpoint RPC
+	rpcStatusWidth   = 48              // Largura máxima do trecho RPC na barra de status
+)
+
+// Mensagem periódica para redesenhar o estado do endpoint RPC
+type rpcStatusTickMsg struct{}
+
+func rpcStatusTickCmd() tea.Cmd {
+	return tea.Tick(rpcStatusRefresh, func(time.Time) tea.Msg {
+		return rpcStatusTickMsg{}
+	})
+}
+
+// watchRPC agenda a atualização do estado quando há failover configurado
+func (m *CLIModel) watchRPC() tea.Cmd {
+	if m.RPC == nil {
+		return nil
+	}
+	return rpcStatusTickCmd()
+}
+
+// renderRPCStatus mostra o endpoint ativo, a latência e o bloco mais recente,
+// ou o motivo da falha quando o endpoint não está saudável
+func (m *CLIModel) renderRPCStatus() string {
+	if m.RPC == nil {
+		return ""
+	}
+	health := m.RPC.ActiveEndpoint()
+	style := m.styles.StatusBarLeft.Background(lipgloss.Color("#2E8B57"))
+	content := fmt.Sprintf(localization.Labels["rpc_status_healthy"], health.URL, health.Latency.Milliseconds(), health.Head)
+	if !health.Healthy {
+		style = m.styles.StatusBarLeft.Background(lipgloss.Color("#B22222"))
+		content = fmt.Sprintf(localization.Labels["rpc_status_unhealthy"], health.URL, health.Error)
+	}
+	return style.SetString(truncateText(content, rpcStatusWidth)).String()
+}
+
+func truncateText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,104 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Intervalo de verificação da inatividade e de atualização do tempo restante
+const sessionRefresh = time.Second
+
+type sessionTickMsg struct{}
+
+func sessionTickCmd() tea.Cmd {
+	return tea.Tick(sessionRefresh, func(time.Time) tea.Msg {
+		return sessionTickMsg{}
+	})
+}
+
+// watchSession agenda a verificação quando o bloqueio por inatividade está ativo
+func (m *CLIModel) watchSession() tea.Cmd {
+	if m.Service.Session == nil || m.Service.Session.IdleTimeout <= 0 {
+		return nil
+	}
+	return sessionTickCmd()
+}
+
+// sessionLocked indica se a tela de bloqueio já está sendo exibida
+func (m *CLIModel) sessionLocked() bool {
+	return m.currentView == constants.VaultView && (m.vaultLocked() || m.vaultStep == vaultStepResume)
+}
+
+// checkSession bloqueia a aplicação quando o tempo de inatividade se esgota
+func (m *CLIModel) checkSession() bool {
+	if m.Service.Session == nil || !m.Service.Session.Idle() {
+		return false
+	}
+	if m.currentView == constants.SplashView || m.sessionLocked() {
+		return false
+	}
+	m.lockSession()
+	return true
+}
+
+// lockSession descarta as chaves e os segredos mantidos em memória e volta à
+// tela de bloqueio
+func (m *CLIModel) lockSession() {
+	m.Service.Session.Clear()
+	m.err = nil
+	m.walletDetails = nil
+	m.wallets = nil
+	m.selectedWallet = nil
+	m.deletingWallet = nil
+	m.historyWallet = nil
+	m.mnemonic = ""
+	m.importWords = nil
+	m.textInputs = nil
+	m.passwordInput = textinput.New()
+	m.privateKeyInput = textinput.New()
+	m.sweepPassword = textinput.New()
+	m.sweepWallets = nil
+	m.sweepItems = nil
+	m.sweepUnlocked = map[int]*usecases.WalletDetails{}
+	m.discoveryMnemonic = ""
+	m.discoveryPassword = ""
+	m.leaveTwoFactor(m.currentView)
+	m.twoFactorNotice = ""
+	m.resetUnlockPrompt()
+
+	if m.Vault != nil {
+		m.Vault.Lock()
+		m.initVault()
+	} else {
+		m.setVaultStep(vaultStepResume)
+		m.currentView = constants.VaultView
+	}
+	m.vaultMessage = fmt.Sprintf(localization.Labels["session_locked_idle"], formatSessionTime(m.Service.Session.IdleTimeout))
+}
+
+// renderSessionStatus mostra o tempo restante até o bloqueio por inatividade
+func (m *CLIModel) renderSessionStatus() string {
+	session := m.Service.Session
+	if session == nil || session.IdleTimeout <= 0 || m.sessionLocked() || m.vaultLocked() {
+		return ""
+	}
+	style := m.styles.StatusBarLeft.Background(lipgloss.Color("#2E8B57"))
+	remaining := session.Remaining()
+	if remaining < time.Minute {
+		style = m.styles.StatusBarLeft.Background(lipgloss.Color("#B8860B"))
+	}
+	return style.SetString(fmt.Sprintf(localization.Labels["session_status"], formatSessionTime(remaining))).String()
+}
+
+// formatSessionTime formata a duração como minutos e segundos
+func formatSessionTime(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,481 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Etapas da varredura de saldos
+const (
+	sweepStepSetup = iota
+	sweepStepPlan
+	sweepStepPassword
+	sweepStepSend
+)
+
+// O planejamento consulta vários endereços, por isso o prazo é maior que fetchTimeout
+const sweepTimeout = 2 * time.Minute
+
+type sweepPlannedMsg struct {
+	items []usecases.SweepItem
+	err   error
+}
+
+type sweepUnlockedMsg struct {
+	unlocked map[int]*usecases.WalletDetails // Posição em sweepItems
+	auto     bool                            // Desbloqueio pelos provedores de senha
+}
+
+type sweepSentMsg struct {
+	index int
+	hash  string
+	err   error
+}
+
+func planSweepCmd(service *usecases.SweepService, wallets []domain.Wallet, to common.Address, asset domain.Asset) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sweepTimeout)
+		defer cancel()
+		items, err := service.Plan(ctx, wallets, to, asset)
+		return sweepPlannedMsg{items: items, err: err}
+	}
+}
+
+// unlockSweepCmd tenta a mesma senha em todas as wallets ainda bloqueadas do lote
+func unlockSweepCmd(service *usecases.WalletService, wallets []domain.Wallet, indexes []int, password string) tea.Cmd {
+	return func() tea.Msg {
+		unlocked := make(map[int]*usecases.WalletDetails)
+		for i, details := range service.UnlockGroup(wallets, password) {
+			unlocked[indexes[i]] = details
+		}
+		return sweepUnlockedMsg{unlocked: unlocked}
+	}
+}
+
+// autoUnlockSweepCmd desbloqueia as wallets com os provedores de senha configurados;
+// as que sobrarem seguem para o pedido de senha
+func autoUnlockSweepCmd(service *usecases.WalletService, wallets []domain.Wallet, indexes []int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sweepTimeout)
+		defer cancel()
+		unlocked := make(map[int]*usecases.WalletDetails)
+		for i, details := range service.UnlockGroupNonInteractive(ctx, wallets) {
+			unlocked[indexes[i]] = details
+		}
+		return sweepUnlockedMsg{unlocked: unlocked, auto: true}
+	}
+}
+
+func sendSweepCmd(transactions *usecases.TransactionService, details *usecases.WalletDetails, item usecases.SweepItem, index int, memo string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		signed, err := transactions.SignTransaction(ctx, details, item.Tx, memo)
+		if err == nil {
+			err = transactions.SendTransaction(ctx, signed)
+		}
+		if err != nil {
+			return sweepSentMsg{index: index, err: err}
+		}
+		return sweepSentMsg{index: index, hash: signed.Hash().Hex()}
+	}
+}
+
+// toggleSweepSelection marca ou desmarca a wallet sob o cursor para a varredura
+func (m *CLIModel) toggleSweepSelection() {
+	selectedRow := m.walletTable.SelectedRow()
+	if len(selectedRow) < 2 {
+		return
+	}
+	if m.sweepSelected == nil {
+		m.sweepSelected = map[string]bool{}
+	}
+	address := strings.ToLower(walletRowAddress(selectedRow))
+	if m.sweepSelected[address] {
+		delete(m.sweepSelected, address)
+	} else {
+		m.sweepSelected[address] = true
+	}
+	rows := make([]table.Row, 0, len(m.wallets))
+	for _, w := range m.wallets {
+		rows = append(rows, m.walletRow(w))
+	}
+	m.walletTable.SetRows(rows)
+}
+
+// walletIDLabel marca na coluna de ID as wallets selecionadas para a varredura
+func (m *CLIModel) walletIDLabel(w domain.Wallet) string {
+	if m.sweepSelected[strings.ToLower(w.Address)] {
+		return fmt.Sprintf("* %d", w.ID)
+	}
+	return fmt.Sprintf("%d", w.ID)
+}
+
+// initSweep abre a varredura com as wallets selecionadas ou, sem seleção, a wallet sob o cursor
+func (m *CLIModel) initSweep() {
+	var wallets []domain.Wallet
+	for _, w := range m.wallets {
+		if m.sweepSelected[strings.ToLower(w.Address)] {
+			wallets = append(wallets, w)
+		}
+	}
+	if len(wallets) == 0 {
+		selectedRow := m.walletTable.SelectedRow()
+		if len(selectedRow) < 2 {
+			return
+		}
+		address := walletRowAddress(selectedRow)
+		for _, w := range m.wallets {
+			if w.Address == address {
+				wallets = append(wallets, w)
+			}
+		}
+	}
+
+	m.sweepWallets = wallets
+	m.sweepItems = nil
+	m.sweepUnlocked = map[int]*usecases.WalletDetails{}
+	m.sweepStatus = map[int]string{}
+	m.sweepAsset = 0
+	m.sweepBusy = false
+	m.sweepMessage = ""
+	m.txNotice = ""
+	m.sweepDestination = newAddressInput(localization.Labels["sweep_destination"])
+	m.sweepTable = newDataTable(m.sweepColumns(), nil, m.tableHeight())
+	m.sweepStep = sweepStepSetup
+	m.currentView = constants.SweepView
+}
+
+// sweepBack pula as wallets ainda bloqueadas ou volta para a lista de wallets
+func (m *CLIModel) sweepBack() {
+	if m.sweepBusy {
+		return
+	}
+	if m.sweepStep == sweepStepPassword && len(m.sweepUnlocked) > 0 {
+		m.sweepStep = sweepStepSend
+		m.sweepMessage = m.sweepConfirmMessage()
+		return
+	}
+	m.sweepUnlocked = nil
+	m.sweepPassword.Reset()
+	m.currentView = constants.ListWalletsView
+}
+
+// sweepConfirmMessage pede a confirmação e, para a moeda nativa, avisa que a
+// taxa reservada pode sobrar em parte na wallet
+func (m *CLIModel) sweepConfirmMessage() string {
+	message := fmt.Sprintf(localization.Labels["sweep_confirm"], len(m.sweepUnlocked))
+	if m.sweepAssetInfo().Contract == "" {
+		message += "\n" + localization.Labels["sweep_fee_note"]
+	}
+	return message
+}
+
+func (m *CLIModel) sweepColumns() []table.Column {
+	return []table.Column{
+		{Title: localization.Labels["sweep_wallet"], Width: 14},
+		{Title: localization.Labels["sweep_balance"], Width: 22},
+		{Title: localization.Labels["sweep_amount"], Width: 22},
+		{Title: localization.Labels["sweep_fee"], Width: 18},
+		{Title: localization.Labels["sweep_status"], Width: 22},
+	}
+}
+
+func (m *CLIModel) sweepAssetInfo() domain.Asset {
+	return m.Sweeps.Assets[m.sweepAsset]
+}
+
+func (m *CLIModel) refreshSweepRows() {
+	asset := m.sweepAssetInfo()
+	rows := make([]table.Row, 0, len(m.sweepItems))
+	for i, item := range m.sweepItems {
+		balance, amount, fee := "-", "-", "-"
+		if item.Balance != nil {
+			balance = usecases.FormatUnits(item.Balance, asset.Decimals) + " " + asset.Symbol
+		}
+		if item.Amount != nil {
+			amount = usecases.FormatUnits(item.Amount, asset.Decimals) + " " + asset.Symbol
+		}
+		if item.Fee != nil {
+			fee = usecases.FormatUnits(item.Fee, constants.NativeDecimals)
+		}
+		rows = append(rows, table.Row{shortHex(item.Wallet.Address), balance, amount, fee, m.sweepItemStatus(i)})
+	}
+	m.sweepTable.SetRows(rows)
+}
+
+func (m *CLIModel) sweepItemStatus(index int) string {
+	if status, ok := m.sweepStatus[index]; ok {
+		return status
+	}
+	item := m.sweepItems[index]
+	if item.Tx == nil {
+		return localization.Labels["sweep_skip_"+item.Skip]
+	}
+	if _, ok := m.sweepUnlocked[index]; ok {
+		return localization.Labels["sweep_unlocked"]
+	}
+	return localization.Labels["sweep_locked"]
+}
+
+// lockedSweepItems lista as wallets com transação planejada que ainda aguardam senha
+func (m *CLIModel) lockedSweepItems() ([]domain.Wallet, []int) {
+	var wallets []domain.Wallet
+	var indexes []int
+	for i, item := range m.sweepItems {
+		if item.Tx == nil {
+			continue
+		}
+		if _, ok := m.sweepUnlocked[i]; ok {
+			continue
+		}
+		wallets = append(wallets, item.Wallet)
+		indexes = append(indexes, i)
+	}
+	return wallets, indexes
+}
+
+// nextSweepCmd transmite a próxima transação desbloqueada ainda não enviada
+func (m *CLIModel) nextSweepCmd() tea.Cmd {
+	for i, item := range m.sweepItems {
+		details, ok := m.sweepUnlocked[i]
+		if !ok || item.Tx == nil {
+			continue
+		}
+		if _, done := m.sweepStatus[i]; done {
+			continue
+		}
+		m.sweepStatus[i] = localization.Labels["sweep_sending"]
+		m.refreshSweepRows()
+		memo := fmt.Sprintf("sweep → %s", m.sweepTo)
+		return sendSweepCmd(m.Transactions, details, item, i, memo)
+	}
+	return nil
+}
+
+func (m *CLIModel) applySweepMsg(msg tea.Msg) tea.Cmd {
+	if m.currentView != constants.SweepView {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case sweepPlannedMsg:
+		m.sweepBusy = false
+		if msg.err != nil {
+			log.Println("Erro ao planejar a varredura:", msg.err)
+			m.sweepMessage = fmt.Sprintf(localization.Labels["sweep_error"], msg.err)
+			return nil
+		}
+		m.sweepItems = msg.items
+		m.sweepStep = sweepStepPlan
+		m.refreshSweepRows()
+		m.sweepTable.GotoTop()
+		if _, indexes := m.lockedSweepItems(); len(indexes) == 0 {
+			m.sweepMessage = localization.Labels["sweep_nothing"]
+		} else {
+			m.sweepMessage = fmt.Sprintf(localization.Labels["sweep_plan_prompt"], len(indexes))
+		}
+	case sweepUnlockedMsg:
+		m.sweepBusy = false
+		for index, details := range msg.unlocked {
+			m.sweepUnlocked[index] = details
+		}
+		m.refreshSweepRows()
+		_, remaining := m.lockedSweepItems()
+		if msg.auto && len(remaining) > 0 {
+			m.askSweepPassword(len(remaining))
+			return nil
+		}
+		m.sweepPassword.Reset()
+		if len(remaining) > 0 {
+			m.sweepMessage = fmt.Sprintf(localization.Labels["sweep_unlock_partial"], len(msg.unlocked), len(remaining))
+			return nil
+		}
+		m.sweepStep = sweepStepSend
+		m.sweepMessage = m.sweepConfirmMessage()
+	case sweepSentMsg:
+		if msg.err != nil {
+			log.Println("Erro ao transmitir a varredura:", msg.err)
+			m.sweepStatus[msg.index] = localization.Labels["sweep_failed"]
+		} else {
+			m.sweepStatus[msg.index] = shortHex(msg.hash)
+		}
+		m.refreshSweepRows()
+		if cmd := m.nextSweepCmd(); cmd != nil {
+			return cmd
+		}
+
+		// Lote concluído: descartar as chaves desbloqueadas
+		m.sweepBusy = false
+		m.sweepUnlocked = map[int]*usecases.WalletDetails{}
+		sent, failed := 0, 0
+		for _, status := range m.sweepStatus {
+			if status == localization.Labels["sweep_failed"] {
+				failed++
+			} else {
+				sent++
+			}
+		}
+		m.sweepMessage = fmt.Sprintf(localization.Labels["sweep_done"], sent, failed)
+		m.sweepSelected = nil
+		return m.refreshWalletsTable()
+	}
+	return nil
+}
+
+// askSweepPassword pede a senha das wallets que continuam bloqueadas
+func (m *CLIModel) askSweepPassword(locked int) {
+	m.sweepPassword = newPasswordInput(localization.Labels["sweep_password"])
+	m.sweepStep = sweepStepPassword
+	m.sweepMessage = fmt.Sprintf(localization.Labels["sweep_password_prompt"], locked)
+}
+
+func (m *CLIModel) updateSweep(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && m.sweepBusy {
+		return m, nil
+	}
+
+	switch m.sweepStep {
+	case sweepStepSetup:
+		if ok {
+			switch keyMsg.String() {
+			case "tab":
+				m.sweepAsset = (m.sweepAsset + 1) % len(m.Sweeps.Assets)
+				return m, nil
+			case "enter":
+				wallets := m.sweepWallets
+				asset := m.sweepAssetInfo()
+				return m, m.withRecipient(m.sweepDestination.Value(), func(to common.Address, label string) tea.Cmd {
+					m.sweepTo = label
+					m.sweepBusy = true
+					m.txNotice = ""
+					m.sweepMessage = localization.Labels["sweep_planning"]
+					return planSweepCmd(m.Sweeps, wallets, to, asset)
+				})
+			}
+		}
+		var cmd tea.Cmd
+		m.sweepDestination, cmd = m.sweepDestination.Update(msg)
+		return m, cmd
+
+	case sweepStepPlan:
+		if ok && keyMsg.String() == "enter" {
+			wallets, indexes := m.lockedSweepItems()
+			if len(indexes) == 0 {
+				return m, nil
+			}
+			if m.Service.Passwords != nil {
+				m.sweepBusy = true
+				m.sweepMessage = localization.Labels["sweep_unlocking"]
+				return m, autoUnlockSweepCmd(m.Service, wallets, indexes)
+			}
+			m.askSweepPassword(len(indexes))
+			return m, nil
+		}
+
+	case sweepStepPassword:
+		if ok && keyMsg.String() == "enter" {
+			wallets, indexes := m.lockedSweepItems()
+			m.sweepBusy = true
+			m.sweepMessage = localization.Labels["sweep_unlocking"]
+			return m, unlockSweepCmd(m.Service, wallets, indexes, m.sweepPassword.Value())
+		}
+		var cmd tea.Cmd
+		m.sweepPassword, cmd = m.sweepPassword.Update(msg)
+		return m, cmd
+
+	case sweepStepSend:
+		if ok && (keyMsg.String() == "enter" || keyMsg.String() == "s") && len(m.sweepUnlocked) > 0 {
+			var targets []twoFactorTarget
+			for i, item := range m.sweepItems {
+				if _, ok := m.sweepUnlocked[i]; !ok || item.Tx == nil {
+					continue
+				}
+				if _, done := m.sweepStatus[i]; !done {
+					targets = append(targets, twoFactorTarget{wallet: item.Wallet, value: usecases.SignedValue(item.Tx)})
+				}
+			}
+			return m, m.requireTwoFactor(domain.TwoFactorSign, targets, func() tea.Cmd {
+				m.sweepBusy = true
+				m.sweepMessage = localization.Labels["sweep_broadcasting"]
+				return m.nextSweepCmd()
+			})
+		}
+	}
+
+	var cmd tea.Cmd
+	m.sweepTable, cmd = m.sweepTable.Update(msg)
+	return m, cmd
+}
+
+// viewSweep renderiza o formulário, o plano por wallet e o progresso da transmissão
+func (m *CLIModel) viewSweep() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Render(fmt.Sprintf(localization.Labels["sweep_title"], len(m.sweepWallets)))
+
+	parts := []string{title, ""}
+	if m.sweepStep == sweepStepSetup {
+		asset := m.sweepAssetInfo()
+		parts = append(parts,
+			fmt.Sprintf(localization.Labels["sweep_asset"], asset.Symbol),
+			"",
+			localization.Labels["sweep_destination"],
+			m.sweepDestination.View(),
+		)
+	} else {
+		parts = append(parts,
+			fmt.Sprintf(localization.Labels["sweep_summary"], m.sweepAssetInfo().Symbol, m.sweepTo, m.sweepTotal()),
+			"",
+			m.sweepTable.View(),
+		)
+	}
+	if m.sweepStep == sweepStepPassword {
+		parts = append(parts, "", m.sweepPassword.View())
+	}
+	if m.txNotice != "" {
+		parts = append(parts, "", m.txNotice)
+	}
+	if m.sweepMessage != "" {
+		parts = append(parts, "", m.sweepMessage)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// sweepTotal soma os valores planejados para o destino
+func (m *CLIModel) sweepTotal() string {
+	total := new(big.Int)
+	for _, item := range m.sweepItems {
+		if item.Amount != nil {
+			total.Add(total, item.Amount)
+		}
+	}
+	return usecases.FormatUnits(total, m.sweepAssetInfo().Decimals)
+}
+
+// newPasswordInput cria um campo de senha mascarado
+func newPasswordInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = constants.PasswordCharLimit
+	ti.Width = constants.PasswordWidth
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '•'
+	ti.Focus()
+	return ti
+}
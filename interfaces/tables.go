@@ -0,0 +1,48 @@
+package interfaces
+
+import (
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newDataTable cria uma tabela com o mesmo estilo da lista de wallets
+func newDataTable(columns []table.Column, rows []table.Row, height int) table.Model {
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(true)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	s.Cell = s.Cell.Align(lipgloss.Left)
+	t.SetStyles(s)
+
+	if height < 5 {
+		height = 5
+	}
+	t.SetHeight(height)
+	return t
+}
+
+// tableHeight calcula a altura disponível para tabelas renderizadas na área de conteúdo
+func (m *CLIModel) tableHeight() int {
+	// Cabeçalho com logo, rodapé e título/instruções da própria visualização
+	return m.height - 22
+}
+
+// shortHex abrevia endereços e hashes para caberem nas colunas das tabelas
+func shortHex(value string) string {
+	if len(value) <= 14 {
+		return value
+	}
+	return value[:8] + "…" + value[len(value)-4:]
+}
@@ -0,0 +1,476 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"rsc.io/qr"
+)
+
+// Modos da tela de dois fatores
+const (
+	twoFactorEnroll    = iota // Segredo novo aguardando o primeiro código
+	twoFactorSettings         // Operações protegidas de uma wallet já cadastrada
+	twoFactorChallenge        // Código pedido antes de uma operação sensível
+)
+
+// Linhas das configurações: três opções, o limite de assinatura e o código
+const (
+	twoFactorRowThreshold = 3
+	twoFactorRowCode      = 4
+)
+
+// twoFactorTarget é uma wallet envolvida na operação e o valor nativo que ela assina
+type twoFactorTarget struct {
+	wallet domain.Wallet
+	value  *big.Int
+}
+
+func newTwoFactorCodeInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = localization.Labels["two_factor_code"]
+	input.CharLimit = 16
+	input.Width = 20
+	input.Focus()
+	return input
+}
+
+// showWalletDetails exibe a wallet desbloqueada, ocultando as chaves quando a
+// revelação exige o código de dois fatores
+func (m *CLIModel) showWalletDetails(details *usecases.WalletDetails) {
+	m.walletDetails = details
+	m.twoFactorNotice = ""
+	m.detailsHidden = false
+	if m.TwoFactor != nil {
+		required, err := m.TwoFactor.Required(details.Wallet.ID, domain.TwoFactorReveal, nil)
+		if err != nil {
+			log.Println("Erro ao ler a autenticação em dois fatores:", err)
+		}
+		m.detailsHidden = required || err != nil
+	}
+	m.currentView = constants.WalletDetailsView
+}
+
+// revealWalletKeys pede o código antes de mostrar a chave privada e o mnemônico
+func (m *CLIModel) revealWalletKeys() tea.Cmd {
+	targets := []twoFactorTarget{{wallet: *m.walletDetails.Wallet}}
+	return m.requireTwoFactor(domain.TwoFactorReveal, targets, func() tea.Cmd {
+		m.detailsHidden = false
+		return nil
+	})
+}
+
+// requireTwoFactor executa done de imediato quando nenhuma wallet exige código
+// para a operação; do contrário pede os códigos, um por wallet, e só então executa done
+func (m *CLIModel) requireTwoFactor(op domain.TwoFactorOperation, targets []twoFactorTarget, done func() tea.Cmd) tea.Cmd {
+	var pending []domain.Wallet
+	if m.TwoFactor != nil {
+		for _, target := range targets {
+			required, err := m.TwoFactor.Required(target.wallet.ID, op, target.value)
+			if err != nil {
+				// Na dúvida o código é exigido
+				log.Println("Erro ao ler a autenticação em dois fatores:", err)
+				required = true
+			}
+			if required {
+				pending = append(pending, target.wallet)
+			}
+		}
+	}
+	if len(pending) == 0 {
+		return done()
+	}
+	m.twoFactorMode = twoFactorChallenge
+	m.twoFactorQueue = pending
+	m.twoFactorTotal = len(pending)
+	m.twoFactorOperation = op
+	m.twoFactorReturn = m.currentView
+	m.twoFactorDone = done
+	m.twoFactorCode = newTwoFactorCodeInput()
+	m.twoFactorMessage = ""
+	m.currentView = constants.TwoFactorView
+	return nil
+}
+
+// initTwoFactor abre o cadastro ou as configurações da wallet aberta nos detalhes
+func (m *CLIModel) initTwoFactor() {
+	wallet := *m.walletDetails.Wallet
+	m.twoFactorNotice = ""
+	status, err := m.TwoFactor.Status(wallet.ID)
+	if err != nil {
+		log.Println("Erro ao ler a autenticação em dois fatores:", err)
+		m.twoFactorNotice = fmt.Sprintf(localization.Labels["two_factor_error"], err)
+		return
+	}
+
+	m.twoFactorWallet = wallet
+	m.twoFactorMessage = ""
+	m.twoFactorCode = newTwoFactorCodeInput()
+	if status != nil {
+		m.openTwoFactorSettings(status)
+		m.currentView = constants.TwoFactorView
+		return
+	}
+
+	enrollment, err := m.TwoFactor.Enroll(&wallet)
+	if err != nil {
+		m.twoFactorNotice = fmt.Sprintf(localization.Labels["two_factor_error"], twoFactorErrorText(err))
+		return
+	}
+	code, err := renderQR(enrollment.URI)
+	if err != nil {
+		log.Println("Erro ao gerar o QR code:", err)
+	}
+	m.twoFactorMode = twoFactorEnroll
+	m.twoFactorEnrollment = enrollment
+	m.twoFactorQR = code
+	m.currentView = constants.TwoFactorView
+}
+
+// openTwoFactorSettings carrega a política cadastrada para edição
+func (m *CLIModel) openTwoFactorSettings(status *domain.TwoFactor) {
+	m.twoFactorMode = twoFactorSettings
+	m.twoFactorEnrollment = nil
+	m.twoFactorQR = ""
+	m.twoFactorPolicy = status.Policy
+	m.twoFactorThreshold = textinput.New()
+	m.twoFactorThreshold.Placeholder = localization.Labels["two_factor_threshold_placeholder"]
+	m.twoFactorThreshold.CharLimit = 40
+	m.twoFactorThreshold.Width = 24
+	if status.Policy.SignThreshold != nil {
+		m.twoFactorThreshold.SetValue(usecases.FormatUnits(status.Policy.SignThreshold, constants.NativeDecimals))
+	}
+	m.focusTwoFactorRow(twoFactorRowCode)
+}
+
+func (m *CLIModel) focusTwoFactorRow(row int) {
+	m.twoFactorFocus = (row + twoFactorRowCode + 1) % (twoFactorRowCode + 1)
+	m.twoFactorThreshold.Blur()
+	m.twoFactorCode.Blur()
+	switch m.twoFactorFocus {
+	case twoFactorRowThreshold:
+		m.twoFactorThreshold.Focus()
+	case twoFactorRowCode:
+		m.twoFactorCode.Focus()
+	}
+}
+
+// leaveTwoFactor descarta o segredo exibido e a operação pendente
+func (m *CLIModel) leaveTwoFactor(view string) {
+	m.twoFactorEnrollment = nil
+	m.twoFactorQR = ""
+	m.twoFactorQueue = nil
+	m.twoFactorDone = nil
+	m.twoFactorCode = textinput.New()
+	m.twoFactorMessage = ""
+	m.currentView = view
+}
+
+func twoFactorErrorText(err error) string {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidTwoFactorCode):
+		return localization.Labels["two_factor_invalid_code"]
+	case errors.Is(err, usecases.ErrUnlockBlocked):
+		return localization.Labels["two_factor_blocked"]
+	case errors.Is(err, usecases.ErrTwoFactorNeedsVault):
+		return localization.Labels["two_factor_needs_vault"]
+	case errors.Is(err, usecases.ErrTwoFactorNoVault):
+		return localization.Labels["two_factor_no_vault"]
+	}
+	return err.Error()
+}
+
+// twoFactorFailed mostra o erro, com as tentativas restantes quando o código é
+// limitado, e limpa o código para a próxima tentativa
+func (m *CLIModel) twoFactorFailed(err error) {
+	var unlockErr *usecases.UnlockError
+	limited := errors.As(err, &unlockErr)
+	if !limited && !errors.Is(err, usecases.ErrInvalidTwoFactorCode) {
+		log.Println("Erro na autenticação em dois fatores:", err)
+	}
+	m.twoFactorMessage = fmt.Sprintf(localization.Labels["two_factor_error"], twoFactorErrorText(err))
+	if limited {
+		lines := append([]string{m.twoFactorMessage}, unlockStatusLines(unlockErr.Status)...)
+		m.twoFactorMessage = strings.Join(lines, "\n")
+	}
+	m.twoFactorCode.Reset()
+}
+
+func (m *CLIModel) updateTwoFactor(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch m.twoFactorMode {
+	case twoFactorEnroll:
+		return m.updateTwoFactorEnroll(keyMsg)
+	case twoFactorSettings:
+		return m.updateTwoFactorSettings(keyMsg)
+	}
+	return m.updateTwoFactorChallenge(keyMsg)
+}
+
+func (m *CLIModel) updateTwoFactorEnroll(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "esc":
+		m.leaveTwoFactor(constants.WalletDetailsView)
+		return m, nil
+	case "enter":
+		if err := m.TwoFactor.ConfirmEnrollment(m.twoFactorEnrollment, m.twoFactorCode.Value()); err != nil {
+			m.twoFactorFailed(err)
+			return m, nil
+		}
+		status, err := m.TwoFactor.Status(m.twoFactorWallet.ID)
+		if err != nil || status == nil {
+			m.leaveTwoFactor(constants.WalletDetailsView)
+			m.twoFactorNotice = localization.Labels["two_factor_enabled"]
+			return m, nil
+		}
+		m.twoFactorCode = newTwoFactorCodeInput()
+		m.openTwoFactorSettings(status)
+		m.twoFactorMessage = localization.Labels["two_factor_enabled"]
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.twoFactorCode, cmd = m.twoFactorCode.Update(keyMsg)
+	return m, cmd
+}
+
+func (m *CLIModel) updateTwoFactorSettings(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "esc":
+		m.leaveTwoFactor(constants.WalletDetailsView)
+		return m, nil
+	case "tab", "down":
+		m.focusTwoFactorRow(m.twoFactorFocus + 1)
+		return m, nil
+	case "shift+tab", "up":
+		m.focusTwoFactorRow(m.twoFactorFocus - 1)
+		return m, nil
+	case " ":
+		switch m.twoFactorFocus {
+		case 0:
+			m.twoFactorPolicy.Reveal = !m.twoFactorPolicy.Reveal
+			return m, nil
+		case 1:
+			m.twoFactorPolicy.Export = !m.twoFactorPolicy.Export
+			return m, nil
+		case 2:
+			m.twoFactorPolicy.Delete = !m.twoFactorPolicy.Delete
+			return m, nil
+		}
+	case "ctrl+d":
+		if err := m.TwoFactor.Disable(&m.twoFactorWallet, m.twoFactorCode.Value()); err != nil {
+			m.twoFactorFailed(err)
+			return m, nil
+		}
+		m.leaveTwoFactor(constants.WalletDetailsView)
+		m.detailsHidden = false
+		m.twoFactorNotice = localization.Labels["two_factor_disabled"]
+		return m, nil
+	case "enter":
+		if m.twoFactorFocus != twoFactorRowCode {
+			m.focusTwoFactorRow(twoFactorRowCode)
+			return m, nil
+		}
+		policy := m.twoFactorPolicy
+		policy.SignThreshold = nil
+		if value := strings.TrimSpace(m.twoFactorThreshold.Value()); value != "" {
+			threshold, err := usecases.ParseUnits(value, constants.NativeDecimals)
+			if err != nil {
+				m.twoFactorMessage = fmt.Sprintf(localization.Labels["two_factor_invalid_threshold"], value)
+				return m, nil
+			}
+			policy.SignThreshold = threshold
+		}
+		if err := m.TwoFactor.UpdatePolicy(&m.twoFactorWallet, m.twoFactorCode.Value(), policy); err != nil {
+			m.twoFactorFailed(err)
+			return m, nil
+		}
+		m.twoFactorPolicy = policy
+		m.twoFactorCode.Reset()
+		m.twoFactorMessage = localization.Labels["two_factor_saved"]
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch m.twoFactorFocus {
+	case twoFactorRowThreshold:
+		m.twoFactorThreshold, cmd = m.twoFactorThreshold.Update(keyMsg)
+	case twoFactorRowCode:
+		m.twoFactorCode, cmd = m.twoFactorCode.Update(keyMsg)
+	}
+	return m, cmd
+}
+
+func (m *CLIModel) updateTwoFactorChallenge(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch keyMsg.String() {
+	case "esc":
+		m.leaveTwoFactor(m.twoFactorReturn)
+		return m, nil
+	case "enter":
+		if err := m.TwoFactor.Verify(&m.twoFactorQueue[0], m.twoFactorCode.Value()); err != nil {
+			m.twoFactorFailed(err)
+			return m, nil
+		}
+		m.twoFactorQueue = m.twoFactorQueue[1:]
+		if len(m.twoFactorQueue) > 0 {
+			m.twoFactorCode.Reset()
+			m.twoFactorMessage = ""
+			return m, nil
+		}
+		done := m.twoFactorDone
+		m.leaveTwoFactor(m.twoFactorReturn)
+		return m, done()
+	}
+	var cmd tea.Cmd
+	m.twoFactorCode, cmd = m.twoFactorCode.Update(keyMsg)
+	return m, cmd
+}
+
+// viewTwoFactor renderiza o cadastro, as configurações e o pedido de código
+func (m *CLIModel) viewTwoFactor() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
+	var parts []string
+	switch m.twoFactorMode {
+	case twoFactorEnroll:
+		codeStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD700"))
+		parts = append(parts,
+			titleStyle.Render(localization.Labels["two_factor_enroll_title"]), "",
+			fmt.Sprintf(localization.Labels["two_factor_enroll_desc"], m.twoFactorWallet.Address), "")
+		if m.twoFactorQR != "" {
+			parts = append(parts, m.twoFactorQR, "")
+		}
+		parts = append(parts,
+			fmt.Sprintf("%-*s %s", 10, localization.Labels["two_factor_secret"], codeStyle.Render(m.twoFactorEnrollment.Secret)),
+			fmt.Sprintf("%-*s %s", 10, localization.Labels["two_factor_uri"], m.twoFactorEnrollment.URI), "",
+			localization.Labels["two_factor_recovery_codes"])
+		codes := m.twoFactorEnrollment.RecoveryCodes
+		for i := 0; i < len(codes); i += 2 {
+			line := "  " + codeStyle.Render(codes[i])
+			if i+1 < len(codes) {
+				line += "    " + codeStyle.Render(codes[i+1])
+			}
+			parts = append(parts, line)
+		}
+		parts = append(parts, "", m.twoFactorCode.View())
+		if m.twoFactorMessage != "" {
+			parts = append(parts, "", m.twoFactorMessage)
+		}
+		parts = append(parts, "", localization.Labels["two_factor_enroll_hint"])
+
+	case twoFactorSettings:
+		parts = append(parts,
+			titleStyle.Render(fmt.Sprintf(localization.Labels["two_factor_settings_title"], m.twoFactorWallet.Address)), "",
+			localization.Labels["two_factor_settings_desc"], "")
+		options := []struct {
+			label   string
+			enabled bool
+		}{
+			{"two_factor_reveal", m.twoFactorPolicy.Reveal},
+			{"two_factor_export", m.twoFactorPolicy.Export},
+			{"two_factor_delete", m.twoFactorPolicy.Delete},
+		}
+		for i, option := range options {
+			mark := " "
+			if option.enabled {
+				mark = "x"
+			}
+			parts = append(parts, m.twoFactorRow(i, fmt.Sprintf("[%s] %s", mark, localization.Labels[option.label])))
+		}
+		parts = append(parts,
+			m.twoFactorRow(twoFactorRowThreshold, fmt.Sprintf(localization.Labels["two_factor_threshold"], constants.DefaultNativeSymbol)+" "+m.twoFactorThreshold.View()),
+			"",
+			m.twoFactorRow(twoFactorRowCode, m.twoFactorCode.View()))
+		if status, err := m.TwoFactor.Status(m.twoFactorWallet.ID); err == nil && status != nil {
+			parts = append(parts, "", fmt.Sprintf(localization.Labels["two_factor_recovery_left"], len(status.RecoveryCodes)))
+		}
+		if m.twoFactorMessage != "" {
+			parts = append(parts, "", m.twoFactorMessage)
+		}
+		parts = append(parts, "", localization.Labels["two_factor_settings_hint"])
+
+	default:
+		wallet := m.twoFactorQueue[0]
+		parts = append(parts,
+			titleStyle.Render(localization.Labels["two_factor_challenge_title"]), "",
+			fmt.Sprintf(localization.Labels["two_factor_challenge_desc"],
+				localization.Labels["two_factor_op_"+string(m.twoFactorOperation)], m.walletAddressLabel(wallet.Address)))
+		if m.twoFactorTotal > 1 {
+			confirmed := m.twoFactorTotal - len(m.twoFactorQueue)
+			parts = append(parts, fmt.Sprintf(localization.Labels["two_factor_challenge_progress"], confirmed+1, m.twoFactorTotal))
+		}
+		parts = append(parts, "", m.twoFactorCode.View())
+		if m.twoFactorMessage != "" {
+			parts = append(parts, "", m.twoFactorMessage)
+		}
+		parts = append(parts, "", localization.Labels["two_factor_challenge_hint"])
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// twoFactorRow destaca a linha selecionada nas configurações
+func (m *CLIModel) twoFactorRow(row int, text string) string {
+	if row == m.twoFactorFocus {
+		return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4")).Render("▸ ") + text
+	}
+	return "  " + text
+}
+
+// renderTwoFactorStatus mostra nos detalhes o aviso e as teclas de dois fatores
+func (m *CLIModel) renderTwoFactorStatus() string {
+	if m.TwoFactor == nil {
+		return ""
+	}
+	var status string
+	if m.twoFactorNotice != "" {
+		status += m.twoFactorNotice + "\n"
+	}
+	return status + localization.Labels["two_factor_details_hint"] + "\n\n"
+}
+
+// renderQR desenha o QR code com meio blocos, duas linhas de módulos por linha
+// do terminal, claro sobre escuro fixo para ser lido em qualquer tema
+func renderQR(text string) (string, error) {
+	code, err := qr.Encode(text, qr.L)
+	if err != nil {
+		return "", err
+	}
+	const quiet = 2
+	light := func(x, y int) bool {
+		if x < 0 || y < 0 || x >= code.Size || y >= code.Size {
+			return true
+		}
+		return !code.Black(x, y)
+	}
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#000000"))
+	var lines []string
+	for y := -quiet; y < code.Size+quiet; y += 2 {
+		var line strings.Builder
+		for x := -quiet; x < code.Size+quiet; x++ {
+			top, bottom := light(x, y), light(x, y+1)
+			switch {
+			case top && bottom:
+				line.WriteString("█")
+			case top:
+				line.WriteString("▀")
+			case bottom:
+				line.WriteString("▄")
+			default:
+				line.WriteString(" ")
+			}
+		}
+		lines = append(lines, style.Render(line.String()))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,425 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Mensagens do fluxo de revisão, assinatura e transmissão de transações
+type txBuiltMsg struct {
+	tx  *types.Transaction
+	err error
+}
+
+type txSignedMsg struct {
+	tx  *types.Transaction
+	err error
+}
+
+type txSentMsg struct {
+	hash common.Hash
+	err  error
+}
+
+type txSimulatedMsg struct {
+	tx         *types.Transaction
+	simulation *usecases.Simulation
+	err        error
+}
+
+type txDecodedMsg struct {
+	tx   *types.Transaction
+	call *usecases.DecodedCall
+	err  error
+}
+
+// buildTxCmd prepara uma transação não assinada a partir da wallet desbloqueada
+func buildTxCmd(service *usecases.TransactionService, from common.Address, to *common.Address, value *big.Int, data []byte) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		tx, err := service.BuildTransaction(ctx, from, to, value, data)
+		return txBuiltMsg{tx: tx, err: err}
+	}
+}
+
+func signTxCmd(service *usecases.TransactionService, details *usecases.WalletDetails, tx *types.Transaction, memo string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		signed, err := service.SignTransaction(ctx, details, tx, memo)
+		return txSignedMsg{tx: signed, err: err}
+	}
+}
+
+func sendTxCmd(service *usecases.TransactionService, tx *types.Transaction) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		err := service.SendTransaction(ctx, tx)
+		return txSentMsg{hash: tx.Hash(), err: err}
+	}
+}
+
+// decodeTxCmd decodifica os dados da chamada para exibição legível na revisão
+func decodeTxCmd(decoder *usecases.CalldataDecoder, tx *types.Transaction) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		call, err := decoder.Decode(ctx, tx.To(), tx.Data())
+		return txDecodedMsg{tx: tx, call: call, err: err}
+	}
+}
+
+// simulateTxCmd executa a transação não assinada no nó para prever o resultado
+func simulateTxCmd(simulator *usecases.SimulationService, from common.Address, tx *types.Transaction) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		simulation, err := simulator.Simulate(ctx, from, tx, nil)
+		return txSimulatedMsg{tx: tx, simulation: simulation, err: err}
+	}
+}
+
+// initTxReview abre a tela de revisão para uma transação já construída.
+// returnView indica para onde voltar ao cancelar ou concluir.
+func (m *CLIModel) initTxReview(tx *types.Transaction, memo string, returnView string) {
+	m.reviewTx = tx
+	m.reviewSigned = nil
+	m.reviewMemo = memo
+	m.reviewReturn = returnView
+	m.reviewBusy = false
+	m.reviewErr = nil
+	m.reviewMessage = ""
+	m.txNotice = ""
+	m.reviewDecoded = nil
+	m.reviewDecodeErr = nil
+	m.reviewDecoding = false
+	m.reviewSimulation = nil
+	m.reviewSimErr = nil
+	m.reviewSimulating = false
+	m.currentView = constants.TxReviewView
+}
+
+// reviewCmds dispara a decodificação e a simulação da transação em revisão
+func (m *CLIModel) reviewCmds() tea.Cmd {
+	tx := m.reviewTx
+	var cmds []tea.Cmd
+	// Contratos novos não têm seletor; transferências simples não têm dados
+	if m.Decoder != nil && tx.To() != nil && len(tx.Data()) > 0 {
+		m.reviewDecoding = true
+		cmds = append(cmds, decodeTxCmd(m.Decoder, tx))
+	}
+	if cmd := m.simulateReview(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *CLIModel) simulateReview() tea.Cmd {
+	if m.Simulator == nil || m.walletDetails == nil {
+		return nil
+	}
+	m.reviewSimulating = true
+	m.reviewSimErr = nil
+	return simulateTxCmd(m.Simulator, common.HexToAddress(m.walletDetails.Wallet.Address), m.reviewTx)
+}
+
+// closeTxReview descarta a transação em revisão e volta para a tela de origem
+func (m *CLIModel) closeTxReview() {
+	m.reviewTx = nil
+	m.reviewSigned = nil
+	m.currentView = m.reviewReturn
+}
+
+func (m *CLIModel) updateTxReview(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.reviewBusy {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "enter", "s":
+		if m.reviewSigned == nil && m.walletDetails != nil {
+			details, tx, memo := m.walletDetails, m.reviewTx, m.reviewMemo
+			targets := []twoFactorTarget{{wallet: *details.Wallet, value: usecases.SignedValue(tx)}}
+			return m, m.requireTwoFactor(domain.TwoFactorSign, targets, func() tea.Cmd {
+				m.reviewBusy = true
+				m.reviewErr = nil
+				m.reviewMessage = localization.Labels["tx_signing"]
+				return signTxCmd(m.Transactions, details, tx, memo)
+			})
+		}
+	case "r":
+		if m.reviewSigned == nil && !m.reviewSimulating {
+			return m, m.simulateReview()
+		}
+	case "b":
+		if m.reviewSigned != nil {
+			m.reviewBusy = true
+			m.reviewErr = nil
+			m.reviewMessage = localization.Labels["tx_broadcasting"]
+			return m, sendTxCmd(m.Transactions, m.reviewSigned)
+		}
+	}
+	return m, nil
+}
+
+// applyTxMsg trata os resultados assíncronos do fluxo de transações
+func (m *CLIModel) applyTxMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case txBuiltMsg:
+		if msg.err != nil {
+			log.Println("Erro ao construir a transação:", msg.err)
+			m.reviewErr = msg.err
+			m.txNotice = fmt.Sprintf(localization.Labels["tx_error"], msg.err)
+			return nil
+		}
+		m.initTxReview(msg.tx, m.reviewMemo, m.currentView)
+		return m.reviewCmds()
+	case txSimulatedMsg:
+		if msg.tx != m.reviewTx {
+			return nil
+		}
+		m.reviewSimulating = false
+		m.reviewSimulation = msg.simulation
+		m.reviewSimErr = msg.err
+		if msg.err != nil {
+			log.Println("Erro ao simular a transação:", msg.err)
+		}
+	case txDecodedMsg:
+		if msg.tx != m.reviewTx {
+			return nil
+		}
+		m.reviewDecoding = false
+		m.reviewDecoded = msg.call
+		m.reviewDecodeErr = msg.err
+		if msg.err != nil {
+			log.Println("Erro ao decodificar os dados da transação:", msg.err)
+		}
+	case txSignedMsg:
+		m.reviewBusy = false
+		if msg.err != nil {
+			log.Println("Erro ao assinar a transação:", msg.err)
+			m.reviewErr = msg.err
+			m.reviewMessage = ""
+			return nil
+		}
+		m.reviewSigned = msg.tx
+		m.reviewMessage = localization.Labels["tx_signed"]
+	case txSentMsg:
+		m.reviewBusy = false
+		if msg.err != nil {
+			log.Println("Erro ao transmitir a transação:", msg.err)
+			m.reviewErr = msg.err
+			m.reviewMessage = ""
+			return nil
+		}
+		m.reviewMessage = fmt.Sprintf(localization.Labels["tx_broadcast_done"], msg.hash.Hex())
+		m.saveDeployedContract(m.reviewSigned)
+	}
+	return nil
+}
+
+// viewTxReview renderiza os campos da transação antes da assinatura
+func (m *CLIModel) viewTxReview() string {
+	if m.reviewTx == nil {
+		return localization.Labels["unknown_state"]
+	}
+	tx := m.reviewTx
+	labelWidth := 20
+	line := func(label, value string) string {
+		return fmt.Sprintf("%-*s %s\n", labelWidth, localization.Labels[label], value)
+	}
+
+	var view strings.Builder
+	view.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4")).Render(localization.Labels["tx_review_title"]) + "\n\n")
+	if m.walletDetails != nil {
+		view.WriteString(line("tx_from", m.walletDetails.Wallet.Address))
+	}
+	to := localization.Labels["tx_contract_creation"]
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	view.WriteString(line("tx_to", to))
+	view.WriteString(line("tx_value", fmt.Sprintf("%s %s", usecases.FormatUnits(tx.Value(), constants.NativeDecimals), constants.DefaultNativeSymbol)))
+	view.WriteString(line("tx_nonce", fmt.Sprintf("%d", tx.Nonce())))
+	view.WriteString(line("tx_gas_limit", fmt.Sprintf("%d", tx.Gas())))
+	view.WriteString(line("tx_max_fee_per_gas", fmt.Sprintf("%s gwei", usecases.FormatUnits(tx.GasFeeCap(), 9))))
+	maxFee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+	view.WriteString(line("tx_max_fee", fmt.Sprintf("%s %s", usecases.FormatUnits(maxFee, constants.NativeDecimals), constants.DefaultNativeSymbol)))
+	view.WriteString(line("tx_data", summarizeCalldata(tx.Data())))
+	view.WriteString(m.viewDecodedCalldata())
+	view.WriteString(m.viewSimulation())
+	if m.reviewMemo != "" {
+		view.WriteString(line("ledger_memo", m.reviewMemo))
+	}
+
+	if m.reviewSigned != nil {
+		view.WriteString("\n")
+		view.WriteString(line("tx_hash", m.reviewSigned.Hash().Hex()))
+		if raw, err := m.reviewSigned.MarshalBinary(); err == nil {
+			view.WriteString(line("tx_raw", shortRaw(hexutil.Encode(raw))))
+		}
+	}
+
+	view.WriteString("\n")
+	if m.reviewErr != nil {
+		view.WriteString(m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["tx_error"], m.reviewErr)) + "\n")
+	}
+	if m.reviewMessage != "" {
+		view.WriteString(m.reviewMessage + "\n")
+	}
+	if m.reviewSigned == nil {
+		view.WriteString(localization.Labels["tx_sign_prompt"])
+	} else {
+		view.WriteString(localization.Labels["tx_broadcast_prompt"])
+	}
+	return view.String()
+}
+
+// viewDecodedCalldata mostra a função chamada, os parâmetros formatados e os alertas
+func (m *CLIModel) viewDecodedCalldata() string {
+	switch {
+	case m.reviewDecoding:
+		return localization.Labels["tx_decoding"] + "\n"
+	case m.reviewDecodeErr != nil:
+		return m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["tx_decode_failed"], m.reviewDecodeErr)) + "\n"
+	case m.reviewDecoded != nil:
+		return "\n" + m.renderDecodedCall(m.reviewDecoded, "")
+	}
+	return ""
+}
+
+func (m *CLIModel) renderDecodedCall(call *usecases.DecodedCall, indent string) string {
+	warningStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5F5F"))
+
+	var view strings.Builder
+	view.WriteString(fmt.Sprintf("%s%s %s [%s]\n", indent, localization.Labels["tx_function"], call.Signature, call.Source))
+	for i, param := range call.Params {
+		name := param.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		value := param.Value
+		if param.Warning != "" {
+			value = warningStyle.Render(value + " ⚠ " + localization.Labels["tx_warn_"+string(param.Warning)])
+		}
+		view.WriteString(fmt.Sprintf("%s  %s (%s): %s\n", indent, name, param.Type, value))
+	}
+	for _, warning := range call.Warnings {
+		// Alertas de parâmetros já aparecem ao lado do valor
+		if warning == usecases.WarnDelegateCall || warning == usecases.WarnSelectorCollision {
+			view.WriteString(indent + warningStyle.Render("⚠ "+localization.Labels["tx_warn_"+string(warning)]) + "\n")
+		}
+	}
+	if call.InnerErr != nil {
+		view.WriteString(indent + "  " + m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["tx_decode_failed"], call.InnerErr)) + "\n")
+	}
+	if call.Inner != nil {
+		view.WriteString(m.renderDecodedCall(call.Inner, indent+"  "))
+	}
+	return view.String()
+}
+
+// viewSimulation mostra o resultado previsto: sucesso ou motivo da reversão, gás e variações de saldo
+func (m *CLIModel) viewSimulation() string {
+	warningStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5F5F"))
+	successStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#04B575"))
+
+	switch {
+	case m.reviewSimulating:
+		return "\n" + localization.Labels["tx_simulating"] + "\n"
+	case m.reviewSimErr != nil:
+		return "\n" + m.styles.ErrorStyle.Render(fmt.Sprintf(localization.Labels["tx_simulation_error"], m.reviewSimErr)) + "\n"
+	case m.reviewSimulation == nil:
+		return ""
+	}
+
+	simulation := m.reviewSimulation
+	var view strings.Builder
+	view.WriteString("\n")
+	if !simulation.Success {
+		reason := simulation.RevertReason
+		if reason == "" {
+			reason = "-"
+		}
+		view.WriteString(warningStyle.Render(fmt.Sprintf(localization.Labels["tx_simulation_reverted"], reason)) + "\n")
+		return view.String()
+	}
+	view.WriteString(successStyle.Render(localization.Labels["tx_simulation_success"]) + "\n")
+	view.WriteString(fmt.Sprintf(localization.Labels["tx_simulation_gas"], simulation.GasUsed,
+		usecases.FormatUnits(simulation.Fee, constants.NativeDecimals), constants.DefaultNativeSymbol) + "\n")
+
+	if len(simulation.Deltas) == 0 {
+		view.WriteString(localization.Labels["tx_simulation_no_changes"] + "\n")
+	} else {
+		view.WriteString(localization.Labels["tx_simulation_changes"] + "\n")
+	}
+	for _, delta := range simulation.Deltas {
+		amount := usecases.FormatUnits(delta.Amount, delta.Decimals)
+		if delta.Amount.Sign() > 0 {
+			amount = "+" + amount
+		}
+		asset := delta.Symbol
+		switch {
+		case delta.Token == "":
+			asset = constants.DefaultNativeSymbol
+		case delta.TokenID != "":
+			asset = fmt.Sprintf("%s #%s", shortHex(delta.Token), delta.TokenID)
+		case asset == "":
+			asset = shortHex(delta.Token)
+		}
+		style := successStyle
+		if delta.Amount.Sign() < 0 {
+			style = warningStyle
+		}
+		view.WriteString("  " + style.Render(fmt.Sprintf("%s %s", amount, asset)) + "\n")
+	}
+	if !simulation.LogsAvailable {
+		view.WriteString(localization.Labels["tx_simulation_no_logs"] + "\n")
+	}
+	return view.String()
+}
+
+// summarizeCalldata mostra o seletor e o tamanho dos dados da chamada
+func summarizeCalldata(data []byte) string {
+	if len(data) == 0 {
+		return "-"
+	}
+	if len(data) < 4 {
+		return hexutil.Encode(data)
+	}
+	return fmt.Sprintf("%s… (%d bytes)", hexutil.Encode(data[:4]), len(data))
+}
+
+func shortRaw(raw string) string {
+	if len(raw) <= 72 {
+		return raw
+	}
+	return raw[:64] + "…" + raw[len(raw)-8:]
+}
+
+// newAddressInput cria o campo para digitação de endereços de destino
+func newAddressInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 253 // Endereço ou nome ENS
+	ti.Width = 44
+	ti.Focus()
+	return ti
+}
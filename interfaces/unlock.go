@@ -0,0 +1,190 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/domain"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Atualização da contagem regressiva da espera entre tentativas
+const unlockRefresh = time.Second
+
+type unlockTickMsg struct{}
+
+func unlockTickCmd() tea.Cmd {
+	return tea.Tick(unlockRefresh, func(time.Time) tea.Msg {
+		return unlockTickMsg{}
+	})
+}
+
+// unlockReleasedMsg traz o resultado da senha mestra, verificada fora do loop de eventos
+type unlockReleasedMsg struct {
+	wallet *domain.Wallet
+	err    error
+}
+
+func releaseLockoutCmd(guard *usecases.UnlockGuard, wallet *domain.Wallet, masterPassword string) tea.Cmd {
+	return func() tea.Msg {
+		return unlockReleasedMsg{wallet: wallet, err: guard.Release(wallet, masterPassword)}
+	}
+}
+
+// loadUnlockStatus lê as tentativas da wallet ao abrir a tela de senha
+func (m *CLIModel) loadUnlockStatus() tea.Cmd {
+	m.unlockStatus = usecases.UnlockStatus{Remaining: -1}
+	m.unlockMessage = ""
+	m.unlockRelease = false
+	m.unlockBusy = false
+	if m.Service.Guard == nil || m.selectedWallet == nil {
+		return nil
+	}
+	status, err := m.Service.Guard.Status(m.selectedWallet)
+	if err != nil {
+		log.Println("Erro ao ler as tentativas de senha:", err)
+		return nil
+	}
+	m.unlockStatus = status
+	return m.watchUnlockWait()
+}
+
+// watchUnlockWait mantém a contagem regressiva enquanto houver espera, com um
+// único tick pendente por vez
+func (m *CLIModel) watchUnlockWait() tea.Cmd {
+	if m.unlockTicking || m.unlockStatus.Wait() <= 0 {
+		return nil
+	}
+	m.unlockTicking = true
+	return unlockTickCmd()
+}
+
+func (m *CLIModel) applyUnlockTick() tea.Cmd {
+	m.unlockTicking = false
+	if m.currentView != constants.WalletPasswordView {
+		return nil
+	}
+	return m.watchUnlockWait()
+}
+
+// applyUnlockError mostra o resultado da tentativa recusada e limpa a senha;
+// informa se o erro veio do limite de tentativas
+func (m *CLIModel) applyUnlockError(err error) (tea.Cmd, bool) {
+	var unlockErr *usecases.UnlockError
+	if !errors.As(err, &unlockErr) {
+		return nil, false
+	}
+	m.unlockStatus = unlockErr.Status
+	m.unlockMessage = localization.Labels["unlock_blocked"]
+	if errors.Is(err, domain.ErrIncorrectPassword) {
+		m.unlockMessage = localization.Labels["unlock_wrong_password"]
+	}
+	m.passwordInput.Reset()
+	return m.watchUnlockWait(), true
+}
+
+// startLockoutRelease troca o campo de senha pela senha mestra
+func (m *CLIModel) startLockoutRelease() {
+	if !m.unlockStatus.LockedOut || m.unlockStatus.Wait() <= 0 || m.Service.Guard == nil || m.Service.Guard.Vault == nil {
+		return
+	}
+	m.unlockRelease = true
+	m.unlockMessage = ""
+	m.passwordInput = newPasswordInput(localization.Labels["unlock_release_placeholder"])
+}
+
+// stopLockoutRelease volta a pedir a senha da wallet
+func (m *CLIModel) stopLockoutRelease() {
+	m.unlockRelease = false
+	m.passwordInput = newPasswordInput(localization.Labels["enter_wallet_password"])
+}
+
+func (m *CLIModel) applyUnlockReleased(msg unlockReleasedMsg) tea.Cmd {
+	if !m.unlockRelease || m.selectedWallet == nil || msg.wallet.ID != m.selectedWallet.ID {
+		return nil
+	}
+	m.unlockBusy = false
+	if msg.err != nil {
+		if !errors.Is(msg.err, usecases.ErrWrongMasterPassword) && !errors.Is(msg.err, usecases.ErrUnlockBlocked) {
+			log.Println("Erro ao encerrar o bloqueio da wallet:", msg.err)
+		}
+		m.passwordInput.Reset()
+		m.unlockMessage = fmt.Sprintf(localization.Labels["unlock_release_error"], vaultErrorText(msg.err))
+		return nil
+	}
+	m.stopLockoutRelease()
+	cmd := m.loadUnlockStatus()
+	m.unlockMessage = localization.Labels["unlock_released"]
+	return cmd
+}
+
+// updateLockoutRelease trata a tela de senha enquanto ela recebe a senha mestra
+func (m *CLIModel) updateLockoutRelease(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.unlockBusy {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.stopLockoutRelease()
+		m.unlockMessage = ""
+		return m, nil
+	case "enter":
+		if m.passwordInput.Value() == "" {
+			return m, nil
+		}
+		m.unlockBusy = true
+		m.unlockMessage = localization.Labels["vault_working"]
+		return m, releaseLockoutCmd(m.Service.Guard, m.selectedWallet, m.passwordInput.Value())
+	}
+	var cmd tea.Cmd
+	m.passwordInput, cmd = m.passwordInput.Update(msg)
+	return m, cmd
+}
+
+// renderUnlockStatus mostra as tentativas restantes, a espera e o bloqueio
+func (m *CLIModel) renderUnlockStatus() string {
+	var lines []string
+	if m.unlockMessage != "" {
+		lines = append(lines, m.unlockMessage)
+	}
+	lines = append(lines, unlockStatusLines(m.unlockStatus)...)
+	if m.unlockStatus.LockedOut && m.unlockStatus.Wait() > 0 &&
+		m.Service.Guard != nil && m.Service.Guard.Vault != nil && !m.unlockRelease {
+		lines = append(lines, localization.Labels["unlock_release_hint"])
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...) + "\n\n"
+}
+
+// unlockStatusLines descreve a espera ou o bloqueio e as tentativas restantes
+func unlockStatusLines(status usecases.UnlockStatus) []string {
+	warning := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
+	var lines []string
+	wait := status.Wait()
+	switch {
+	case status.LockedOut && wait > 0:
+		lines = append(lines, warning.Render(fmt.Sprintf(localization.Labels["unlock_locked_out"],
+			status.Failures, status.RetryAt.Format("15:04:05"), formatSessionTime(wait))))
+	case wait > 0:
+		lines = append(lines, warning.Render(fmt.Sprintf(localization.Labels["unlock_wait"], formatSessionTime(wait))))
+	}
+	if status.Failures > 0 && status.Remaining > 0 && !status.LockedOut {
+		lines = append(lines, fmt.Sprintf(localization.Labels["unlock_attempts_left"], status.Remaining))
+	}
+	return lines
+}
+
+// resetUnlockPrompt descarta a senha mestra digitada para encerrar o bloqueio
+func (m *CLIModel) resetUnlockPrompt() {
+	m.unlockRelease = false
+	m.unlockBusy = false
+	m.unlockMessage = ""
+}
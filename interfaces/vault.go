@@ -0,0 +1,317 @@
+package interfaces
+
+import (
+	"blocowallet/constants"
+	"blocowallet/localization"
+	"blocowallet/usecases"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Etapas da tela da senha mestra
+const (
+	vaultStepUnlock      = iota
+	vaultStepSetup       // Primeira execução: definir a senha
+	vaultStepRecover     // Chave de recuperação e nova senha
+	vaultStepRecoveryKey // Exibição da chave de recuperação
+	vaultStepChange      // Troca da senha a partir do menu
+	vaultStepNewRecovery // Senha atual para gerar outra chave de recuperação
+	vaultStepResume      // Bloqueio por inatividade sem senha mestra definida
+)
+
+// vaultDoneMsg traz o resultado da derivação da chave, feita fora do loop de eventos
+type vaultDoneMsg struct {
+	step        int
+	recoveryKey string
+	err         error
+}
+
+func vaultCmd(step int, action func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		recoveryKey, err := action()
+		return vaultDoneMsg{step: step, recoveryKey: recoveryKey, err: err}
+	}
+}
+
+// vaultLocked indica se a aplicação aguarda a senha mestra
+func (m *CLIModel) vaultLocked() bool {
+	return m.Vault != nil && m.Vault.Locked()
+}
+
+// initVault abre a tela de desbloqueio ou, sem senha mestra, a de definição
+func (m *CLIModel) initVault() {
+	initialized, err := m.Vault.Initialized()
+	if err != nil {
+		log.Println("Erro ao ler a senha mestra:", err)
+	}
+	if initialized || err != nil {
+		m.setVaultStep(vaultStepUnlock)
+	} else {
+		m.setVaultStep(vaultStepSetup)
+	}
+	m.currentView = constants.VaultView
+}
+
+// setVaultStep prepara os campos da etapa, descartando o que foi digitado antes
+func (m *CLIModel) setVaultStep(step int) {
+	m.vaultStep = step
+	m.vaultFocus = 0
+	m.vaultMessage = ""
+	m.vaultBusy = false
+	switch step {
+	case vaultStepUnlock, vaultStepNewRecovery:
+		m.vaultInputs = []textinput.Model{newPasswordInput(localization.Labels["vault_password"])}
+	case vaultStepSetup:
+		m.vaultInputs = []textinput.Model{
+			newPasswordInput(localization.Labels["vault_new_password"]),
+			newPasswordInput(localization.Labels["vault_confirm_password"]),
+		}
+	case vaultStepRecover:
+		recoveryKey := textinput.New()
+		recoveryKey.Placeholder = localization.Labels["vault_recovery_key"]
+		recoveryKey.CharLimit = 64
+		recoveryKey.Width = 48
+		recoveryKey.Focus()
+		m.vaultInputs = []textinput.Model{
+			recoveryKey,
+			newPasswordInput(localization.Labels["vault_new_password"]),
+			newPasswordInput(localization.Labels["vault_confirm_password"]),
+		}
+	case vaultStepChange:
+		m.vaultInputs = []textinput.Model{
+			newPasswordInput(localization.Labels["vault_password"]),
+			newPasswordInput(localization.Labels["vault_new_password"]),
+			newPasswordInput(localization.Labels["vault_confirm_password"]),
+		}
+	default:
+		m.vaultInputs = nil
+	}
+	for i := 1; i < len(m.vaultInputs); i++ {
+		m.vaultInputs[i].Blur()
+	}
+}
+
+// leaveVault volta ao menu principal e carrega o que dependia do desbloqueio
+func (m *CLIModel) leaveVault(unlocked bool) tea.Cmd {
+	m.vaultInputs = nil
+	m.vaultRecoveryKey = ""
+	m.vaultMessage = ""
+	m.menuItems = m.mainMenu()
+	m.selectedMenu = 0
+	m.currentView = constants.DefaultView
+	if !unlocked {
+		return nil
+	}
+	cmds := []tea.Cmd{walletCountCmd(m.Service)}
+	if m.Portfolio != nil {
+		cmds = append(cmds, fetchPortfolioCmd(m.Service, m.Portfolio))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *CLIModel) focusVaultInput(index int) {
+	m.vaultInputs[m.vaultFocus].Blur()
+	m.vaultFocus = (index + len(m.vaultInputs)) % len(m.vaultInputs)
+	m.vaultInputs[m.vaultFocus].Focus()
+}
+
+// submitVault valida os campos da etapa e dispara a derivação da chave
+func (m *CLIModel) submitVault() tea.Cmd {
+	values := make([]string, len(m.vaultInputs))
+	for i, input := range m.vaultInputs {
+		values[i] = input.Value()
+	}
+
+	// Nova senha e confirmação são sempre os dois últimos campos
+	if m.vaultStep == vaultStepSetup || m.vaultStep == vaultStepRecover || m.vaultStep == vaultStepChange {
+		newPassword, confirmation := values[len(values)-2], values[len(values)-1]
+		if len(strings.TrimSpace(newPassword)) < constants.PasswordMinLength {
+			m.retypeVaultPassword(localization.Labels["password_too_short"])
+			return nil
+		}
+		if newPassword != confirmation {
+			m.retypeVaultPassword(localization.Labels["vault_password_mismatch"])
+			return nil
+		}
+	}
+
+	vault := m.Vault
+	step := m.vaultStep
+	m.vaultBusy = true
+	m.vaultMessage = localization.Labels["vault_working"]
+	switch step {
+	case vaultStepUnlock:
+		return vaultCmd(step, func() (string, error) { return "", vault.Unlock(values[0]) })
+	case vaultStepSetup:
+		return vaultCmd(step, func() (string, error) { return vault.Setup(values[0]) })
+	case vaultStepRecover:
+		return vaultCmd(step, func() (string, error) { return "", vault.Recover(values[0], values[1]) })
+	case vaultStepChange:
+		return vaultCmd(step, func() (string, error) { return "", vault.ChangePassword(values[0], values[1]) })
+	case vaultStepNewRecovery:
+		return vaultCmd(step, func() (string, error) { return vault.NewRecoveryKey(values[0]) })
+	}
+	m.vaultBusy = false
+	return nil
+}
+
+// retypeVaultPassword limpa a nova senha e a confirmação para serem digitadas de novo
+func (m *CLIModel) retypeVaultPassword(message string) {
+	last := len(m.vaultInputs) - 1
+	m.vaultInputs[last-1].Reset()
+	m.vaultInputs[last].Reset()
+	m.focusVaultInput(last - 1)
+	m.vaultMessage = message
+}
+
+func (m *CLIModel) applyVaultMsg(msg vaultDoneMsg) tea.Cmd {
+	if m.currentView != constants.VaultView || msg.step != m.vaultStep {
+		return nil
+	}
+	m.vaultBusy = false
+	if msg.err != nil {
+		if !errors.Is(msg.err, usecases.ErrWrongMasterPassword) && !errors.Is(msg.err, usecases.ErrInvalidRecoveryKey) &&
+			!errors.Is(msg.err, usecases.ErrUnlockBlocked) {
+			log.Println("Erro na senha mestra:", msg.err)
+		}
+		// Os campos são limpos para a próxima tentativa
+		m.setVaultStep(msg.step)
+		m.vaultMessage = fmt.Sprintf(localization.Labels["vault_error"], vaultErrorText(msg.err))
+		return nil
+	}
+
+	switch msg.step {
+	case vaultStepUnlock, vaultStepRecover:
+		return m.leaveVault(true)
+	case vaultStepChange:
+		m.setVaultStep(vaultStepChange)
+		m.vaultMessage = localization.Labels["vault_password_changed"]
+	case vaultStepSetup, vaultStepNewRecovery:
+		// A chave só é exibida agora; a primeira definição também desbloqueia a aplicação
+		m.setVaultStep(vaultStepRecoveryKey)
+		m.vaultRecoveryKey = msg.recoveryKey
+		m.vaultUnlockOnContinue = msg.step == vaultStepSetup
+	}
+	return nil
+}
+
+// vaultErrorText traduz o erro, com as tentativas restantes da senha mestra
+func vaultErrorText(err error) string {
+	text := err.Error()
+	switch {
+	case errors.Is(err, usecases.ErrWrongMasterPassword):
+		text = localization.Labels["vault_wrong_password"]
+	case errors.Is(err, usecases.ErrUnlockBlocked):
+		text = localization.Labels["vault_blocked"]
+	case errors.Is(err, usecases.ErrInvalidRecoveryKey):
+		text = localization.Labels["vault_invalid_recovery_key"]
+	}
+	var unlockErr *usecases.UnlockError
+	if errors.As(err, &unlockErr) {
+		text = strings.Join(append([]string{text}, unlockStatusLines(unlockErr.Status)...), "\n")
+	}
+	return text
+}
+
+func (m *CLIModel) updateVault(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyMsg.String() == tea.KeyCtrlX.String() {
+		return m, tea.Quit
+	}
+	if m.vaultBusy {
+		return m, nil
+	}
+
+	// Etapas sem campos: apenas confirmar para seguir ao menu
+	if m.vaultStep == vaultStepRecoveryKey || m.vaultStep == vaultStepResume {
+		if keyMsg.String() == "enter" {
+			return m, m.leaveVault(m.vaultStep == vaultStepRecoveryKey && m.vaultUnlockOnContinue)
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		switch m.vaultStep {
+		case vaultStepRecover:
+			m.setVaultStep(vaultStepUnlock)
+		case vaultStepChange, vaultStepNewRecovery:
+			return m, m.leaveVault(false)
+		}
+		return m, nil
+	case "ctrl+r":
+		switch m.vaultStep {
+		case vaultStepUnlock:
+			m.setVaultStep(vaultStepRecover)
+		case vaultStepChange:
+			m.setVaultStep(vaultStepNewRecovery)
+		}
+		return m, nil
+	case "tab", "down":
+		m.focusVaultInput(m.vaultFocus + 1)
+		return m, nil
+	case "shift+tab", "up":
+		m.focusVaultInput(m.vaultFocus - 1)
+		return m, nil
+	case "enter":
+		if m.vaultFocus < len(m.vaultInputs)-1 {
+			m.focusVaultInput(m.vaultFocus + 1)
+			return m, nil
+		}
+		return m, m.submitVault()
+	}
+
+	var cmd tea.Cmd
+	m.vaultInputs[m.vaultFocus], cmd = m.vaultInputs[m.vaultFocus].Update(msg)
+	return m, cmd
+}
+
+// viewVault renderiza o desbloqueio, a definição e a troca da senha mestra
+func (m *CLIModel) viewVault() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
+
+	var title, description, hint string
+	switch m.vaultStep {
+	case vaultStepUnlock:
+		title, description, hint = "vault_unlock_title", "vault_unlock_desc", "vault_unlock_hint"
+	case vaultStepSetup:
+		title, description, hint = "vault_setup_title", "vault_setup_desc", "vault_form_hint"
+	case vaultStepRecover:
+		title, description, hint = "vault_recover_title", "vault_recover_desc", "vault_form_hint"
+	case vaultStepRecoveryKey:
+		title, description, hint = "vault_recovery_key_title", "vault_recovery_key_desc", "vault_recovery_key_hint"
+	case vaultStepChange:
+		title, description, hint = "vault_change_title", "vault_change_desc", "vault_change_hint"
+	case vaultStepNewRecovery:
+		title, description, hint = "vault_new_recovery_title", "vault_new_recovery_desc", "vault_form_hint"
+	case vaultStepResume:
+		title, description, hint = "session_locked_title", "session_locked_desc", "session_locked_hint"
+	}
+
+	parts := []string{titleStyle.Render(localization.Labels[title]), "", localization.Labels[description], ""}
+	if m.vaultStep == vaultStepRecoveryKey {
+		keyStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FFD700")).
+			Border(lipgloss.RoundedBorder()).
+			Padding(0, 2)
+		parts = append(parts, keyStyle.Render(m.vaultRecoveryKey), "")
+	}
+	for _, input := range m.vaultInputs {
+		parts = append(parts, input.View())
+	}
+	if m.vaultMessage != "" {
+		parts = append(parts, "", m.vaultMessage)
+	}
+	parts = append(parts, "", localization.Labels[hint])
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
@@ -21,10 +21,17 @@ func (m *CLIModel) viewCreateWalletPassword() string {
 		return "Localization labels not initialized."
 	}
 
+	// Backends remotos geram a chave internamente, sem frase mnemônica
+	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FF00")).Render(localization.Labels["mnemonic_phrase"]) + "\n\n" +
+		fmt.Sprintf("%s\n\n", m.mnemonic)
+	if !m.Service.DefaultExportable() {
+		header = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FF00")).
+			Render(fmt.Sprintf(localization.Labels["key_created_in_backend"], m.Service.Default)) + "\n\n"
+	}
+
 	var view strings.Builder
 	view.WriteString(
-		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00FF00")).Render(localization.Labels["mnemonic_phrase"]) + "\n\n" +
-			fmt.Sprintf("%s\n\n", m.mnemonic) +
+		header +
 			localization.Labels["enter_password"] + "\n\n" +
 			m.passwordInput.View() + "\n\n" +
 			localization.Labels["press_enter"],
@@ -76,8 +83,12 @@ func (m *CLIModel) renderSplash() string {
 func (m *CLIModel) renderStatusBar() string {
 	// Left part: Number of wallets
 	leftStyle := m.styles.StatusBarLeft // Used assignment for copying.
+	walletsText := fmt.Sprintf("Wallets: %d", m.walletCount)
+	if m.vaultLocked() || m.sessionLocked() {
+		walletsText = localization.Labels["vault_locked"]
+	}
 	left := leftStyle.
-		SetString(fmt.Sprintf("Wallets: %d", m.walletCount)).
+		SetString(walletsText).
 		String()
 
 	// Right part: Current date and time
@@ -92,11 +103,35 @@ func (m *CLIModel) renderStatusBar() string {
 	if m.currentView == constants.ListWalletsView {
 		// Special case for wallet list view to include delete instruction
 		centerContent = fmt.Sprintf(localization.Labels["wallet_list_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.HistoryView {
+		centerContent = fmt.Sprintf(localization.Labels["history_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.LedgerView {
+		centerContent = fmt.Sprintf(localization.Labels["ledger_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.NFTView {
+		centerContent = fmt.Sprintf(localization.Labels["nft_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.ApprovalsView {
+		centerContent = fmt.Sprintf(localization.Labels["approval_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.DiscoveryView {
+		centerContent = fmt.Sprintf(localization.Labels["discovery_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.SweepView {
+		centerContent = fmt.Sprintf(localization.Labels["sweep_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.PaymentsView {
+		centerContent = fmt.Sprintf(localization.Labels["payment_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.ContractsView {
+		centerContent = fmt.Sprintf(localization.Labels["contract_status_bar"], localization.Labels[m.currentView])
+	} else if m.currentView == constants.VaultView {
+		centerContent = fmt.Sprintf(localization.Labels["vault_status_bar"], localization.Labels[m.currentView])
 	} else {
 		centerContent = fmt.Sprintf(localization.Labels["status_bar_instructions"], localization.Labels[m.currentView])
 	}
 
-	centerWidth := m.width - lipgloss.Width(left) - lipgloss.Width(right)
+	// Endpoint RPC ativo e sua saúde, quando a rede usa failover
+	rpcStatus := m.renderRPCStatus()
+
+	// Tempo restante até o bloqueio por inatividade
+	sessionStatus := m.renderSessionStatus()
+
+	centerWidth := m.width - lipgloss.Width(left) - lipgloss.Width(sessionStatus) - lipgloss.Width(rpcStatus) - lipgloss.Width(right)
 	centerStyle := m.styles.StatusBarCenter // Used assignment for copying.
 	center := centerStyle.
 		SetString(centerContent).
@@ -108,6 +143,8 @@ func (m *CLIModel) renderStatusBar() string {
 	statusBar := lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		left,
+		sessionStatus,
+		rpcStatus,
 		center,
 		right,
 	)
@@ -127,15 +164,23 @@ func (m *CLIModel) renderMainView() string {
 	walletCount := m.walletCount
 	currentTime := time.Now().Format("02-01-2006 15:04:05")
 
+	walletsLine := fmt.Sprintf("Wallets: %d", walletCount) + m.portfolioHeaderLine()
+	menuItems := m.renderMenuItems()
+
+	// Bloqueada pela senha mestra: sem menu nem informações das wallets
+	if m.vaultLocked() || m.sessionLocked() {
+		walletsLine = localization.Labels["vault_locked"]
+		menuItems = nil
+	}
+
 	headerLeft := lipgloss.JoinVertical(
 		lipgloss.Left,
 		renderedLogo,
-		fmt.Sprintf("Wallets: %d", walletCount),
+		walletsLine,
 		fmt.Sprintf("Date: %s", currentTime),
 		fmt.Sprintf("Version: %s", localization.Labels["version"]),
 	)
 
-	menuItems := m.renderMenuItems()
 	menuGrid := lipgloss.JoinVertical(lipgloss.Left, menuItems...)
 
 	// Montar header
@@ -163,6 +208,9 @@ func (m *CLIModel) renderMainView() string {
 
 	// Obter a visualização do conteúdo
 	content := m.getContentView()
+	if m.vaultLocked() || m.sessionLocked() {
+		content = m.viewVault()
+	}
 
 	// Renderizar conteúdo com altura ajustada
 	renderedContent := m.styles.Content.Height(contentHeight).Render(content)
@@ -406,9 +454,21 @@ func (m *CLIModel) viewWalletPassword() string {
 	}
 
 	var view strings.Builder
+	if m.unlockRelease {
+		view.WriteString(
+			lipgloss.NewStyle().Bold(true).Render(localization.Labels["unlock_release_title"]+"\n\n") +
+				m.renderUnlockStatus() +
+				m.passwordInput.View() + "\n\n" +
+				localization.Labels["unlock_release_help"],
+		)
+		return view.String()
+	}
 	view.WriteString(
 		lipgloss.NewStyle().Bold(true).Render(localization.Labels["enter_wallet_password"]+"\n\n") +
+			m.keyringPromptNotice() +
+			m.renderUnlockStatus() +
 			m.passwordInput.View() + "\n\n" +
+			m.renderRememberPassword() +
 			localization.Labels["press_enter"],
 	)
 	return view.String()
@@ -421,13 +481,27 @@ func (m *CLIModel) viewWalletDetails() string {
 	}
 
 	if m.walletDetails != nil {
+		// Chaves mantidas por backends remotos nunca são exportadas
+		privateKey := fmt.Sprintf("0x%x", crypto.FromECDSA(m.walletDetails.PrivateKey))
+		if m.walletDetails.PrivateKey == nil {
+			privateKey = fmt.Sprintf(localization.Labels["private_key_in_backend"], m.walletDetails.Wallet.Backend)
+		}
+		// Com dois fatores, a chave e o mnemônico só aparecem após o código
+		mnemonic := m.walletDetails.Mnemonic
+		if m.detailsHidden {
+			privateKey = localization.Labels["two_factor_hidden"]
+			mnemonic = localization.Labels["two_factor_hidden"]
+		}
 		var view strings.Builder
 		view.WriteString(
 			lipgloss.NewStyle().Bold(true).Render(localization.Labels["wallet_details_title"]+"\n\n") +
 				fmt.Sprintf("%-*s %s\n", 20, localization.Labels["ethereum_address"], m.walletDetails.Wallet.Address) +
-				fmt.Sprintf("%-*s 0x%x\n", 20, localization.Labels["private_key"], crypto.FromECDSA(m.walletDetails.PrivateKey)) +
+				fmt.Sprintf("%-*s %s\n", 20, localization.Labels["private_key"], privateKey) +
 				fmt.Sprintf("%-*s %x\n", 20, localization.Labels["public_key"], crypto.FromECDSAPub(m.walletDetails.PublicKey)) +
-				fmt.Sprintf("%-*s %s\n\n", 20, localization.Labels["mnemonic_phrase_label"], m.walletDetails.Mnemonic) +
+				fmt.Sprintf("%-*s %s\n\n", 20, localization.Labels["mnemonic_phrase_label"], mnemonic) +
+				m.renderKeyringStatus() +
+				m.renderTwoFactorStatus() +
+				localization.Labels["wallet_details_actions"] + "\n" +
 				localization.Labels["press_esc"],
 		)
 		return view.String()
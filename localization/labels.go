@@ -29,14 +29,53 @@ func SetLanguage(lang string, appDir string) error {
 		return err
 	}
 
+	// Arquivos gerados por versões anteriores não possuem os rótulos mais recentes
+	if defaultLabels, err := defaultLabelsFor(lang); err == nil {
+		if Labels == nil {
+			Labels = map[string]string{}
+		}
+		for key, value := range defaultLabels {
+			if _, ok := Labels[key]; !ok {
+				Labels[key] = value
+			}
+		}
+	}
+
 	return nil
 }
 
 func createDefaultLabels(lang, labelsPath string) error {
-	defaultLabels := map[string]string{}
+	defaultLabels, err := defaultLabelsFor(lang)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(defaultLabels)
+	if err != nil {
+		return err
+	}
+
+	appDir := filepath.Dir(labelsPath)
+	localesDir := filepath.Join(appDir, "locales")
+	if _, err := os.Stat(localesDir); os.IsNotExist(err) {
+		err := os.MkdirAll(localesDir, os.ModePerm)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.WriteFile(labelsPath, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func defaultLabelsFor(lang string) (map[string]string, error) {
 	switch lang {
 	case "en":
-		defaultLabels = map[string]string{
+		return map[string]string{
 			"welcome_message":          "Welcome to the BLOCO wallet Manager!\n\nSelect an option from the menu.",
 			"mnemonic_phrase":          "Mnemonic Phrase (Keep it Safe!):",
 			"enter_password":           "Enter a password to encrypt the wallet:",
@@ -44,7 +83,7 @@ func createDefaultLabels(lang, labelsPath string) error {
 			"import_wallet_title":      "Import an existing Wallet",
 			"wallet_list_instructions": "Use the arrow keys to navigate, Enter to view details, 'd' to delete a wallet, 'esc' to return to the menu.",
 			"status_bar_instructions":  "View: %s | Press 'esc' to return | Press 'q' to quit",
-			"wallet_list_status_bar":   "View: %s | Press 'd' to delete | Press 'esc' to return | Press 'q' to quit",
+			"wallet_list_status_bar":   "View: %s | 'h' history | 'd' delete | 'space' select | 's' sweep | 'esc' return | 'q' quit",
 			"enter_wallet_password":    "Enter the wallet password:",
 			"select_wallet_prompt":     "Select a wallet and enter the password to view the details.",
 			"wallet_details_title":     "Wallet Details",
@@ -91,9 +130,347 @@ func createDefaultLabels(lang, labelsPath string) error {
 			"confirm_delete_wallet":    "Are you sure you want to delete this wallet?",
 			"confirm":                  "Confirm",
 			"cancel":                   "Cancel",
-		}
+			"wallet_history":           "Transaction History",
+			"history_title":            "Transaction History: %s",
+			"history_kind_normal":      "Transactions",
+			"history_kind_internal":    "Internal",
+			"history_kind_token":       "Token Transfers",
+			"history_date":             "Date",
+			"history_hash":             "Hash",
+			"history_direction":        "Dir",
+			"history_counterparty":     "Counterparty",
+			"history_amount":           "Amount",
+			"history_status":           "Status",
+			"history_in":               "IN",
+			"history_out":              "OUT",
+			"history_self":             "SELF",
+			"history_failed":           "failed",
+			"history_ok":               "ok",
+			"history_loading":          "Loading history...",
+			"history_empty":            "No transactions found on this page.",
+			"history_error":            "Error loading history: %v",
+			"history_page_info":        "Page %d | Fetched at %s",
+			"history_stale":            "(offline copy)",
+			"history_status_bar":       "View: %s | 'tab' type | 'n'/'p' page | 'r' refresh | 'esc' return | 'q' quit",
+			"ledger":                   "Ledger",
+			"ledger_desc":              "Transactions signed or broadcast by BLOCO",
+			"ledger_title":             "Transaction Ledger",
+			"ledger_since":             "From",
+			"ledger_until":             "Until",
+			"ledger_invalid_date":      "Invalid date: %s",
+			"ledger_error":             "Ledger error: %v",
+			"ledger_exported":          "Ledger exported to %s",
+			"ledger_from":              "From",
+			"ledger_to":                "To",
+			"ledger_memo":              "Memo",
+			"ledger_all":               "All",
+			"ledger_filters":           "Wallet: %s | Status: %s",
+			"ledger_period":            "Period: %s → %s",
+			"ledger_empty":             "No ledger entries match the current filters.",
+			"ledger_status_bar":        "View: %s | 'w' wallet | 's' status | 'f' dates | 'x' clear | 'c' CSV | 'J' JSON | 'esc' return",
+			"wallet_nfts":              "NFTs",
+			"tx_review":                "Transaction Review",
+			"wallet_details_actions":   "Press 'n' for NFTs, 'a' for token approvals, 'c' for the contract workbench or 'p' for batch payments.",
+			"nft_title":                "NFTs held by %s",
+			"nft_loading":              "Scanning NFT holdings...",
+			"nft_error":                "Error loading NFTs: %v",
+			"nft_empty":                "No NFTs found for this wallet.",
+			"nft_recipient":            "Recipient address or ENS name:",
+			"nft_collection":           "Collection",
+			"nft_standard":             "Standard",
+			"nft_token_id":             "Token ID",
+			"nft_balance":              "Balance",
+			"nft_metadata":             "Metadata URI",
+			"nft_status_bar":           "View: %s | 't' transfer | 'r' refresh | 'esc' return | 'q' quit",
+			"invalid_address":          "Invalid Ethereum address or ENS name.",
+			"tx_error":                 "Transaction error: %v",
+			"tx_building":              "Preparing transaction...",
+			"tx_signing":               "Signing transaction...",
+			"tx_signed":                "Transaction signed and recorded in the ledger.",
+			"tx_broadcasting":          "Broadcasting transaction...",
+			"tx_broadcast_done":        "Transaction broadcast: %s",
+			"tx_review_title":          "Review Transaction",
+			"tx_from":                  "From:",
+			"tx_to":                    "To:",
+			"tx_contract_creation":     "(contract creation)",
+			"tx_value":                 "Value:",
+			"tx_nonce":                 "Nonce:",
+			"tx_gas_limit":             "Gas limit:",
+			"tx_max_fee_per_gas":       "Max fee per gas:",
+			"tx_max_fee":               "Max fee:",
+			"tx_data":                  "Data:",
+			"tx_hash":                  "Hash:",
+			"tx_raw":                   "Signed (raw):",
+			"tx_sign_prompt":           "Press Enter to sign, 'r' to simulate again or ESC to cancel.",
+			"tx_broadcast_prompt":      "Press 'b' to broadcast or ESC to return.",
+			"portfolio_value_column":   "Value (%s)",
+			"portfolio_header":         " | Portfolio: %s %s",
+			"portfolio_title":          "Portfolio",
+			"portfolio_loading":        "Loading prices...",
+			"portfolio_error":          "Price update failed: %v",
+			"portfolio_no_price":       "no price",
+			"portfolio_total":          "Total",
+			"portfolio_updated":        "Balances at %s",
+			"portfolio_priced":         "prices at %s",
+			"contracts":                "Contract Workbench",
+			"contract_status_bar":      "View: %s | 'esc' back | 'q' quit",
+			"contract_title":           "Contract workbench for %s",
+			"contract_error":           "Error: %v",
+			"contract_name":            "Name",
+			"contract_address":         "Contract address",
+			"contract_abi_path":        "ABI or artifact JSON file",
+			"contract_artifact_path":   "Artifact or ABI JSON file",
+			"contract_bytecode_path":   "Bytecode file (optional when the artifact has it)",
+			"contract_function":        "Function",
+			"contract_mutability":      "Mutability",
+			"contract_value":           "Value in %s (optional)",
+			"contract_no_bytecode":     "No bytecode found; provide a bytecode file.",
+			"contract_invalid_input":   "Invalid input: %v",
+			"contract_calling":         "Calling contract...",
+			"contract_no_outputs":      "Call succeeded with no return values.",
+			"contract_deployed":        "Contract address: %s (saved)",
+			"contract_empty":           "No saved contracts for this network.",
+			"contract_list_help":       "'enter' open | 'l' load ABI | 'd' deploy | 'x' remove",
+			"contract_load_title":      "Load contract ABI",
+			"contract_deploy_title":    "Deploy contract",
+			"contract_form_help":       "'tab' next field | 'enter' submit | 'esc' back",
+			"contract_no_functions":    "The ABI has no functions.",
+			"contract_functions_help":  "'enter' select function | 'esc' back",
+			"contract_constructor":     "constructor of %s",
+			"contract_no_inputs":       "No arguments.",
+			"contract_result":          "Result:",
+
+			"tx_decoding":                "Decoding calldata...",
+			"tx_decode_failed":           "WARNING: calldata could not be decoded (%v). Verify the raw data before signing.",
+			"tx_function":                "Function:",
+			"tx_warn_unlimited_approval": "UNLIMITED APPROVAL",
+			"tx_warn_approval_for_all":   "grants control of ALL tokens in the collection",
+			"tx_warn_delegatecall":       "Safe DELEGATECALL: the target code runs with the Safe's full permissions",
+			"tx_warn_selector_collision": "Several signatures share this selector; the match may be wrong",
+			"tx_simulating":              "Simulating transaction...",
+			"tx_simulation_error":        "Simulation unavailable: %v",
+			"tx_simulation_reverted":     "SIMULATION REVERTED: %s",
+			"tx_simulation_success":      "Simulation succeeded",
+			"tx_simulation_gas":          "Gas used: %d (fee ≈ %s %s)",
+			"tx_simulation_changes":      "Balance changes for the sender (excluding fee):",
+			"tx_simulation_no_changes":   "No balance changes detected for the sender (excluding fee).",
+			"tx_simulation_no_logs":      "The node does not support eth_simulateV1; token changes could not be determined.",
+			"wallet_approvals":           "Token Approvals",
+			"approval_status_bar":        "View: %s | 'space' select | 'A' select risky | 'v' revoke | 'r' rescan | 'esc' return",
+			"approval_title":             "Active token approvals of %s",
+			"approval_loading":           "Scanning Approval events...",
+			"approval_error":             "Error scanning approvals: %v",
+			"approval_empty":             "No active approvals found.",
+			"approval_token":             "Token",
+			"approval_spender":           "Spender",
+			"approval_kind":              "Type",
+			"approval_allowance":         "Allowance",
+			"approval_risk":              "Risk",
+			"approval_status":            "Revoke",
+			"approval_all_tokens":        "all tokens",
+			"approval_unlimited":         "UNLIMITED",
+			"approval_limited":           "limited",
+			"approval_high":              "HIGH",
+			"approval_failed":            "failed",
+			"approval_revokes_sent":      "%d revoke transaction(s) broadcast.",
+			"approval_revoking":          "Signing and broadcasting revokes...",
+			"approval_none_selected":     "Select approvals with 'space' first.",
+			"approval_confirm_title":     "%d revoke transaction(s) will be signed by this wallet:",
+			"approval_confirm_fee":       "Maximum total fee: %s %s",
+			"approval_confirm_prompt":    "Press Enter to sign and broadcast all, or ESC to cancel.",
+			"ens_resolving":              "Resolving %s...",
+			"ens_resolve_error":          "Could not resolve %s: %v",
+			"account_discovery":          "Account Discovery",
+			"discovery_status_bar":       "View: %s | 'space' select | 'A' select all | 'enter' register | 'esc' continue",
+			"discovery_title":            "Used accounts derived from this mnemonic",
+			"discovery_loading":          "Scanning derived addresses (stops after %d unused in a row)...",
+			"discovery_error":            "Error discovering accounts: %v",
+			"discovery_path":             "Path",
+			"discovery_nonce":            "Nonce",
+			"discovery_balance":          "Balance",
+			"discovery_status":           "Status",
+			"discovery_registered":       "registered",
+			"discovery_none_selected":    "No accounts selected.",
+			"discovery_registering":      "Registering the selected accounts...",
+			"discovery_registered_count": "%d account(s) registered. Press 'esc' to continue.",
+			"discovery_register_error":   "Error registering accounts: %v",
+			"dev_mine_block":             "Mine Block",
+			"dev_mine_block_desc":        "Seal pending transactions on the local dev chain",
+			"dev_mining":                 "Mining a block...",
+			"dev_block_mined":            "Block #%d mined.",
+			"dev_mine_error":             "Error mining block: %v",
+			"dev_running":                "Dev mode: local chain (ID 1337) serving JSON-RPC at %s",
+			"wallet_sweep":               "Sweep",
+			"sweep_status_bar":           "View: %s | 'tab' asset | 'enter' continue | 'esc' back/skip locked",
+			"sweep_title":                "Sweep %d wallet(s) into one destination",
+			"sweep_destination":          "Destination address or ENS name:",
+			"sweep_asset":                "Asset: %s (press 'tab' to change)",
+			"sweep_summary":              "%s → %s | total %s",
+			"sweep_wallet":               "Wallet",
+			"sweep_balance":              "Balance",
+			"sweep_amount":               "Amount",
+			"sweep_fee":                  "Fee",
+			"sweep_status":               "Status",
+			"sweep_planning":             "Reading balances and estimating fees...",
+			"sweep_error":                "Error planning the sweep: %v",
+			"sweep_nothing":              "No wallet has a sweepable balance.",
+			"sweep_plan_prompt":          "%d transaction(s) planned. Press 'enter' to unlock the wallets.",
+			"sweep_password":             "Wallet password",
+			"sweep_password_prompt":      "Enter the password of the wallets (%d locked). Each password unlocks every wallet that shares it.",
+			"sweep_unlocking":            "Unlocking wallets...",
+			"sweep_unlock_partial":       "%d wallet(s) unlocked, %d still locked. Enter another password or press 'esc' to skip them.",
+			"sweep_confirm":              "%d transaction(s) ready. Press 'enter' to sign and broadcast.",
+			"sweep_broadcasting":         "Broadcasting...",
+			"sweep_done":                 "Sweep finished: %d sent, %d failed.",
+			"sweep_locked":               "locked",
+			"sweep_unlocked":             "unlocked",
+			"sweep_sending":              "sending...",
+			"sweep_failed":               "failed",
+			"sweep_skip_empty":           "skipped: empty",
+			"sweep_skip_below_fee":       "skipped: below fee",
+			"sweep_skip_destination":     "skipped: destination",
+			"batch_payments":             "Batch Payments",
+			"payment_status_bar":         "View: %s | 'tab' Disperse | 'enter' continue | 'r' resume | 'esc' back",
+			"payment_title":              "Batch payments from %s",
+			"payment_file":               "Payment file (CSV: recipient,amount,token):",
+			"payment_file_placeholder":   "~/payroll.csv",
+			"payment_mode":               "Mode: %s",
+			"payment_mode_toggle":        "Mode: %s (press 'tab' to switch)",
+			"payment_mode_direct":        "one transfer per line",
+			"payment_mode_disperse":      "Disperse contract",
+			"payment_loading":            "Reading the payment file and balances...",
+			"payment_load_error":         "Error loading payments: %v",
+			"payment_line":               "Line",
+			"payment_recipient":          "Recipient",
+			"payment_amount":             "Amount",
+			"payment_token":              "Token",
+			"payment_status":             "Status",
+			"payment_status_pending":     "pending",
+			"payment_status_sent":        "sent",
+			"payment_status_failed":      "failed",
+			"payment_total":              "%s: %s to pay, %s available",
+			"payment_insufficient":       "The balance does not cover the payments. Fix the file or fund the wallet.",
+			"payment_all_sent":           "Every payment in this file was already sent.",
+			"payment_review_prompt":      "%d payment(s) pending via %s. Press 'enter' to build and sign all transactions.",
+			"payment_signing":            "Building and signing transactions...",
+			"payment_signed":             "%d transaction(s) signed, fees up to %s %s. Press 'enter' to broadcast.",
+			"payment_broadcasting":       "Broadcasting...",
+			"payment_stopped":            "Broadcast stopped at transaction %d of %d: %v. Press 'r' to resume.",
+			"payment_done":               "%d transaction(s) broadcast. Results written to %s.",
+			"payment_results_error":      "Error writing the results file: %v",
+			"rpc_status_healthy":         "RPC: %s %dms #%d",
+			"rpc_status_unhealthy":       "RPC: %s down: %s",
+			"portfolio_verified":         "✓ Balances proven at trusted block %d (%s)",
+			"portfolio_unverified":       "⚠ marks balances that could not be proven",
+			"portfolio_verify_error":     "⚠ Balances unverified: %v",
+			"private_key_in_backend":     "held by the %s backend, never exported",
+			"key_created_in_backend":     "The key will be generated inside the %s backend and never leaves it. There is no mnemonic phrase to back up.",
+			"keyring_unlock_failed":      "Could not unlock with the keyring (%v). Enter the password instead.",
+			"keyring_remember":           "[%s] Remember the password in the system keyring (Tab to toggle)",
+			"keyring_saved":              "Password saved in the system keyring.",
+			"keyring_forgotten":          "Password removed from the system keyring.",
+			"keyring_forget_hint":        "The password is stored in the system keyring. Press 'f' to forget it.",
+			"keyring_error":              "Keyring error: %v",
+			"pay_usage":                  "usage: pay <wallet address> <file.csv> [--disperse]",
+			"pay_no_disperse":            "no Disperse contract is configured for the active network",
+			"pay_unknown_wallet":         "wallet %s not found",
+			"pay_nothing":                "Every payment in the file was already sent.",
+			"pay_short":                  "insufficient %s balance for the pending payments",
+			"pay_signed":                 "%d transaction(s) signed, fees up to %s %s.",
+			"pay_sent":                   "[%d/%d] %s",
+			"pay_stopped":                "broadcast stopped at transaction %d of %d: %v; run the command again to resume (results in %s)",
+			"pay_done":                   "%d transaction(s) broadcast. Results written to %s.",
+			"vault":                      "Master Password",
+			"vault_status_bar":           "View: %s | 'ctrl+x' quit",
+			"vault_locked":               "Locked",
+			"master_password":            "Master Password",
+			"master_password_desc":       "Change the master password",
+			"vault_password":             "Master password",
+			"vault_new_password":         "New master password",
+			"vault_confirm_password":     "Confirm the new password",
+			"vault_recovery_key":         "Recovery key",
+			"vault_password_mismatch":    "The passwords do not match.",
+			"vault_working":              "Deriving the key...",
+			"vault_error":                "Error: %s",
+			"vault_wrong_password":       "incorrect master password",
+			"vault_invalid_recovery_key": "invalid recovery key",
+			"vault_password_changed":     "Master password changed.",
+			"vault_unlock_title":         "BLOCO is locked",
+			"vault_unlock_desc":          "Enter the master password to open your wallets.",
+			"vault_unlock_hint":          "enter: unlock | ctrl+r: forgot the password | ctrl+x: quit",
+			"vault_setup_title":          "Set a master password",
+			"vault_setup_desc":           "The master password locks the application and encrypts mnemonics, saved contracts and transaction notes stored in the database. It is separate from each wallet's password.",
+			"vault_form_hint":            "tab: next field | enter: confirm | esc: back",
+			"vault_recover_title":        "Recover access",
+			"vault_recover_desc":         "Enter the recovery key you wrote down and choose a new master password.",
+			"vault_recovery_key_title":   "Recovery key",
+			"vault_recovery_key_desc":    "Write this key down and keep it offline. It is the only way back in if you forget the master password, and it will not be shown again.",
+			"vault_recovery_key_hint":    "enter: I have written it down",
+			"vault_change_title":         "Change the master password",
+			"vault_change_desc":          "Enter the current master password and the new one. Wallet passwords are not affected.",
+			"vault_change_hint":          "tab: next field | enter: confirm | ctrl+r: new recovery key | esc: back",
+			"vault_new_recovery_title":   "New recovery key",
+			"vault_new_recovery_desc":    "Enter the master password to replace the recovery key. The previous key stops working.",
+			"session_status":             "Lock in %s",
+			"session_locked_idle":        "Locked after %s without activity.",
+			"session_locked_title":       "Session locked",
+			"session_locked_desc":        "Unlocked wallets and typed secrets were cleared from memory.",
+			"session_locked_hint":        "enter: continue | ctrl+x: quit",
+
+			"two_factor_code":                  "Authentication code",
+			"two_factor_enroll_title":          "Two-factor authentication",
+			"two_factor_enroll_desc":           "Scan the QR code or enter the secret in an authenticator app, then type the 6-digit code it shows to enable two-factor authentication for %s.",
+			"two_factor_secret":                "Secret:",
+			"two_factor_uri":                   "URI:",
+			"two_factor_recovery_codes":        "Recovery codes — each one replaces a code once. Write them down: they are shown only now.",
+			"two_factor_enroll_hint":           "Enter to confirm • Esc to cancel",
+			"two_factor_enabled":               "Two-factor authentication enabled.",
+			"two_factor_settings_title":        "Two-factor authentication — %s",
+			"two_factor_settings_desc":         "Choose the operations that need a code besides the password. Changes are saved with a current code.",
+			"two_factor_reveal":                "Reveal the private key and mnemonic",
+			"two_factor_export":                "Export the ledger",
+			"two_factor_delete":                "Delete the wallet",
+			"two_factor_threshold":             "Sign more than (%s, or any token or contract call; empty never asks):",
+			"two_factor_threshold_placeholder": "e.g. 0.5",
+			"two_factor_recovery_left":         "%d recovery codes left",
+			"two_factor_settings_hint":         "↑/↓ to move • Space to toggle • Enter to save • Ctrl+D to disable • Esc to go back",
+			"two_factor_saved":                 "Settings saved.",
+			"two_factor_disabled":              "Two-factor authentication disabled for this wallet.",
+			"two_factor_challenge_title":       "Authentication code required",
+			"two_factor_challenge_desc":        "%s needs the authenticator code (or a recovery code) of %s.",
+			"two_factor_challenge_progress":    "Wallet %d of %d",
+			"two_factor_challenge_hint":        "Enter to confirm • Esc to cancel",
+			"two_factor_op_reveal":             "Revealing the keys",
+			"two_factor_op_export":             "Exporting the ledger",
+			"two_factor_op_delete":             "Deleting the wallet",
+			"two_factor_op_sign":               "Signing this amount",
+			"two_factor_error":                 "Error: %s",
+			"two_factor_invalid_code":          "invalid authentication code",
+			"two_factor_needs_vault":           "two-factor authentication needs the application master password",
+			"two_factor_invalid_threshold":     "Invalid amount: %s",
+			"two_factor_hidden":                "hidden — press 'r' and enter the authentication code",
+			"two_factor_details_hint":          "Press 't' for two-factor authentication.",
+			"pay_two_factor":                   "wallet %s requires a two-factor code to sign %s %s; run the payment from the interactive mode",
+			"unlock_wrong_password":            "Incorrect password.",
+			"unlock_blocked":                   "Too many failed attempts: wait before trying again.",
+			"unlock_attempts_left":             "%d attempt(s) left before the wallet is locked out.",
+			"unlock_wait":                      "Next attempt in %s.",
+			"unlock_locked_out":                "Locked out after %d failed attempts until %s (%s left).",
+			"unlock_release_hint":              "Press Ctrl+U to lift the lockout with the master password.",
+			"unlock_release_title":             "Enter the master password to lift the lockout",
+			"unlock_release_placeholder":       "Master password",
+			"unlock_release_help":              "Press Enter to confirm or Esc to go back.",
+			"unlock_released":                  "Lockout lifted. You can enter the wallet password again.",
+			"unlock_release_error":             "Error: %s",
+			"pay_two_factor_tokens":            "wallet %s requires a two-factor code to sign token payments; run the payment from the interactive mode",
+			"two_factor_blocked":               "too many wrong codes, wait before trying again",
+			"headless_master_password":         "the master password is set: configure master_password.providers to run without the interface",
+			"vault_blocked":                    "too many wrong master passwords, wait before trying again",
+			"sweep_fee_note":                   "Each fee is capped at the next block's base fee plus a 25% margin and the tip; whatever the fee does not use stays in the wallet.",
+			"two_factor_no_vault":              "set a master password first: remove master_password.disabled from the config and restart",
+		}, nil
 	case "pt":
-		defaultLabels = map[string]string{
+		return map[string]string{
 			"welcome_message":           "Bem-vindo ao Administrador de Carteiras BLOCO!\n\nSelecione uma opção do menu.",
 			"mnemonic_phrase":           "Frase Mnemotécnica (Mantenha-a Segura!):",
 			"enter_password":            "Digite uma senha para encriptar a carteira:",
@@ -101,7 +478,7 @@ func createDefaultLabels(lang, labelsPath string) error {
 			"import_wallet_title":       "Importar carteira pré existente",
 			"wallet_list_instructions":  "Use as teclas de seta para navegar, Enter para ver detalhes, ESC para voltar ao menu.",
 			"status_bar_instructions":   "Visualização: %s | Pressione 'esc' ou 'backspace' para retornar | Pressione 'q' para sair",
-			"wallet_list_status_bar":    "Visualização: %s | Pressione 'd' para excluir | Pressione 'esc' para retornar | Pressione 'q' para sair",
+			"wallet_list_status_bar":    "Visualização: %s | 'h' histórico | 'd' excluir | 'espaço' selecionar | 's' varrer | 'esc' retornar | 'q' sair",
 			"enter_wallet_password":     "Digite a senha da carteira:",
 			"select_wallet_prompt":      "Selecione uma carteira e digite a senha para ver os detalhes.",
 			"wallet_details_title":      "Detalhes da Carteira",
@@ -143,9 +520,347 @@ func createDefaultLabels(lang, labelsPath string) error {
 			"cancel":                    "Cancelar",
 			"list_wallets_title":        "Lista de Carteiras",
 			"list_wallets_instructions": "Use as setas ↑↓ para navegar, Enter para selecionar, 'd' ou 'delete' para excluir uma carteira, ESC para voltar ao menu.",
-		}
+			"wallet_history":            "Histórico de Transações",
+			"history_title":             "Histórico de Transações: %s",
+			"history_kind_normal":       "Transações",
+			"history_kind_internal":     "Internas",
+			"history_kind_token":        "Transferências de Tokens",
+			"history_date":              "Data",
+			"history_hash":              "Hash",
+			"history_direction":         "Dir",
+			"history_counterparty":      "Contraparte",
+			"history_amount":            "Valor",
+			"history_status":            "Status",
+			"history_in":                "ENT",
+			"history_out":               "SAI",
+			"history_self":              "PRÓPRIA",
+			"history_failed":            "falhou",
+			"history_ok":                "ok",
+			"history_loading":           "Carregando histórico...",
+			"history_empty":             "Nenhuma transação encontrada nesta página.",
+			"history_error":             "Erro ao carregar o histórico: %v",
+			"history_page_info":         "Página %d | Obtido em %s",
+			"history_stale":             "(cópia offline)",
+			"history_status_bar":        "Visualização: %s | 'tab' tipo | 'n'/'p' página | 'r' atualizar | 'esc' retornar | 'q' sair",
+			"ledger":                    "Livro-Razão",
+			"ledger_desc":               "Transações assinadas ou transmitidas pelo BLOCO",
+			"ledger_title":              "Livro-Razão de Transações",
+			"ledger_since":              "De",
+			"ledger_until":              "Até",
+			"ledger_invalid_date":       "Data inválida: %s",
+			"ledger_error":              "Erro no livro-razão: %v",
+			"ledger_exported":           "Livro-razão exportado para %s",
+			"ledger_from":               "Origem",
+			"ledger_to":                 "Destino",
+			"ledger_memo":               "Memo",
+			"ledger_all":                "Todos",
+			"ledger_filters":            "Carteira: %s | Status: %s",
+			"ledger_period":             "Período: %s → %s",
+			"ledger_empty":              "Nenhum lançamento corresponde aos filtros atuais.",
+			"ledger_status_bar":         "Visualização: %s | 'w' carteira | 's' status | 'f' datas | 'x' limpar | 'c' CSV | 'J' JSON | 'esc' retornar",
+			"wallet_nfts":               "NFTs",
+			"tx_review":                 "Revisão de Transação",
+			"wallet_details_actions":    "Pressione 'n' para NFTs, 'a' para aprovações de tokens, 'c' para o workbench de contratos ou 'p' para pagamentos em lote.",
+			"nft_title":                 "NFTs da carteira %s",
+			"nft_loading":               "Buscando NFTs...",
+			"nft_error":                 "Erro ao carregar os NFTs: %v",
+			"nft_empty":                 "Nenhum NFT encontrado para esta carteira.",
+			"nft_recipient":             "Endereço ou nome ENS do destinatário:",
+			"nft_collection":            "Coleção",
+			"nft_standard":              "Padrão",
+			"nft_token_id":              "Token ID",
+			"nft_balance":               "Saldo",
+			"nft_metadata":              "URI de Metadados",
+			"nft_status_bar":            "Visualização: %s | 't' transferir | 'r' atualizar | 'esc' retornar | 'q' sair",
+			"invalid_address":           "Endereço Ethereum ou nome ENS inválido.",
+			"tx_error":                  "Erro na transação: %v",
+			"tx_building":               "Preparando a transação...",
+			"tx_signing":                "Assinando a transação...",
+			"tx_signed":                 "Transação assinada e registrada no livro-razão.",
+			"tx_broadcasting":           "Transmitindo a transação...",
+			"tx_broadcast_done":         "Transação transmitida: %s",
+			"tx_review_title":           "Revisar Transação",
+			"tx_from":                   "De:",
+			"tx_to":                     "Para:",
+			"tx_contract_creation":      "(criação de contrato)",
+			"tx_value":                  "Valor:",
+			"tx_nonce":                  "Nonce:",
+			"tx_gas_limit":              "Limite de gas:",
+			"tx_max_fee_per_gas":        "Taxa máx. por gas:",
+			"tx_max_fee":                "Taxa máxima:",
+			"tx_data":                   "Dados:",
+			"tx_hash":                   "Hash:",
+			"tx_raw":                    "Assinada (raw):",
+			"tx_sign_prompt":            "Pressione Enter para assinar, 'r' para simular novamente ou ESC para cancelar.",
+			"tx_broadcast_prompt":       "Pressione 'b' para transmitir ou ESC para voltar.",
+			"portfolio_value_column":    "Valor (%s)",
+			"portfolio_header":          " | Portfólio: %s %s",
+			"portfolio_title":           "Portfólio",
+			"portfolio_loading":         "Carregando cotações...",
+			"portfolio_error":           "Falha ao atualizar cotações: %v",
+			"portfolio_no_price":        "sem cotação",
+			"portfolio_total":           "Total",
+			"portfolio_updated":         "Saldos em %s",
+			"portfolio_priced":          "cotações em %s",
+			"contracts":                 "Workbench de Contratos",
+			"contract_status_bar":       "Tela: %s | 'esc' voltar | 'q' sair",
+			"contract_title":            "Workbench de contratos para %s",
+			"contract_error":            "Erro: %v",
+			"contract_name":             "Nome",
+			"contract_address":          "Endereço do contrato",
+			"contract_abi_path":         "Arquivo JSON de ABI ou artefato",
+			"contract_artifact_path":    "Arquivo JSON de artefato ou ABI",
+			"contract_bytecode_path":    "Arquivo de bytecode (opcional se o artefato contiver)",
+			"contract_function":         "Função",
+			"contract_mutability":       "Mutabilidade",
+			"contract_value":            "Valor em %s (opcional)",
+			"contract_no_bytecode":      "Bytecode não encontrado; informe um arquivo de bytecode.",
+			"contract_invalid_input":    "Entrada inválida: %v",
+			"contract_calling":          "Chamando o contrato...",
+			"contract_no_outputs":       "Chamada concluída sem valores de retorno.",
+			"contract_deployed":         "Endereço do contrato: %s (salvo)",
+			"contract_empty":            "Nenhum contrato salvo para esta rede.",
+			"contract_list_help":        "'enter' abrir | 'l' carregar ABI | 'd' implantar | 'x' remover",
+			"contract_load_title":       "Carregar ABI do contrato",
+			"contract_deploy_title":     "Implantar contrato",
+			"contract_form_help":        "'tab' próximo campo | 'enter' enviar | 'esc' voltar",
+			"contract_no_functions":     "A ABI não possui funções.",
+			"contract_functions_help":   "'enter' selecionar função | 'esc' voltar",
+			"contract_constructor":      "construtor de %s",
+			"contract_no_inputs":        "Sem argumentos.",
+			"contract_result":           "Resultado:",
+
+			"tx_decoding":                "Decodificando os dados da chamada...",
+			"tx_decode_failed":           "ATENÇÃO: não foi possível decodificar os dados da chamada (%v). Verifique os dados brutos antes de assinar.",
+			"tx_function":                "Função:",
+			"tx_warn_unlimited_approval": "APROVAÇÃO ILIMITADA",
+			"tx_warn_approval_for_all":   "concede controle de TODOS os tokens da coleção",
+			"tx_warn_delegatecall":       "DELEGATECALL no Safe: o código de destino executa com todas as permissões do Safe",
+			"tx_warn_selector_collision": "Várias assinaturas compartilham este seletor; a correspondência pode estar errada",
+			"tx_simulating":              "Simulando a transação...",
+			"tx_simulation_error":        "Simulação indisponível: %v",
+			"tx_simulation_reverted":     "SIMULAÇÃO REVERTIDA: %s",
+			"tx_simulation_success":      "Simulação bem-sucedida",
+			"tx_simulation_gas":          "Gás usado: %d (taxa ≈ %s %s)",
+			"tx_simulation_changes":      "Variações de saldo do remetente (sem a taxa):",
+			"tx_simulation_no_changes":   "Nenhuma variação de saldo detectada para o remetente (sem a taxa).",
+			"tx_simulation_no_logs":      "O nó não suporta eth_simulateV1; não foi possível determinar as variações de tokens.",
+			"wallet_approvals":           "Aprovações de Tokens",
+			"approval_status_bar":        "Tela: %s | 'espaço' selecionar | 'A' selecionar arriscadas | 'v' revogar | 'r' reescanear | 'esc' voltar",
+			"approval_title":             "Aprovações de tokens ativas de %s",
+			"approval_loading":           "Buscando eventos Approval...",
+			"approval_error":             "Erro ao buscar aprovações: %v",
+			"approval_empty":             "Nenhuma aprovação ativa encontrada.",
+			"approval_token":             "Token",
+			"approval_spender":           "Autorizado",
+			"approval_kind":              "Tipo",
+			"approval_allowance":         "Limite",
+			"approval_risk":              "Risco",
+			"approval_status":            "Revogação",
+			"approval_all_tokens":        "todos os tokens",
+			"approval_unlimited":         "ILIMITADO",
+			"approval_limited":           "limitado",
+			"approval_high":              "ALTO",
+			"approval_failed":            "falhou",
+			"approval_revokes_sent":      "%d transação(ões) de revogação transmitida(s).",
+			"approval_revoking":          "Assinando e transmitindo revogações...",
+			"approval_none_selected":     "Selecione aprovações com 'espaço' primeiro.",
+			"approval_confirm_title":     "%d transação(ões) de revogação serão assinadas por esta carteira:",
+			"approval_confirm_fee":       "Taxa total máxima: %s %s",
+			"approval_confirm_prompt":    "Pressione Enter para assinar e transmitir todas, ou ESC para cancelar.",
+			"ens_resolving":              "Resolvendo %s...",
+			"ens_resolve_error":          "Não foi possível resolver %s: %v",
+			"account_discovery":          "Descoberta de Contas",
+			"discovery_status_bar":       "Tela: %s | 'espaço' selecionar | 'A' selecionar todas | 'enter' registrar | 'esc' continuar",
+			"discovery_title":            "Contas usadas derivadas desta frase mnemônica",
+			"discovery_loading":          "Verificando endereços derivados (para após %d sem uso seguidos)...",
+			"discovery_error":            "Erro ao descobrir contas: %v",
+			"discovery_path":             "Caminho",
+			"discovery_nonce":            "Nonce",
+			"discovery_balance":          "Saldo",
+			"discovery_status":           "Status",
+			"discovery_registered":       "registrada",
+			"discovery_none_selected":    "Nenhuma conta selecionada.",
+			"discovery_registering":      "Registrando as contas selecionadas...",
+			"discovery_registered_count": "%d conta(s) registrada(s). Pressione 'esc' para continuar.",
+			"discovery_register_error":   "Erro ao registrar as contas: %v",
+			"dev_mine_block":             "Minerar Bloco",
+			"dev_mine_block_desc":        "Incluir as transações pendentes na cadeia local de desenvolvimento",
+			"dev_mining":                 "Minerando um bloco...",
+			"dev_block_mined":            "Bloco #%d minerado.",
+			"dev_mine_error":             "Erro ao minerar o bloco: %v",
+			"dev_running":                "Modo dev: cadeia local (ID 1337) servindo JSON-RPC em %s",
+			"wallet_sweep":               "Varredura",
+			"sweep_status_bar":           "Tela: %s | 'tab' ativo | 'enter' continuar | 'esc' voltar/pular bloqueadas",
+			"sweep_title":                "Varrer %d wallet(s) para um único destino",
+			"sweep_destination":          "Endereço ou nome ENS de destino:",
+			"sweep_asset":                "Ativo: %s (pressione 'tab' para trocar)",
+			"sweep_summary":              "%s → %s | total %s",
+			"sweep_wallet":               "Wallet",
+			"sweep_balance":              "Saldo",
+			"sweep_amount":               "Valor",
+			"sweep_fee":                  "Taxa",
+			"sweep_status":               "Status",
+			"sweep_planning":             "Lendo saldos e estimando taxas...",
+			"sweep_error":                "Erro ao planejar a varredura: %v",
+			"sweep_nothing":              "Nenhuma wallet tem saldo para varrer.",
+			"sweep_plan_prompt":          "%d transação(ões) planejada(s). Pressione 'enter' para desbloquear as wallets.",
+			"sweep_password":             "Senha da wallet",
+			"sweep_password_prompt":      "Digite a senha das wallets (%d bloqueadas). Cada senha desbloqueia todas as wallets que a compartilham.",
+			"sweep_unlocking":            "Desbloqueando wallets...",
+			"sweep_unlock_partial":       "%d wallet(s) desbloqueada(s), %d ainda bloqueada(s). Digite outra senha ou pressione 'esc' para pulá-las.",
+			"sweep_confirm":              "%d transação(ões) pronta(s). Pressione 'enter' para assinar e transmitir.",
+			"sweep_broadcasting":         "Transmitindo...",
+			"sweep_done":                 "Varredura concluída: %d enviada(s), %d com falha.",
+			"sweep_locked":               "bloqueada",
+			"sweep_unlocked":             "desbloqueada",
+			"sweep_sending":              "enviando...",
+			"sweep_failed":               "falhou",
+			"sweep_skip_empty":           "ignorada: vazia",
+			"sweep_skip_below_fee":       "ignorada: abaixo da taxa",
+			"sweep_skip_destination":     "ignorada: destino",
+			"batch_payments":             "Pagamentos em Lote",
+			"payment_status_bar":         "Tela: %s | 'tab' Disperse | 'enter' continuar | 'r' retomar | 'esc' voltar",
+			"payment_title":              "Pagamentos em lote de %s",
+			"payment_file":               "Arquivo de pagamentos (CSV: recipient,amount,token):",
+			"payment_file_placeholder":   "~/folha.csv",
+			"payment_mode":               "Modo: %s",
+			"payment_mode_toggle":        "Modo: %s (pressione 'tab' para alternar)",
+			"payment_mode_direct":        "uma transferência por linha",
+			"payment_mode_disperse":      "contrato Disperse",
+			"payment_loading":            "Lendo o arquivo de pagamentos e os saldos...",
+			"payment_load_error":         "Erro ao carregar os pagamentos: %v",
+			"payment_line":               "Linha",
+			"payment_recipient":          "Destinatário",
+			"payment_amount":             "Valor",
+			"payment_token":              "Token",
+			"payment_status":             "Status",
+			"payment_status_pending":     "pendente",
+			"payment_status_sent":        "enviado",
+			"payment_status_failed":      "falhou",
+			"payment_total":              "%s: %s a pagar, %s disponível",
+			"payment_insufficient":       "O saldo não cobre os pagamentos. Corrija o arquivo ou financie a wallet.",
+			"payment_all_sent":           "Todos os pagamentos deste arquivo já foram enviados.",
+			"payment_review_prompt":      "%d pagamento(s) pendente(s) via %s. Pressione 'enter' para montar e assinar todas as transações.",
+			"payment_signing":            "Montando e assinando as transações...",
+			"payment_signed":             "%d transação(ões) assinada(s), taxas de até %s %s. Pressione 'enter' para transmitir.",
+			"payment_broadcasting":       "Transmitindo...",
+			"payment_stopped":            "Transmissão interrompida na transação %d de %d: %v. Pressione 'r' para retomar.",
+			"payment_done":               "%d transação(ões) transmitida(s). Resultados gravados em %s.",
+			"payment_results_error":      "Erro ao gravar o arquivo de resultados: %v",
+			"rpc_status_healthy":         "RPC: %s %dms #%d",
+			"rpc_status_unhealthy":       "RPC: %s fora: %s",
+			"portfolio_verified":         "✓ Saldos provados no bloco confiável %d (%s)",
+			"portfolio_unverified":       "⚠ indica saldos que não puderam ser provados",
+			"portfolio_verify_error":     "⚠ Saldos não verificados: %v",
+			"private_key_in_backend":     "mantida pelo backend %s, nunca exportada",
+			"key_created_in_backend":     "A chave será gerada dentro do backend %s e nunca sairá dele. Não há frase mnemônica para guardar.",
+			"keyring_unlock_failed":      "Não foi possível desbloquear pelo chaveiro (%v). Digite a senha.",
+			"keyring_remember":           "[%s] Lembrar a senha no chaveiro do sistema (Tab alterna)",
+			"keyring_saved":              "Senha guardada no chaveiro do sistema.",
+			"keyring_forgotten":          "Senha removida do chaveiro do sistema.",
+			"keyring_forget_hint":        "A senha está guardada no chaveiro do sistema. Pressione 'f' para esquecê-la.",
+			"keyring_error":              "Erro no chaveiro: %v",
+			"pay_usage":                  "uso: pay <endereço da wallet> <arquivo.csv> [--disperse]",
+			"pay_no_disperse":            "nenhum contrato Disperse configurado para a rede ativa",
+			"pay_unknown_wallet":         "wallet %s não encontrada",
+			"pay_nothing":                "Todos os pagamentos do arquivo já foram enviados.",
+			"pay_short":                  "saldo de %s insuficiente para os pagamentos pendentes",
+			"pay_signed":                 "%d transação(ões) assinada(s), taxas de até %s %s.",
+			"pay_sent":                   "[%d/%d] %s",
+			"pay_stopped":                "transmissão interrompida na transação %d de %d: %v; execute o comando novamente para retomar (resultados em %s)",
+			"pay_done":                   "%d transação(ões) transmitida(s). Resultados gravados em %s.",
+			"vault":                      "Senha Mestra",
+			"vault_status_bar":           "Tela: %s | 'ctrl+x' sair",
+			"vault_locked":               "Bloqueado",
+			"master_password":            "Senha Mestra",
+			"master_password_desc":       "Trocar a senha mestra",
+			"vault_password":             "Senha mestra",
+			"vault_new_password":         "Nova senha mestra",
+			"vault_confirm_password":     "Confirme a nova senha",
+			"vault_recovery_key":         "Chave de recuperação",
+			"vault_password_mismatch":    "As senhas não coincidem.",
+			"vault_working":              "Derivando a chave...",
+			"vault_error":                "Erro: %s",
+			"vault_wrong_password":       "senha mestra incorreta",
+			"vault_invalid_recovery_key": "chave de recuperação inválida",
+			"vault_password_changed":     "Senha mestra alterada.",
+			"vault_unlock_title":         "O BLOCO está bloqueado",
+			"vault_unlock_desc":          "Digite a senha mestra para abrir suas wallets.",
+			"vault_unlock_hint":          "enter: desbloquear | ctrl+r: esqueci a senha | ctrl+x: sair",
+			"vault_setup_title":          "Defina uma senha mestra",
+			"vault_setup_desc":           "A senha mestra bloqueia a aplicação e cifra as frases mnemônicas, os contratos salvos e as notas de transações guardados no banco. Ela é independente da senha de cada wallet.",
+			"vault_form_hint":            "tab: próximo campo | enter: confirmar | esc: voltar",
+			"vault_recover_title":        "Recuperar o acesso",
+			"vault_recover_desc":         "Digite a chave de recuperação anotada e escolha uma nova senha mestra.",
+			"vault_recovery_key_title":   "Chave de recuperação",
+			"vault_recovery_key_desc":    "Anote esta chave e guarde-a fora do computador. Ela é o único caminho de volta se você esquecer a senha mestra e não será exibida novamente.",
+			"vault_recovery_key_hint":    "enter: já anotei",
+			"vault_change_title":         "Trocar a senha mestra",
+			"vault_change_desc":          "Digite a senha mestra atual e a nova. As senhas das wallets não mudam.",
+			"vault_change_hint":          "tab: próximo campo | enter: confirmar | ctrl+r: nova chave de recuperação | esc: voltar",
+			"vault_new_recovery_title":   "Nova chave de recuperação",
+			"vault_new_recovery_desc":    "Digite a senha mestra para substituir a chave de recuperação. A chave anterior deixa de funcionar.",
+			"session_status":             "Bloqueio em %s",
+			"session_locked_idle":        "Bloqueado após %s sem atividade.",
+			"session_locked_title":       "Sessão bloqueada",
+			"session_locked_desc":        "As wallets desbloqueadas e os segredos digitados foram apagados da memória.",
+			"session_locked_hint":        "enter: continuar | ctrl+x: sair",
+
+			"two_factor_code":                  "Código de autenticação",
+			"two_factor_enroll_title":          "Autenticação em dois fatores",
+			"two_factor_enroll_desc":           "Escaneie o QR code ou informe o segredo em um app autenticador e digite o código de 6 dígitos exibido para ativar a autenticação em dois fatores de %s.",
+			"two_factor_secret":                "Segredo:",
+			"two_factor_uri":                   "URI:",
+			"two_factor_recovery_codes":        "Códigos de recuperação — cada um substitui um código uma vez. Anote-os: eles só são exibidos agora.",
+			"two_factor_enroll_hint":           "Enter para confirmar • Esc para cancelar",
+			"two_factor_enabled":               "Autenticação em dois fatores ativada.",
+			"two_factor_settings_title":        "Autenticação em dois fatores — %s",
+			"two_factor_settings_desc":         "Escolha as operações que exigem um código além da senha. As alterações são salvas com um código atual.",
+			"two_factor_reveal":                "Revelar a chave privada e o mnemônico",
+			"two_factor_export":                "Exportar o livro-razão",
+			"two_factor_delete":                "Excluir a wallet",
+			"two_factor_threshold":             "Assinar mais de (%s, ou qualquer token ou chamada de contrato; vazio nunca pede):",
+			"two_factor_threshold_placeholder": "ex.: 0.5",
+			"two_factor_recovery_left":         "%d códigos de recuperação restantes",
+			"two_factor_settings_hint":         "↑/↓ para mover • Espaço para marcar • Enter para salvar • Ctrl+D para desativar • Esc para voltar",
+			"two_factor_saved":                 "Configurações salvas.",
+			"two_factor_disabled":              "Autenticação em dois fatores desativada para esta wallet.",
+			"two_factor_challenge_title":       "Código de autenticação necessário",
+			"two_factor_challenge_desc":        "%s exige o código do autenticador (ou um código de recuperação) de %s.",
+			"two_factor_challenge_progress":    "Wallet %d de %d",
+			"two_factor_challenge_hint":        "Enter para confirmar • Esc para cancelar",
+			"two_factor_op_reveal":             "Revelar as chaves",
+			"two_factor_op_export":             "Exportar o livro-razão",
+			"two_factor_op_delete":             "Excluir a wallet",
+			"two_factor_op_sign":               "Assinar este valor",
+			"two_factor_error":                 "Erro: %s",
+			"two_factor_invalid_code":          "código de autenticação inválido",
+			"two_factor_needs_vault":           "a autenticação em dois fatores exige a senha mestra da aplicação",
+			"two_factor_invalid_threshold":     "Valor inválido: %s",
+			"two_factor_hidden":                "oculto — pressione 'r' e informe o código de autenticação",
+			"two_factor_details_hint":          "Pressione 't' para a autenticação em dois fatores.",
+			"pay_two_factor":                   "a wallet %s exige um código de dois fatores para assinar %s %s; execute o pagamento pelo modo interativo",
+			"unlock_wrong_password":            "Senha incorreta.",
+			"unlock_blocked":                   "Tentativas malsucedidas demais: aguarde antes de tentar novamente.",
+			"unlock_attempts_left":             "%d tentativa(s) restante(s) antes do bloqueio da wallet.",
+			"unlock_wait":                      "Próxima tentativa em %s.",
+			"unlock_locked_out":                "Bloqueada após %d tentativas malsucedidas até %s (faltam %s).",
+			"unlock_release_hint":              "Pressione Ctrl+U para encerrar o bloqueio com a senha mestra.",
+			"unlock_release_title":             "Digite a senha mestra para encerrar o bloqueio",
+			"unlock_release_placeholder":       "Senha mestra",
+			"unlock_release_help":              "Pressione Enter para confirmar ou Esc para voltar.",
+			"unlock_released":                  "Bloqueio encerrado. Você já pode digitar a senha da wallet.",
+			"unlock_release_error":             "Erro: %s",
+			"pay_two_factor_tokens":            "a wallet %s exige um código de dois fatores para assinar pagamentos em tokens; execute o pagamento pelo modo interativo",
+			"two_factor_blocked":               "códigos incorretos demais, aguarde antes de tentar novamente",
+			"headless_master_password":         "a senha mestra está definida: configure master_password.providers para executar sem a interface",
+			"vault_blocked":                    "senhas mestras incorretas demais, aguarde antes de tentar novamente",
+			"sweep_fee_note":                   "Cada taxa é limitada à taxa base do próximo bloco mais uma margem de 25% e a gorjeta; o que a taxa não usar fica na wallet.",
+			"two_factor_no_vault":              "defina uma senha mestra antes: remova master_password.disabled da configuração e reinicie",
+		}, nil
 	case "es":
-		defaultLabels = map[string]string{
+		return map[string]string{
 			"welcome_message":          "¡Bienvenido al Administrador de Carteras BLOCO!\n\nSeleccione una opción del menú.",
 			"mnemonic_phrase":          "Frase Mnemotécnica (¡Guárdela de Forma Segura!):",
 			"enter_password":           "Ingrese una contraseña para encriptar la cartera:",
@@ -153,7 +868,7 @@ func createDefaultLabels(lang, labelsPath string) error {
 			"import_wallet_title":      "Importar Cartera mediante Frase Mnemotécnica",
 			"wallet_list_instructions": "Use las teclas de flecha para navegar, Enter para ver detalles, 'd' o 'delete' para eliminar una cartera, ESC para volver al menú.",
 			"status_bar_instructions":  "Vista: %s | Presione 'esc' o 'backspace' para regresar | Presione 'q' para salir",
-			"wallet_list_status_bar":   "Vista: %s | Presione 'd' para eliminar | Presione 'esc' para regresar | Presione 'q' para salir",
+			"wallet_list_status_bar":   "Vista: %s | 'h' historial | 'd' eliminar | 'espacio' seleccionar | 's' barrer | 'esc' regresar | 'q' salir",
 			"enter_wallet_password":    "Ingrese la contraseña de la cartera:",
 			"select_wallet_prompt":     "Seleccione una cartera e ingrese la contraseña para ver los detalles.",
 			"wallet_details_title":     "Detalles de la Cartera",
@@ -193,29 +908,346 @@ func createDefaultLabels(lang, labelsPath string) error {
 			"confirm_delete_wallet":    "¿Está seguro de que desea eliminar esta cartera?",
 			"confirm":                  "Confirmar",
 			"cancel":                   "Cancelar",
-		}
-	default:
-		return fmt.Errorf("unsupported language: %s", lang)
-	}
+			"wallet_history":           "Historial de Transacciones",
+			"history_title":            "Historial de Transacciones: %s",
+			"history_kind_normal":      "Transacciones",
+			"history_kind_internal":    "Internas",
+			"history_kind_token":       "Transferencias de Tokens",
+			"history_date":             "Fecha",
+			"history_hash":             "Hash",
+			"history_direction":        "Dir",
+			"history_counterparty":     "Contraparte",
+			"history_amount":           "Monto",
+			"history_status":           "Estado",
+			"history_in":               "ENT",
+			"history_out":              "SAL",
+			"history_self":             "PROPIA",
+			"history_failed":           "fallida",
+			"history_ok":               "ok",
+			"history_loading":          "Cargando historial...",
+			"history_empty":            "No se encontraron transacciones en esta página.",
+			"history_error":            "Error al cargar el historial: %v",
+			"history_page_info":        "Página %d | Obtenido el %s",
+			"history_stale":            "(copia sin conexión)",
+			"history_status_bar":       "Vista: %s | 'tab' tipo | 'n'/'p' página | 'r' actualizar | 'esc' regresar | 'q' salir",
+			"ledger":                   "Libro Mayor",
+			"ledger_desc":              "Transacciones firmadas o transmitidas por BLOCO",
+			"ledger_title":             "Libro Mayor de Transacciones",
+			"ledger_since":             "Desde",
+			"ledger_until":             "Hasta",
+			"ledger_invalid_date":      "Fecha inválida: %s",
+			"ledger_error":             "Error en el libro mayor: %v",
+			"ledger_exported":          "Libro mayor exportado a %s",
+			"ledger_from":              "Origen",
+			"ledger_to":                "Destino",
+			"ledger_memo":              "Memo",
+			"ledger_all":               "Todos",
+			"ledger_filters":           "Cartera: %s | Estado: %s",
+			"ledger_period":            "Período: %s → %s",
+			"ledger_empty":             "Ningún registro coincide con los filtros actuales.",
+			"ledger_status_bar":        "Vista: %s | 'w' cartera | 's' estado | 'f' fechas | 'x' limpiar | 'c' CSV | 'J' JSON | 'esc' regresar",
+			"wallet_nfts":              "NFTs",
+			"tx_review":                "Revisión de Transacción",
+			"wallet_details_actions":   "Presione 'n' para NFTs, 'a' para aprobaciones de tokens, 'c' para el banco de contratos o 'p' para pagos en lote.",
+			"nft_title":                "NFTs de la cartera %s",
+			"nft_loading":              "Buscando NFTs...",
+			"nft_error":                "Error al cargar los NFTs: %v",
+			"nft_empty":                "No se encontraron NFTs para esta cartera.",
+			"nft_recipient":            "Dirección o nombre ENS del destinatario:",
+			"nft_collection":           "Colección",
+			"nft_standard":             "Estándar",
+			"nft_token_id":             "Token ID",
+			"nft_balance":              "Saldo",
+			"nft_metadata":             "URI de Metadatos",
+			"nft_status_bar":           "Vista: %s | 't' transferir | 'r' actualizar | 'esc' regresar | 'q' salir",
+			"invalid_address":          "Dirección Ethereum o nombre ENS inválido.",
+			"tx_error":                 "Error en la transacción: %v",
+			"tx_building":              "Preparando la transacción...",
+			"tx_signing":               "Firmando la transacción...",
+			"tx_signed":                "Transacción firmada y registrada en el libro mayor.",
+			"tx_broadcasting":          "Transmitiendo la transacción...",
+			"tx_broadcast_done":        "Transacción transmitida: %s",
+			"tx_review_title":          "Revisar Transacción",
+			"tx_from":                  "De:",
+			"tx_to":                    "Para:",
+			"tx_contract_creation":     "(creación de contrato)",
+			"tx_value":                 "Valor:",
+			"tx_nonce":                 "Nonce:",
+			"tx_gas_limit":             "Límite de gas:",
+			"tx_max_fee_per_gas":       "Tarifa máx. por gas:",
+			"tx_max_fee":               "Tarifa máxima:",
+			"tx_data":                  "Datos:",
+			"tx_hash":                  "Hash:",
+			"tx_raw":                   "Firmada (raw):",
+			"tx_sign_prompt":           "Presione Enter para firmar, 'r' para simular de nuevo o ESC para cancelar.",
+			"tx_broadcast_prompt":      "Presione 'b' para transmitir o ESC para volver.",
+			"portfolio_value_column":   "Valor (%s)",
+			"portfolio_header":         " | Portafolio: %s %s",
+			"portfolio_title":          "Portafolio",
+			"portfolio_loading":        "Cargando cotizaciones...",
+			"portfolio_error":          "Error al actualizar cotizaciones: %v",
+			"portfolio_no_price":       "sin cotización",
+			"portfolio_total":          "Total",
+			"portfolio_updated":        "Saldos a las %s",
+			"portfolio_priced":         "cotizaciones a las %s",
+			"contracts":                "Banco de Contratos",
+			"contract_status_bar":      "Vista: %s | 'esc' volver | 'q' salir",
+			"contract_title":           "Banco de contratos para %s",
+			"contract_error":           "Error: %v",
+			"contract_name":            "Nombre",
+			"contract_address":         "Dirección del contrato",
+			"contract_abi_path":        "Archivo JSON de ABI o artefacto",
+			"contract_artifact_path":   "Archivo JSON de artefacto o ABI",
+			"contract_bytecode_path":   "Archivo de bytecode (opcional si el artefacto lo incluye)",
+			"contract_function":        "Función",
+			"contract_mutability":      "Mutabilidad",
+			"contract_value":           "Valor en %s (opcional)",
+			"contract_no_bytecode":     "No se encontró bytecode; indique un archivo de bytecode.",
+			"contract_invalid_input":   "Entrada inválida: %v",
+			"contract_calling":         "Llamando al contrato...",
+			"contract_no_outputs":      "Llamada completada sin valores de retorno.",
+			"contract_deployed":        "Dirección del contrato: %s (guardado)",
+			"contract_empty":           "No hay contratos guardados para esta red.",
+			"contract_list_help":       "'enter' abrir | 'l' cargar ABI | 'd' desplegar | 'x' eliminar",
+			"contract_load_title":      "Cargar ABI del contrato",
+			"contract_deploy_title":    "Desplegar contrato",
+			"contract_form_help":       "'tab' siguiente campo | 'enter' enviar | 'esc' volver",
+			"contract_no_functions":    "La ABI no tiene funciones.",
+			"contract_functions_help":  "'enter' seleccionar función | 'esc' volver",
+			"contract_constructor":     "constructor de %s",
+			"contract_no_inputs":       "Sin argumentos.",
+			"contract_result":          "Resultado:",
 
-	data, err := yaml.Marshal(defaultLabels)
-	if err != nil {
-		return err
-	}
+			"tx_decoding":                "Decodificando los datos de la llamada...",
+			"tx_decode_failed":           "ATENCIÓN: no se pudieron decodificar los datos de la llamada (%v). Verifique los datos sin procesar antes de firmar.",
+			"tx_function":                "Función:",
+			"tx_warn_unlimited_approval": "APROBACIÓN ILIMITADA",
+			"tx_warn_approval_for_all":   "otorga control de TODOS los tokens de la colección",
+			"tx_warn_delegatecall":       "DELEGATECALL en Safe: el código de destino se ejecuta con todos los permisos del Safe",
+			"tx_warn_selector_collision": "Varias firmas comparten este selector; la coincidencia puede ser incorrecta",
+			"tx_simulating":              "Simulando la transacción...",
+			"tx_simulation_error":        "Simulación no disponible: %v",
+			"tx_simulation_reverted":     "SIMULACIÓN REVERTIDA: %s",
+			"tx_simulation_success":      "Simulación exitosa",
+			"tx_simulation_gas":          "Gas usado: %d (comisión ≈ %s %s)",
+			"tx_simulation_changes":      "Cambios de saldo del remitente (sin la comisión):",
+			"tx_simulation_no_changes":   "No se detectaron cambios de saldo para el remitente (sin la comisión).",
+			"tx_simulation_no_logs":      "El nodo no soporta eth_simulateV1; no se pudieron determinar los cambios de tokens.",
+			"wallet_approvals":           "Aprobaciones de Tokens",
+			"approval_status_bar":        "Vista: %s | 'espacio' seleccionar | 'A' seleccionar riesgosas | 'v' revocar | 'r' reescanear | 'esc' volver",
+			"approval_title":             "Aprobaciones de tokens activas de %s",
+			"approval_loading":           "Buscando eventos Approval...",
+			"approval_error":             "Error al buscar aprobaciones: %v",
+			"approval_empty":             "No se encontraron aprobaciones activas.",
+			"approval_token":             "Token",
+			"approval_spender":           "Autorizado",
+			"approval_kind":              "Tipo",
+			"approval_allowance":         "Límite",
+			"approval_risk":              "Riesgo",
+			"approval_status":            "Revocación",
+			"approval_all_tokens":        "todos los tokens",
+			"approval_unlimited":         "ILIMITADO",
+			"approval_limited":           "limitado",
+			"approval_high":              "ALTO",
+			"approval_failed":            "falló",
+			"approval_revokes_sent":      "%d transacción(es) de revocación transmitida(s).",
+			"approval_revoking":          "Firmando y transmitiendo revocaciones...",
+			"approval_none_selected":     "Seleccione aprobaciones con 'espacio' primero.",
+			"approval_confirm_title":     "%d transacción(es) de revocación serán firmadas por esta cartera:",
+			"approval_confirm_fee":       "Comisión total máxima: %s %s",
+			"approval_confirm_prompt":    "Presione Enter para firmar y transmitir todas, o ESC para cancelar.",
+			"ens_resolving":              "Resolviendo %s...",
+			"ens_resolve_error":          "No se pudo resolver %s: %v",
+			"account_discovery":          "Descubrimiento de Cuentas",
+			"discovery_status_bar":       "Vista: %s | 'espacio' seleccionar | 'A' seleccionar todas | 'enter' registrar | 'esc' continuar",
+			"discovery_title":            "Cuentas usadas derivadas de esta frase mnemónica",
+			"discovery_loading":          "Revisando direcciones derivadas (se detiene tras %d sin uso seguidas)...",
+			"discovery_error":            "Error al descubrir cuentas: %v",
+			"discovery_path":             "Ruta",
+			"discovery_nonce":            "Nonce",
+			"discovery_balance":          "Saldo",
+			"discovery_status":           "Estado",
+			"discovery_registered":       "registrada",
+			"discovery_none_selected":    "Ninguna cuenta seleccionada.",
+			"discovery_registering":      "Registrando las cuentas seleccionadas...",
+			"discovery_registered_count": "%d cuenta(s) registrada(s). Presione 'esc' para continuar.",
+			"discovery_register_error":   "Error al registrar las cuentas: %v",
+			"dev_mine_block":             "Minar Bloque",
+			"dev_mine_block_desc":        "Incluir las transacciones pendientes en la cadena local de desarrollo",
+			"dev_mining":                 "Minando un bloque...",
+			"dev_block_mined":            "Bloque #%d minado.",
+			"dev_mine_error":             "Error al minar el bloque: %v",
+			"dev_running":                "Modo dev: cadena local (ID 1337) sirviendo JSON-RPC en %s",
+			"wallet_sweep":               "Barrido",
+			"sweep_status_bar":           "Vista: %s | 'tab' activo | 'enter' continuar | 'esc' volver/omitir bloqueadas",
+			"sweep_title":                "Barrer %d wallet(s) hacia un único destino",
+			"sweep_destination":          "Dirección o nombre ENS de destino:",
+			"sweep_asset":                "Activo: %s (presione 'tab' para cambiar)",
+			"sweep_summary":              "%s → %s | total %s",
+			"sweep_wallet":               "Wallet",
+			"sweep_balance":              "Saldo",
+			"sweep_amount":               "Monto",
+			"sweep_fee":                  "Comisión",
+			"sweep_status":               "Estado",
+			"sweep_planning":             "Leyendo saldos y estimando comisiones...",
+			"sweep_error":                "Error al planificar el barrido: %v",
+			"sweep_nothing":              "Ninguna wallet tiene saldo para barrer.",
+			"sweep_plan_prompt":          "%d transacción(es) planificada(s). Presione 'enter' para desbloquear las wallets.",
+			"sweep_password":             "Contraseña de la wallet",
+			"sweep_password_prompt":      "Ingrese la contraseña de las wallets (%d bloqueadas). Cada contraseña desbloquea todas las wallets que la comparten.",
+			"sweep_unlocking":            "Desbloqueando wallets...",
+			"sweep_unlock_partial":       "%d wallet(s) desbloqueada(s), %d aún bloqueada(s). Ingrese otra contraseña o presione 'esc' para omitirlas.",
+			"sweep_confirm":              "%d transacción(es) lista(s). Presione 'enter' para firmar y transmitir.",
+			"sweep_broadcasting":         "Transmitiendo...",
+			"sweep_done":                 "Barrido terminado: %d enviada(s), %d fallida(s).",
+			"sweep_locked":               "bloqueada",
+			"sweep_unlocked":             "desbloqueada",
+			"sweep_sending":              "enviando...",
+			"sweep_failed":               "falló",
+			"sweep_skip_empty":           "omitida: vacía",
+			"sweep_skip_below_fee":       "omitida: bajo la comisión",
+			"sweep_skip_destination":     "omitida: destino",
+			"batch_payments":             "Pagos en Lote",
+			"payment_status_bar":         "Vista: %s | 'tab' Disperse | 'enter' continuar | 'r' reanudar | 'esc' volver",
+			"payment_title":              "Pagos en lote desde %s",
+			"payment_file":               "Archivo de pagos (CSV: recipient,amount,token):",
+			"payment_file_placeholder":   "~/nomina.csv",
+			"payment_mode":               "Modo: %s",
+			"payment_mode_toggle":        "Modo: %s (presione 'tab' para cambiar)",
+			"payment_mode_direct":        "una transferencia por línea",
+			"payment_mode_disperse":      "contrato Disperse",
+			"payment_loading":            "Leyendo el archivo de pagos y los saldos...",
+			"payment_load_error":         "Error al cargar los pagos: %v",
+			"payment_line":               "Línea",
+			"payment_recipient":          "Destinatario",
+			"payment_amount":             "Monto",
+			"payment_token":              "Token",
+			"payment_status":             "Estado",
+			"payment_status_pending":     "pendiente",
+			"payment_status_sent":        "enviado",
+			"payment_status_failed":      "falló",
+			"payment_total":              "%s: %s a pagar, %s disponible",
+			"payment_insufficient":       "El saldo no cubre los pagos. Corrija el archivo o fondee la wallet.",
+			"payment_all_sent":           "Todos los pagos de este archivo ya fueron enviados.",
+			"payment_review_prompt":      "%d pago(s) pendiente(s) vía %s. Presione 'enter' para construir y firmar todas las transacciones.",
+			"payment_signing":            "Construyendo y firmando las transacciones...",
+			"payment_signed":             "%d transacción(es) firmada(s), comisiones de hasta %s %s. Presione 'enter' para transmitir.",
+			"payment_broadcasting":       "Transmitiendo...",
+			"payment_stopped":            "Transmisión detenida en la transacción %d de %d: %v. Presione 'r' para reanudar.",
+			"payment_done":               "%d transacción(es) transmitida(s). Resultados guardados en %s.",
+			"payment_results_error":      "Error al guardar el archivo de resultados: %v",
+			"rpc_status_healthy":         "RPC: %s %dms #%d",
+			"rpc_status_unhealthy":       "RPC: %s caído: %s",
+			"portfolio_verified":         "✓ Saldos probados en el bloque de confianza %d (%s)",
+			"portfolio_unverified":       "⚠ indica saldos que no pudieron probarse",
+			"portfolio_verify_error":     "⚠ Saldos no verificados: %v",
+			"private_key_in_backend":     "custodiada por el backend %s, nunca exportada",
+			"key_created_in_backend":     "La clave se generará dentro del backend %s y nunca saldrá de él. No hay frase mnemónica que respaldar.",
+			"keyring_unlock_failed":      "No se pudo desbloquear con el llavero (%v). Ingrese la contraseña.",
+			"keyring_remember":           "[%s] Recordar la contraseña en el llavero del sistema (Tab alterna)",
+			"keyring_saved":              "Contraseña guardada en el llavero del sistema.",
+			"keyring_forgotten":          "Contraseña eliminada del llavero del sistema.",
+			"keyring_forget_hint":        "La contraseña está guardada en el llavero del sistema. Presione 'f' para olvidarla.",
+			"keyring_error":              "Error del llavero: %v",
+			"pay_usage":                  "uso: pay <dirección de la cartera> <archivo.csv> [--disperse]",
+			"pay_no_disperse":            "no hay un contrato Disperse configurado para la red activa",
+			"pay_unknown_wallet":         "cartera %s no encontrada",
+			"pay_nothing":                "Todos los pagos del archivo ya fueron enviados.",
+			"pay_short":                  "saldo de %s insuficiente para los pagos pendientes",
+			"pay_signed":                 "%d transacción(es) firmada(s), comisiones de hasta %s %s.",
+			"pay_sent":                   "[%d/%d] %s",
+			"pay_stopped":                "transmisión detenida en la transacción %d de %d: %v; ejecute el comando de nuevo para reanudar (resultados en %s)",
+			"pay_done":                   "%d transacción(es) transmitida(s). Resultados guardados en %s.",
+			"vault":                      "Contraseña Maestra",
+			"vault_status_bar":           "Vista: %s | 'ctrl+x' salir",
+			"vault_locked":               "Bloqueado",
+			"master_password":            "Contraseña Maestra",
+			"master_password_desc":       "Cambiar la contraseña maestra",
+			"vault_password":             "Contraseña maestra",
+			"vault_new_password":         "Nueva contraseña maestra",
+			"vault_confirm_password":     "Confirme la nueva contraseña",
+			"vault_recovery_key":         "Clave de recuperación",
+			"vault_password_mismatch":    "Las contraseñas no coinciden.",
+			"vault_working":              "Derivando la clave...",
+			"vault_error":                "Error: %s",
+			"vault_wrong_password":       "contraseña maestra incorrecta",
+			"vault_invalid_recovery_key": "clave de recuperación inválida",
+			"vault_password_changed":     "Contraseña maestra cambiada.",
+			"vault_unlock_title":         "BLOCO está bloqueado",
+			"vault_unlock_desc":          "Introduzca la contraseña maestra para abrir sus carteras.",
+			"vault_unlock_hint":          "enter: desbloquear | ctrl+r: olvidé la contraseña | ctrl+x: salir",
+			"vault_setup_title":          "Defina una contraseña maestra",
+			"vault_setup_desc":           "La contraseña maestra bloquea la aplicación y cifra las frases mnemónicas, los contratos guardados y las notas de transacciones guardados en la base de datos. Es independiente de la contraseña de cada cartera.",
+			"vault_form_hint":            "tab: siguiente campo | enter: confirmar | esc: volver",
+			"vault_recover_title":        "Recuperar el acceso",
+			"vault_recover_desc":         "Introduzca la clave de recuperación anotada y elija una nueva contraseña maestra.",
+			"vault_recovery_key_title":   "Clave de recuperación",
+			"vault_recovery_key_desc":    "Anote esta clave y guárdela fuera del ordenador. Es la única forma de volver a entrar si olvida la contraseña maestra y no se mostrará de nuevo.",
+			"vault_recovery_key_hint":    "enter: ya la anoté",
+			"vault_change_title":         "Cambiar la contraseña maestra",
+			"vault_change_desc":          "Introduzca la contraseña maestra actual y la nueva. Las contraseñas de las carteras no cambian.",
+			"vault_change_hint":          "tab: siguiente campo | enter: confirmar | ctrl+r: nueva clave de recuperación | esc: volver",
+			"vault_new_recovery_title":   "Nueva clave de recuperación",
+			"vault_new_recovery_desc":    "Introduzca la contraseña maestra para reemplazar la clave de recuperación. La clave anterior deja de funcionar.",
+			"session_status":             "Bloqueo en %s",
+			"session_locked_idle":        "Bloqueado tras %s sin actividad.",
+			"session_locked_title":       "Sesión bloqueada",
+			"session_locked_desc":        "Las carteras desbloqueadas y los secretos escritos se borraron de la memoria.",
+			"session_locked_hint":        "enter: continuar | ctrl+x: salir",
 
-	appDir := filepath.Dir(labelsPath)
-	localesDir := filepath.Join(appDir, "locales")
-	if _, err := os.Stat(localesDir); os.IsNotExist(err) {
-		err := os.MkdirAll(localesDir, os.ModePerm)
-		if err != nil {
-			return err
-		}
-	}
-
-	err = os.WriteFile(labelsPath, data, 0644)
-	if err != nil {
-		return err
+			"two_factor_code":                  "Código de autenticación",
+			"two_factor_enroll_title":          "Autenticación en dos factores",
+			"two_factor_enroll_desc":           "Escanee el código QR o introduzca el secreto en una app de autenticación y escriba el código de 6 dígitos que muestra para activar la autenticación en dos factores de %s.",
+			"two_factor_secret":                "Secreto:",
+			"two_factor_uri":                   "URI:",
+			"two_factor_recovery_codes":        "Códigos de recuperación — cada uno reemplaza un código una vez. Anótelos: solo se muestran ahora.",
+			"two_factor_enroll_hint":           "Enter para confirmar • Esc para cancelar",
+			"two_factor_enabled":               "Autenticación en dos factores activada.",
+			"two_factor_settings_title":        "Autenticación en dos factores — %s",
+			"two_factor_settings_desc":         "Elija las operaciones que requieren un código además de la contraseña. Los cambios se guardan con un código actual.",
+			"two_factor_reveal":                "Revelar la clave privada y el mnemónico",
+			"two_factor_export":                "Exportar el libro mayor",
+			"two_factor_delete":                "Eliminar la wallet",
+			"two_factor_threshold":             "Firmar más de (%s, o cualquier token o llamada a contrato; vacío nunca pide):",
+			"two_factor_threshold_placeholder": "ej.: 0.5",
+			"two_factor_recovery_left":         "%d códigos de recuperación restantes",
+			"two_factor_settings_hint":         "↑/↓ para moverse • Espacio para marcar • Enter para guardar • Ctrl+D para desactivar • Esc para volver",
+			"two_factor_saved":                 "Configuración guardada.",
+			"two_factor_disabled":              "Autenticación en dos factores desactivada para esta wallet.",
+			"two_factor_challenge_title":       "Código de autenticación requerido",
+			"two_factor_challenge_desc":        "%s requiere el código del autenticador (o un código de recuperación) de %s.",
+			"two_factor_challenge_progress":    "Wallet %d de %d",
+			"two_factor_challenge_hint":        "Enter para confirmar • Esc para cancelar",
+			"two_factor_op_reveal":             "Revelar las claves",
+			"two_factor_op_export":             "Exportar el libro mayor",
+			"two_factor_op_delete":             "Eliminar la wallet",
+			"two_factor_op_sign":               "Firmar este importe",
+			"two_factor_error":                 "Error: %s",
+			"two_factor_invalid_code":          "código de autenticación inválido",
+			"two_factor_needs_vault":           "la autenticación en dos factores requiere la contraseña maestra de la aplicación",
+			"two_factor_invalid_threshold":     "Importe inválido: %s",
+			"two_factor_hidden":                "oculto — pulse 'r' e introduzca el código de autenticación",
+			"two_factor_details_hint":          "Pulse 't' para la autenticación en dos factores.",
+			"pay_two_factor":                   "la wallet %s requiere un código de dos factores para firmar %s %s; ejecute el pago desde el modo interactivo",
+			"unlock_wrong_password":            "Contraseña incorrecta.",
+			"unlock_blocked":                   "Demasiados intentos fallidos: espere antes de volver a intentarlo.",
+			"unlock_attempts_left":             "Quedan %d intento(s) antes del bloqueo de la wallet.",
+			"unlock_wait":                      "Próximo intento en %s.",
+			"unlock_locked_out":                "Bloqueada tras %d intentos fallidos hasta las %s (faltan %s).",
+			"unlock_release_hint":              "Pulse Ctrl+U para levantar el bloqueo con la contraseña maestra.",
+			"unlock_release_title":             "Introduzca la contraseña maestra para levantar el bloqueo",
+			"unlock_release_placeholder":       "Contraseña maestra",
+			"unlock_release_help":              "Pulse Enter para confirmar o Esc para volver.",
+			"unlock_released":                  "Bloqueo levantado. Ya puede introducir la contraseña de la wallet.",
+			"unlock_release_error":             "Error: %s",
+			"pay_two_factor_tokens":            "la wallet %s requiere un código de dos factores para firmar pagos en tokens; ejecute el pago desde el modo interactivo",
+			"two_factor_blocked":               "demasiados códigos incorrectos, espere antes de volver a intentarlo",
+			"headless_master_password":         "la contraseña maestra está definida: configure master_password.providers para ejecutar sin la interfaz",
+			"vault_blocked":                    "demasiadas contraseñas maestras incorrectas, espere antes de volver a intentarlo",
+			"sweep_fee_note":                   "Cada comisión se limita a la tarifa base del próximo bloque más un margen del 25% y la propina; lo que la comisión no use queda en la wallet.",
+			"two_factor_no_vault":              "defina primero una contraseña maestra: quite master_password.disabled de la configuración y reinicie",
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported language: %s", lang)
 	}
-
-	return nil
 }
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"math/big"
 	"os"
 	"path/filepath"
 
 	"blocowallet/config"
+	"blocowallet/constants"
+	"blocowallet/domain"
 	"blocowallet/infrastructure"
 	"blocowallet/interfaces"
 	"blocowallet/localization"
@@ -14,6 +19,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/go-errors/errors"
 )
 
@@ -78,10 +86,163 @@ func main() {
 	// Inicializar o keystore
 	ks := keystore.NewKeyStore(cfg.WalletsDir, keystore.StandardScryptN, keystore.StandardScryptP)
 
-	// Usar o serviço no modelo CLI
-	service := usecases.NewWalletService(repo, ks)
+	// Usar o serviço no modelo CLI, com as chaves nos backends configurados
+	backends, err := newKeyBackends(cfg.KeyBackends, ks)
+	if err != nil {
+		handleError("Erro ao configurar os backends de chaves", err)
+	}
+	defer closeKeyBackends(backends)
+	service := usecases.NewWalletService(repo, backends...)
+	if !cfg.Keyring.Disabled {
+		service.Secrets = infrastructure.NewSecretServiceStore(cfg.Keyring.Service)
+	}
+	if len(cfg.PasswordProviders) > 0 || len(cfg.WalletPasswords) > 0 {
+		service.Passwords, err = newPasswordChain(cfg)
+		if err != nil {
+			handleError("Erro ao configurar os provedores de senha", err)
+		}
+	}
+	if cfg.Session.TTL > 0 || cfg.Session.IdleLock > 0 {
+		service.Session = usecases.NewSession(cfg.Session.TTL, cfg.Session.IdleLock)
+	}
 	model := interfaces.NewCLIModel(service)
 
+	// Senha mestra: exigida quando já definida ou, salvo se desativada, pedida na primeira execução
+	vault := usecases.NewVaultService(repo)
+	vaultSet, err := vault.Initialized()
+	if err != nil {
+		handleError("Erro ao ler a senha mestra", err)
+	}
+	if vaultSet || !cfg.MasterPassword.Disabled {
+		model.Vault = vault
+	}
+
+	// Limite de tentativas de senha por wallet; a senha mestra encerra o bloqueio
+	if !cfg.UnlockThrottle.Disabled {
+		throttle := cfg.UnlockThrottle
+		service.Guard = usecases.NewUnlockGuard(repo, throttle.MaxAttempts, throttle.BaseDelay, throttle.MaxDelay, throttle.Cooldown)
+		service.Guard.Vault = model.Vault
+		// A senha mestra segue os mesmos limites, sem outra senha que encerre o bloqueio
+		vault.Guard = service.Guard.For(domain.UnlockMasterPassword)
+		vault.Guard.Vault = nil
+	}
+
+	// Autenticação em dois fatores por wallet, com o segredo cifrado pela senha mestra
+	twoFactor := usecases.NewTwoFactorService(repo, vault)
+	if service.Guard != nil {
+		twoFactor.Guard = service.Guard.For(domain.UnlockTwoFactor)
+	}
+	model.TwoFactor = twoFactor
+
+	// Livro-razão local das transações assinadas ou transmitidas
+	model.Ledger = usecases.NewLedgerService(repo, filepath.Join(appDir, "exports"))
+
+	// Modo dev: cadeia local em processo com as wallets do repositório pré-financiadas.
+	// Ela recomeça a cada execução com o mesmo chain ID e o mesmo gênesis, então suas
+	// respostas não passam pelo cache persistente de RPC.
+	var rpcCache domain.RPCCache = repo
+	if len(os.Args) > 1 && os.Args[1] == "dev" {
+		devChain, err := startDevChain(cfg, repo)
+		if err != nil {
+			handleError("Erro ao iniciar a cadeia local de desenvolvimento", err)
+		}
+		defer closeDevChain(devChain)
+		model.DevChain = devChain
+		model.DevEndpoint = devChain.Endpoint
+		rpcCache = nil
+	}
+
+	// Conectar ao nó JSON-RPC da rede ativa
+	network, err := cfg.Network()
+	if err != nil {
+		handleError("Erro ao carregar a rede ativa", err)
+	}
+
+	client, failover, err := dialNetwork(cfg.RPC, network, rpcCache)
+	if err != nil {
+		handleError("Erro ao conectar ao nó RPC", err)
+	}
+	defer client.Close()
+	if failover != nil {
+		defer failover.Close()
+		model.RPC = failover
+	}
+
+	model.Transactions = usecases.NewTransactionService(client, model.Ledger)
+	model.NFTs = usecases.NewNFTService(client, network.NFTCollections)
+	model.NFTs.FromBlock = network.LogsFromBlock
+	model.NFTs.BlockRange = network.LogsBlockRange
+
+	// Varredura de saldos para um único destino
+	model.Sweeps = usecases.NewSweepService(model.Transactions, networkAssets(network))
+
+	// Pagamentos em lote, opcionalmente por um contrato Disperse
+	model.Payments = usecases.NewPaymentService(model.Transactions, networkAssets(network), network.Disperse)
+
+	// Histórico de transações via API compatível com Etherscan, na cadeia da rede ativa
+	explorer := infrastructure.NewEtherscanProvider(cfg.Explorer.BaseURL, cfg.Explorer.APIKey, network.ChainID)
+	model.History = usecases.NewHistoryService(explorer, repo, network.ChainID)
+	model.History.PageSize = cfg.Explorer.PageSize
+	model.History.CacheTTL = cfg.Explorer.CacheTTL
+
+	// Modo sem interface: pagamento em lote com a senha vinda dos provedores
+	if len(os.Args) > 1 && os.Args[1] == "pay" {
+		if vaultSet {
+			if err := unlockVaultHeadless(vault, cfg.MasterPassword.Providers); err != nil {
+				handleError("Erro ao abrir a senha mestra", err)
+			}
+		}
+		if err := runPayments(service, model.Payments, twoFactor, os.Args[2:]); err != nil {
+			handleError("Erro ao executar o pagamento em lote", err)
+		}
+		return
+	}
+
+	// Auditoria de aprovações de tokens
+	model.Approvals = usecases.NewApprovalService(client)
+	model.Approvals.FromBlock = network.LogsFromBlock
+	model.Approvals.BlockRange = network.LogsBlockRange
+
+	// Workbench de contratos com os contratos salvos por rede
+	model.Contracts = usecases.NewContractService(client, repo, network.ChainID)
+
+	// Decodificação de calldata na revisão, com a base local de seletores de 4 bytes
+	if _, err := os.Stat(cfg.SignaturesFile); err == nil {
+		count, err := usecases.ImportSignatures(repo, cfg.SignaturesFile)
+		if err != nil {
+			log.Printf("Erro ao importar a base de seletores: %v\n", err)
+		} else {
+			log.Printf("%d assinaturas importadas de %s\n", count, cfg.SignaturesFile)
+		}
+	}
+	model.Decoder = usecases.NewCalldataDecoder(client, repo, repo, network.ChainID)
+
+	// Simulação das transações antes da assinatura
+	model.Simulator = usecases.NewSimulationService(client.Client(), client)
+
+	// Descoberta de contas HD ao importar uma frase mnemônica
+	model.Discovery = usecases.NewAccountDiscovery(client, cfg.GapLimit)
+
+	// Resolução de nomes ENS quando a rede possui um registro configurado
+	if network.ENSRegistry != "" {
+		model.Names = infrastructure.NewENSResolver(client, network.ENSRegistry, cfg.ENSCacheTTL)
+	}
+
+	// Avaliação do portfólio com a fonte de cotações configurada
+	prices, err := newPriceProvider(cfg.Pricing, network, client)
+	if err != nil {
+		handleError("Erro ao configurar a fonte de cotações", err)
+	}
+	model.Portfolio = usecases.NewPortfolioService(client, prices, networkAssets(network), cfg.Pricing.Currency)
+	if network.Verification.Enabled {
+		verifier, err := newBalanceVerifier(network, client)
+		if err != nil {
+			handleError("Erro ao configurar a verificação de saldos", err)
+		}
+		model.Portfolio.Verifier = verifier
+	}
+	model.PortfolioRefresh = cfg.Pricing.RefreshInterval
+
 	// Iniciar o programa Bubble Tea com tela cheia
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -91,6 +252,165 @@ func main() {
 
 // Funções auxiliares
 
+// dialNetwork conecta aos endpoints da rede. Endpoints HTTP(S) passam pelo transporte
+// de failover, com verificação de saúde, limite de requisições e cache das respostas
+// imutáveis; um único endpoint WebSocket ou IPC é conectado diretamente.
+func dialNetwork(rpcConfig config.RPCConfig, network config.NetworkConfig, cache domain.RPCCache) (*ethclient.Client, *infrastructure.FailoverTransport, error) {
+	endpoints := network.Endpoints()
+	if len(endpoints) == 1 && !infrastructure.IsHTTPEndpoint(endpoints[0]) {
+		client, err := ethclient.Dial(endpoints[0])
+		return client, nil, err
+	}
+	failover, err := infrastructure.NewFailoverTransport(endpoints, network.ChainID, cache, infrastructure.FailoverOptions{
+		HealthInterval: rpcConfig.HealthInterval,
+		Timeout:        rpcConfig.Timeout,
+		MaxHeadLag:     rpcConfig.MaxHeadLag,
+		RateLimit:      rpcConfig.RateLimit,
+		CacheDepth:     rpcConfig.CacheDepth,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	rpcClient, err := failover.Dial(context.Background())
+	if err != nil {
+		failover.Close()
+		return nil, nil, err
+	}
+	return ethclient.NewClient(rpcClient), failover, nil
+}
+
+func newPriceProvider(pricing config.PricingConfig, network config.NetworkConfig, client *ethclient.Client) (domain.PriceProvider, error) {
+	switch pricing.Provider {
+	case "coingecko":
+		return infrastructure.NewCoinGeckoProvider(pricing.BaseURL, pricing.APIKey, pricing.APIKeyHeader, network.PriceID, network.PricePlatform), nil
+	case "static":
+		return infrastructure.NewStaticPriceProvider(pricing.StaticFile), nil
+	case "chainlink":
+		return infrastructure.NewChainlinkPriceProvider(client, network.PriceFeeds), nil
+	default:
+		return nil, fmt.Errorf("fonte de cotações desconhecida: %s", pricing.Provider)
+	}
+}
+
+// newKeyBackends lista os backends de chaves com o padrão em primeiro lugar;
+// o keystore local está sempre disponível para as wallets existentes
+func newKeyBackends(keyConfig config.KeyBackendsConfig, ks *keystore.KeyStore) ([]domain.KeyBackend, error) {
+	backends := []domain.KeyBackend{infrastructure.NewKeystoreBackend(ks)}
+	if vault := keyConfig.Vault; vault.Address != "" {
+		if vault.Token == "" && vault.RoleID == "" {
+			vault.Token = os.Getenv("VAULT_TOKEN")
+		}
+		backends = append(backends, infrastructure.NewVaultBackend(infrastructure.VaultOptions{
+			Address:      vault.Address,
+			Namespace:    vault.Namespace,
+			Mount:        vault.Mount,
+			Token:        vault.Token,
+			RoleID:       vault.RoleID,
+			SecretID:     vault.SecretID,
+			AppRoleMount: vault.AppRoleMount,
+			KeyType:      vault.KeyType,
+			DeleteKeys:   vault.DeleteKeys,
+		}))
+	}
+	if kmsConfig := keyConfig.KMS; kmsConfig.Region != "" {
+		kms, err := infrastructure.NewKMSBackend(context.Background(), infrastructure.KMSOptions{
+			Region:         kmsConfig.Region,
+			Endpoint:       kmsConfig.Endpoint,
+			Profile:        kmsConfig.Profile,
+			DeleteKeys:     kmsConfig.DeleteKeys,
+			DeletionWindow: kmsConfig.DeletionWindow,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao configurar o AWS KMS: %v", err)
+		}
+		backends = append(backends, kms)
+	}
+	if token := keyConfig.PKCS11; token.Library != "" {
+		if token.PIN == "" {
+			token.PIN = os.Getenv("PKCS11_PIN")
+		}
+		hsm, err := infrastructure.NewPKCS11Backend(infrastructure.PKCS11Options{
+			Library:    token.Library,
+			Slot:       token.Slot,
+			TokenLabel: token.TokenLabel,
+			PIN:        token.PIN,
+			DeleteKeys: token.DeleteKeys,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao configurar o módulo PKCS#11: %v", err)
+		}
+		backends = append(backends, hsm)
+	}
+
+	for i, backend := range backends {
+		if backend.Name() == keyConfig.Default {
+			backends[0], backends[i] = backends[i], backends[0]
+			return backends, nil
+		}
+	}
+	return nil, fmt.Errorf("backend de chaves padrão não configurado: %s", keyConfig.Default)
+}
+
+// newBalanceVerifier prova os saldos contra o checkpoint fixado na configuração ou,
+// na ausência dele, contra o cabeçalho do nó confiável
+func newBalanceVerifier(network config.NetworkConfig, client *ethclient.Client) (*usecases.BalanceVerifier, error) {
+	verification := network.Verification
+	var checkpoint domain.CheckpointProvider
+	switch {
+	case verification.CheckpointHash != "":
+		checkpoint = infrastructure.NewStaticCheckpoint(client.Client(), verification.CheckpointBlock, verification.CheckpointHash)
+	case verification.TrustedRPC != "":
+		trusted, err := rpc.Dial(verification.TrustedRPC)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao conectar ao nó confiável: %v", err)
+		}
+		checkpoint = infrastructure.NewTrustedNodeCheckpoint(trusted, verification.TrustedBlock)
+	default:
+		return nil, fmt.Errorf("a verificação exige checkpoint_hash ou trusted_rpc")
+	}
+
+	slots := map[common.Address]uint64{}
+	for _, token := range network.Tokens {
+		if token.BalanceSlot != nil {
+			slots[common.HexToAddress(token.Address)] = *token.BalanceSlot
+		}
+	}
+	return usecases.NewBalanceVerifier(client.Client(), checkpoint, slots), nil
+}
+
+// networkAssets lista a moeda nativa seguida dos tokens configurados na rede
+func networkAssets(network config.NetworkConfig) []domain.Asset {
+	assets := []domain.Asset{{Symbol: network.Symbol, Decimals: constants.NativeDecimals}}
+	for _, token := range network.Tokens {
+		assets = append(assets, domain.Asset{Symbol: token.Symbol, Contract: token.Address, Decimals: token.Decimals})
+	}
+	return assets
+}
+
+// startDevChain inicia a cadeia local, financia todas as wallets e a registra como rede ativa
+func startDevChain(cfg *config.Config, repo domain.WalletRepository) (*infrastructure.DevChain, error) {
+	wallets, err := repo.GetAllWallets()
+	if err != nil {
+		return nil, err
+	}
+	balance, err := usecases.ParseUnits(cfg.Dev.Prefund, constants.NativeDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("valor de pré-financiamento inválido: %v", err)
+	}
+	prefund := make(map[common.Address]*big.Int, len(wallets))
+	for _, w := range wallets {
+		prefund[common.HexToAddress(w.Address)] = balance
+	}
+
+	devChain, err := infrastructure.NewDevChain(cfg.Dev.RPCHost, cfg.Dev.RPCPort, cfg.Dev.BlockGasLimit, prefund)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RegisterDevNetwork(devChain.Endpoint, infrastructure.DevChainID)
+	log.Printf("Cadeia de desenvolvimento em %s com %d wallets financiadas\n", devChain.Endpoint, len(wallets))
+	return devChain, nil
+}
+
 func handleError(message string, err error) {
 	log.Println(errors.Wrap(err, 0).ErrorStack())
 	fmt.Println(message)
@@ -103,6 +423,23 @@ func closeFile(file *os.File) {
 	}
 }
 
+func closeDevChain(devChain *infrastructure.DevChain) {
+	if err := devChain.Close(); err != nil {
+		log.Printf("Erro ao encerrar a cadeia de desenvolvimento: %v\n", err)
+	}
+}
+
+// closeKeyBackends libera os backends que mantêm sessões abertas, como o PKCS#11
+func closeKeyBackends(backends []domain.KeyBackend) {
+	for _, backend := range backends {
+		if closer, ok := backend.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Printf("Erro ao fechar o backend de chaves %s: %v\n", backend.Name(), err)
+			}
+		}
+	}
+}
+
 func closeResource(repo *infrastructure.SQLiteRepository) {
 	if err := repo.Close(); err != nil {
 		log.Printf("Erro ao fechar o banco de dados: %v\n", err)
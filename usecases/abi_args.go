@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var bigIntType = reflect.TypeOf(new(big.Int))
+
+// ParseArguments converts the text typed for each input into the Go values expected by abi.Pack
+func ParseArguments(inputs abi.Arguments, values []string) ([]interface{}, error) {
+	if len(values) != len(inputs) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(inputs), len(values))
+	}
+	args := make([]interface{}, len(inputs))
+	for i, input := range inputs {
+		value, err := ParseArgument(input.Type, values[i])
+		if err != nil {
+			name := input.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("%s (%s): %v", name, input.Type.String(), err)
+		}
+		args[i] = value
+	}
+	return args, nil
+}
+
+// ParseArgument converts text into a value of the given ABI type.
+// Integers accept decimal or 0x-prefixed hex; arrays and tuples use JSON arrays,
+// e.g. ["0xabc…", "0xdef…"] or [1, "0x01", true].
+func ParseArgument(t abi.Type, input string) (interface{}, error) {
+	value, err := parseValue(t, strings.TrimSpace(input))
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+func parseValue(t abi.Type, input string) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return parseInteger(t, input)
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("expected true or false")
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		return reflect.ValueOf(input), nil
+	case abi.AddressTy:
+		if !common.IsHexAddress(input) {
+			return reflect.Value{}, fmt.Errorf("invalid address")
+		}
+		return reflect.ValueOf(common.HexToAddress(input)), nil
+	case abi.BytesTy:
+		data, err := hexutil.Decode(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("expected 0x-prefixed hex")
+		}
+		return reflect.ValueOf(data), nil
+	case abi.FixedBytesTy, abi.HashTy:
+		data, err := hexutil.Decode(input)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("expected 0x-prefixed hex")
+		}
+		if len(data) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(data))
+		}
+		value := reflect.New(t.GetType()).Elem()
+		reflect.Copy(value, reflect.ValueOf(data))
+		return value, nil
+	case abi.SliceTy, abi.ArrayTy:
+		elements, err := splitJSONArray(input)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t.T == abi.ArrayTy && len(elements) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", t.Size, len(elements))
+		}
+		var value reflect.Value
+		if t.T == abi.SliceTy {
+			value = reflect.MakeSlice(t.GetType(), len(elements), len(elements))
+		} else {
+			value = reflect.New(t.GetType()).Elem()
+		}
+		for i, element := range elements {
+			item, err := parseValue(*t.Elem, element)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %v", i, err)
+			}
+			value.Index(i).Set(item)
+		}
+		return value, nil
+	case abi.TupleTy:
+		elements, err := splitJSONArray(input)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(elements) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected %d fields, got %d", len(t.TupleElems), len(elements))
+		}
+		value := reflect.New(t.TupleType).Elem()
+		for i, elem := range t.TupleElems {
+			field, err := parseValue(*elem, elements[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s: %v", t.TupleRawNames[i], err)
+			}
+			value.Field(i).Set(field)
+		}
+		return value, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t.String())
+}
+
+func parseInteger(t abi.Type, input string) (reflect.Value, error) {
+	n, ok := new(big.Int).SetString(input, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer")
+	}
+	if t.T == abi.UintTy {
+		if n.Sign() < 0 || n.BitLen() > t.Size {
+			return reflect.Value{}, fmt.Errorf("out of range for uint%d", t.Size)
+		}
+	} else {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+		if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return reflect.Value{}, fmt.Errorf("out of range for int%d", t.Size)
+		}
+	}
+
+	goType := t.GetType()
+	if goType == bigIntType {
+		return reflect.ValueOf(n), nil
+	}
+	if t.T == abi.UintTy {
+		return reflect.ValueOf(n.Uint64()).Convert(goType), nil
+	}
+	return reflect.ValueOf(n.Int64()).Convert(goType), nil
+}
+
+// splitJSONArray returns the raw text of each element; JSON strings are unquoted
+func splitJSONArray(input string) ([]string, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(input), &raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array")
+	}
+	elements := make([]string, len(raw))
+	for i, element := range raw {
+		var s string
+		if err := json.Unmarshal(element, &s); err == nil {
+			elements[i] = s
+			continue
+		}
+		elements[i] = string(element)
+	}
+	return elements, nil
+}
+
+// FormatValue renders a value returned by abi.Unpack for display
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case string:
+		return strconv.Quote(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(data), rv)
+			return hexutil.Encode(data)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = FormatValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		fields := make([]string, rv.NumField())
+		for i := range fields {
+			fields[i] = rv.Type().Field(i).Name + ": " + FormatValue(rv.Field(i).Interface())
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultGapLimit is the number of consecutive unused addresses after which
+// discovery stops, as recommended by BIP-44
+const DefaultGapLimit = 20
+
+// DiscoveredAccount is a derived address of a mnemonic with on-chain activity
+type DiscoveredAccount struct {
+	Index      uint32
+	Address    string
+	Nonce      uint64
+	Balance    *big.Int
+	Registered bool // Already present in the wallet repository
+}
+
+// AccountDiscovery walks the BIP-44 indices of a mnemonic looking for used accounts
+type AccountDiscovery struct {
+	Client   domain.ChainClient
+	GapLimit int
+}
+
+func NewAccountDiscovery(client domain.ChainClient, gapLimit int) *AccountDiscovery {
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+	return &AccountDiscovery{Client: client, GapLimit: gapLimit}
+}
+
+// Discover returns the accounts with a nonce or balance, in index order. The walk
+// stops once GapLimit consecutive indices have neither.
+func (d *AccountDiscovery) Discover(ctx context.Context, mnemonic string, registered []domain.Wallet) ([]DiscoveredAccount, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic phrase")
+	}
+	known := make(map[string]bool, len(registered))
+	for _, w := range registered {
+		known[strings.ToLower(w.Address)] = true
+	}
+
+	var accounts []DiscoveredAccount
+	gap := 0
+	for index := uint32(0); gap < d.GapLimit; index++ {
+		address, err := deriveAddress(mnemonic, index)
+		if err != nil {
+			return nil, err
+		}
+		account, used, err := d.inspect(ctx, index, address)
+		if err != nil {
+			return nil, err
+		}
+		if !used {
+			gap++
+			continue
+		}
+		gap = 0
+		account.Registered = known[strings.ToLower(account.Address)]
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func (d *AccountDiscovery) inspect(ctx context.Context, index uint32, address string) (DiscoveredAccount, bool, error) {
+	account := DiscoveredAccount{Index: index, Address: address}
+	nonce, err := d.Client.NonceAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return account, false, fmt.Errorf("error reading the nonce of %s: %v", address, err)
+	}
+	balance, err := d.Client.BalanceAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return account, false, fmt.Errorf("error reading the balance of %s: %v", address, err)
+	}
+	account.Nonce = nonce
+	account.Balance = balance
+	return account, nonce > 0 || balance.Sign() > 0, nil
+}
+
+func deriveAddress(mnemonic string, index uint32) (string, error) {
+	privateKeyHex, err := DerivePrivateKeyAt(mnemonic, index)
+	if err != nil {
+		return "", err
+	}
+	privKey, err := HexToECDSA(privateKeyHex)
+	if err != nil {
+		return "", err
+	}
+	return crypto.PubkeyToAddress(privKey.PublicKey).Hex(), nil
+}
+
+// ImportDerivedWallets registers the given indices of a mnemonic, all encrypted
+// with the same password. Addresses already in the repository are skipped.
+func (ws *WalletService) ImportDerivedWallets(mnemonic, password string, indices []uint32) ([]domain.Wallet, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic phrase")
+	}
+	existing, err := ws.Repo.GetAllWallets()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, w := range existing {
+		known[strings.ToLower(w.Address)] = true
+	}
+
+	var imported []domain.Wallet
+	for _, index := range indices {
+		privateKeyHex, err := DerivePrivateKeyAt(mnemonic, index)
+		if err != nil {
+			return imported, err
+		}
+		privKey, err := HexToECDSA(privateKeyHex)
+		if err != nil {
+			return imported, err
+		}
+		if known[strings.ToLower(crypto.PubkeyToAddress(privKey.PublicKey).Hex())] {
+			continue
+		}
+
+		details, err := ws.storeKey(privKey, mnemonic, password)
+		if err != nil {
+			return imported, err
+		}
+		wallet := *details.Wallet
+		known[strings.ToLower(wallet.Address)] = true
+		imported = append(imported, wallet)
+	}
+	return imported, nil
+}
@@ -0,0 +1,152 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ApprovalKind distinguishes ERC-20 allowances from operator approvals (ERC-721/1155)
+type ApprovalKind string
+
+const (
+	ApprovalAllowance ApprovalKind = "allowance"
+	ApprovalOperator  ApprovalKind = "operator"
+)
+
+// TokenApproval is an approval that is still active on chain
+type TokenApproval struct {
+	Owner     string
+	Token     string
+	Spender   string
+	Kind      ApprovalKind
+	Allowance *big.Int // Current allowance; nil for operator approvals
+	Symbol    string
+	Decimals  int
+	Block     uint64 // Block of the most recent approval event
+}
+
+// Risky reports unlimited allowances and operator approvals, which expose the whole balance
+func (a TokenApproval) Risky() bool {
+	return a.Kind == ApprovalOperator || isUnlimited(a.Allowance, 256)
+}
+
+// ApprovalService audits the approvals granted by a wallet and builds their revocations
+type ApprovalService struct {
+	Client     domain.ChainClient
+	FromBlock  uint64
+	BlockRange uint64 // Maximum blocks per eth_getLogs request, 0 for a single request
+}
+
+func NewApprovalService(client domain.ChainClient) *ApprovalService {
+	return &ApprovalService{Client: client}
+}
+
+// Scan collects every (token, spender) pair from Approval and ApprovalForAll events
+// emitted for owner and keeps those whose current on-chain state is still non-zero
+func (as *ApprovalService) Scan(ctx context.Context, owner common.Address) ([]TokenApproval, error) {
+	head, err := as.Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the latest block: %v", err)
+	}
+	query := ethereum.FilterQuery{
+		Topics: [][]common.Hash{
+			{ERC20ABI.Events["Approval"].ID, ERC721ABI.Events["ApprovalForAll"].ID},
+			{common.BytesToHash(owner.Bytes())},
+		},
+	}
+	logs, err := FilterLogsInRange(ctx, as.Client, query, as.FromBlock, head, as.BlockRange)
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct {
+		token, spender common.Address
+		kind           ApprovalKind
+	}
+	latest := map[pair]uint64{}
+	for _, entry := range logs {
+		// ERC-721 Approval events index the token id and are cleared on transfer
+		if len(entry.Topics) != 3 {
+			continue
+		}
+		kind := ApprovalAllowance
+		if entry.Topics[0] == ERC721ABI.Events["ApprovalForAll"].ID {
+			kind = ApprovalOperator
+		}
+		key := pair{token: entry.Address, spender: topicAddress(entry.Topics[2]), kind: kind}
+		if entry.BlockNumber >= latest[key] {
+			latest[key] = entry.BlockNumber
+		}
+	}
+
+	var approvals []TokenApproval
+	for key, block := range latest {
+		approval := TokenApproval{
+			Owner:   owner.Hex(),
+			Token:   key.token.Hex(),
+			Spender: key.spender.Hex(),
+			Kind:    key.kind,
+			Block:   block,
+		}
+		if key.kind == ApprovalOperator {
+			out, err := callContract(ctx, as.Client, key.token, ERC721ABI, "isApprovedForAll", owner, key.spender)
+			if err != nil {
+				return nil, fmt.Errorf("error reading the operator approval of %s on %s: %v", key.spender.Hex(), key.token.Hex(), err)
+			}
+			if approved, _ := out[0].(bool); !approved {
+				continue
+			}
+		} else {
+			out, err := callContract(ctx, as.Client, key.token, ERC20ABI, "allowance", owner, key.spender)
+			if err != nil {
+				return nil, fmt.Errorf("error reading the allowance of %s on %s: %v", key.spender.Hex(), key.token.Hex(), err)
+			}
+			approval.Allowance, _ = out[0].(*big.Int)
+			if approval.Allowance == nil || approval.Allowance.Sign() == 0 {
+				continue
+			}
+			if out, err := callContract(ctx, as.Client, key.token, ERC20ABI, "decimals"); err == nil {
+				if decimals, ok := out[0].(uint8); ok {
+					approval.Decimals = int(decimals)
+				}
+			}
+		}
+		if out, err := callContract(ctx, as.Client, key.token, ERC20ABI, "symbol"); err == nil {
+			approval.Symbol, _ = out[0].(string)
+		}
+		approvals = append(approvals, approval)
+	}
+
+	// Risky approvals first, then the most recent
+	sort.Slice(approvals, func(i, j int) bool {
+		if approvals[i].Risky() != approvals[j].Risky() {
+			return approvals[i].Risky()
+		}
+		return approvals[i].Block > approvals[j].Block
+	})
+	return approvals, nil
+}
+
+// RevokeRequest builds the call that resets an approval: approve(spender, 0)
+// for allowances or setApprovalForAll(operator, false) for operators
+func (as *ApprovalService) RevokeRequest(approval TokenApproval) (TxRequest, error) {
+	token := common.HexToAddress(approval.Token)
+	spender := common.HexToAddress(approval.Spender)
+	var data []byte
+	var err error
+	if approval.Kind == ApprovalOperator {
+		data, err = ERC721ABI.Pack("setApprovalForAll", spender, false)
+	} else {
+		data, err = ERC20ABI.Pack("approve", spender, new(big.Int))
+	}
+	if err != nil {
+		return TxRequest{}, err
+	}
+	return TxRequest{To: &token, Data: data}, nil
+}
@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ErrNoBalanceSlot is returned for tokens whose balance mapping slot is not configured
+var ErrNoBalanceSlot = errors.New("no balance slot configured")
+
+// VerifiedBlock identifies the trusted header balances were proven against
+type VerifiedBlock struct {
+	Number uint64
+	Hash   common.Hash
+	Root   common.Hash
+}
+
+// ProvenBalances holds balances read from eth_getProof and checked against the
+// state root of a trusted block, with the reason each unproven balance failed
+type ProvenBalances struct {
+	Block    VerifiedBlock
+	balances map[string]*big.Int
+	errors   map[string]error
+}
+
+// Balance returns the proven balance of owner in asset
+func (p *ProvenBalances) Balance(owner common.Address, asset domain.Asset) (*big.Int, error) {
+	key := provenKey(owner, asset)
+	if err, ok := p.errors[key]; ok {
+		return nil, err
+	}
+	balance, ok := p.balances[key]
+	if !ok {
+		return nil, errors.New("balance not proven")
+	}
+	return balance, nil
+}
+
+func provenKey(owner common.Address, asset domain.Asset) string {
+	return strings.ToLower(owner.Hex()) + ":" + asset.ID()
+}
+
+// BalanceVerifier proves native balances through account proofs and ERC-20
+// balances through storage proofs of the token's balance mapping. Token slots
+// follow the Solidity layout keccak256(owner . slot) and are configured per token.
+type BalanceVerifier struct {
+	RPC        domain.RPCCaller
+	Checkpoint domain.CheckpointProvider
+	Slots      map[common.Address]uint64 // Balance mapping slot per token contract
+}
+
+func NewBalanceVerifier(rpc domain.RPCCaller, checkpoint domain.CheckpointProvider, slots map[common.Address]uint64) *BalanceVerifier {
+	return &BalanceVerifier{
+		RPC:        rpc,
+		Checkpoint: checkpoint,
+		Slots:      slots,
+	}
+}
+
+type proofResult struct {
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	StorageProof []struct {
+		Proof []hexutil.Bytes `json:"proof"`
+	} `json:"storageProof"`
+}
+
+// Verify proves the balances of owners in every asset at the trusted block. An
+// error is returned only when the trusted header cannot be obtained; individual
+// failures are kept in the result so they can be flagged.
+func (v *BalanceVerifier) Verify(ctx context.Context, owners []common.Address, assets []domain.Asset) (*ProvenBalances, error) {
+	header, err := v.Checkpoint.TrustedHeader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the trusted checkpoint: %v", err)
+	}
+	proven := &ProvenBalances{
+		Block:    VerifiedBlock{Number: header.Number.Uint64(), Hash: header.Hash(), Root: header.Root},
+		balances: map[string]*big.Int{},
+		errors:   map[string]error{},
+	}
+	block := hexutil.EncodeBig(header.Number)
+
+	for _, asset := range assets {
+		if asset.Contract == "" {
+			for _, owner := range owners {
+				account, err := v.proveAccount(ctx, owner, block, header.Root)
+				v.record(proven, owner, asset, accountBalance(account), err)
+			}
+			continue
+		}
+
+		token := common.HexToAddress(asset.Contract)
+		slot, ok := v.Slots[token]
+		if !ok {
+			for _, owner := range owners {
+				v.record(proven, owner, asset, nil, ErrNoBalanceSlot)
+			}
+			continue
+		}
+		keys := make([]common.Hash, len(owners))
+		for i, owner := range owners {
+			keys[i] = balanceSlotKey(owner, slot)
+		}
+		values, err := v.proveStorage(ctx, token, keys, block, header.Root)
+		for i, owner := range owners {
+			if err != nil {
+				v.record(proven, owner, asset, nil, err)
+				continue
+			}
+			v.record(proven, owner, asset, values[i], nil)
+		}
+	}
+	return proven, nil
+}
+
+func (v *BalanceVerifier) record(proven *ProvenBalances, owner common.Address, asset domain.Asset, balance *big.Int, err error) {
+	key := provenKey(owner, asset)
+	if err != nil {
+		proven.errors[key] = err
+		return
+	}
+	proven.balances[key] = balance
+}
+
+// proveAccount fetches eth_getProof and verifies the account against root. A
+// valid proof of absence returns a nil account.
+func (v *BalanceVerifier) proveAccount(ctx context.Context, address common.Address, block string, root common.Hash) (*types.StateAccount, error) {
+	account, _, err := v.getProof(ctx, address, nil, block, root)
+	return account, err
+}
+
+// proveStorage verifies the token account and then each storage slot against the
+// account's storage root, returning the slot values in the order of keys
+func (v *BalanceVerifier) proveStorage(ctx context.Context, token common.Address, keys []common.Hash, block string, root common.Hash) ([]*big.Int, error) {
+	account, result, err := v.getProof(ctx, token, keys, block, root)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("token %s does not exist at the trusted block", token.Hex())
+	}
+	if len(result.StorageProof) != len(keys) {
+		return nil, fmt.Errorf("expected %d storage proofs, got %d", len(keys), len(result.StorageProof))
+	}
+	values := make([]*big.Int, len(keys))
+	for i, key := range keys {
+		value, err := verifyProof(account.Root, crypto.Keccak256(key.Bytes()), result.StorageProof[i].Proof)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage proof: %v", err)
+		}
+		values[i] = new(big.Int)
+		if len(value) == 0 {
+			continue
+		}
+		var content []byte
+		if err := rlp.DecodeBytes(value, &content); err != nil {
+			return nil, fmt.Errorf("invalid storage value: %v", err)
+		}
+		values[i].SetBytes(content)
+	}
+	return values, nil
+}
+
+func (v *BalanceVerifier) getProof(ctx context.Context, address common.Address, keys []common.Hash, block string, root common.Hash) (*types.StateAccount, *proofResult, error) {
+	if keys == nil {
+		keys = []common.Hash{}
+	}
+	var result proofResult
+	if err := v.RPC.CallContext(ctx, &result, "eth_getProof", address, keys, block); err != nil {
+		return nil, nil, fmt.Errorf("eth_getProof failed: %v", err)
+	}
+	value, err := verifyProof(root, crypto.Keccak256(address.Bytes()), result.AccountProof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid account proof: %v", err)
+	}
+	if len(value) == 0 {
+		return nil, &result, nil
+	}
+	account, err := types.FullAccount(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid account data: %v", err)
+	}
+	return account, &result, nil
+}
+
+// verifyProof checks a Merkle-Patricia proof and returns the value stored at key,
+// or nil when the proof shows the key is absent
+func verifyProof(root common.Hash, key []byte, proof []hexutil.Bytes) ([]byte, error) {
+	nodes := memorydb.New()
+	for _, node := range proof {
+		if err := nodes.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	return trie.VerifyProof(root, key, nodes)
+}
+
+func accountBalance(account *types.StateAccount) *big.Int {
+	if account == nil {
+		return new(big.Int)
+	}
+	return account.Balance.ToBig()
+}
+
+// balanceSlotKey is the storage slot of balances[owner] for a Solidity mapping at slot
+func balanceSlotKey(owner common.Address, slot uint64) common.Hash {
+	return crypto.Keccak256Hash(
+		common.LeftPadBytes(owner.Bytes(), 32),
+		common.LeftPadBytes(new(big.Int).SetUint64(slot).Bytes(), 32),
+	)
+}
@@ -0,0 +1,308 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DecodeWarning identifies a risk found while decoding calldata
+type DecodeWarning string
+
+const (
+	WarnUnlimitedApproval DecodeWarning = "unlimited_approval"
+	WarnApprovalForAll    DecodeWarning = "approval_for_all"
+	WarnDelegateCall      DecodeWarning = "delegatecall"
+	WarnSelectorCollision DecodeWarning = "selector_collision"
+)
+
+type DecodedParam struct {
+	Name    string
+	Type    string
+	Value   string
+	Warning DecodeWarning // Empty when the value needs no attention
+}
+
+type DecodedCall struct {
+	Selector  string
+	Signature string
+	Source    string // ABI that matched: a bundled standard, a saved contract name or "4byte"
+	Params    []DecodedParam
+	Warnings  []DecodeWarning
+	Inner     *DecodedCall // Call executed by a Safe transaction
+	InnerErr  error
+}
+
+type tokenInfo struct {
+	symbol   string
+	decimals int
+	ok       bool
+}
+
+type bundledABI struct {
+	name string
+	abi  abi.ABI
+}
+
+// Bundled ABIs in lookup order. ERC-20 and ERC-721 share the approve and
+// transferFrom selectors; the decoder tells them apart by probing decimals().
+var bundledABIs = []bundledABI{
+	{"ERC-20", ERC20ABI},
+	{"ERC-721", ERC721ABI},
+	{"ERC-1155", ERC1155ABI},
+	{"Permit2", Permit2ABI},
+	{"Safe", SafeABI},
+	{"Disperse", DisperseABI},
+}
+
+// CalldataDecoder turns transaction calldata into a human readable call using
+// saved contract ABIs, the bundled standard ABIs and the local 4-byte database
+type CalldataDecoder struct {
+	Client    domain.ChainClient
+	Contracts domain.ContractRepository
+	Selectors domain.SelectorRepository
+	ChainID   int64
+
+	mu     sync.Mutex
+	tokens map[common.Address]tokenInfo
+}
+
+func NewCalldataDecoder(client domain.ChainClient, contracts domain.ContractRepository, selectors domain.SelectorRepository, chainID int64) *CalldataDecoder {
+	return &CalldataDecoder{
+		Client:    client,
+		Contracts: contracts,
+		Selectors: selectors,
+		ChainID:   chainID,
+		tokens:    map[common.Address]tokenInfo{},
+	}
+}
+
+// Decode finds the function called by data on contract to. An error is returned
+// when no known ABI or signature matches, in which case the data must be treated as opaque.
+func (d *CalldataDecoder) Decode(ctx context.Context, to *common.Address, data []byte) (*DecodedCall, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata is shorter than a function selector")
+	}
+	selector := data[:4]
+
+	var saved []domain.SavedContract
+	if d.Contracts != nil {
+		contracts, err := d.Contracts.GetContracts(d.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading saved contracts: %v", err)
+		}
+		saved = contracts
+	}
+
+	// The ABI loaded by the user for this exact contract is the most reliable source
+	if to != nil {
+		for _, contract := range saved {
+			if strings.EqualFold(contract.Address, to.Hex()) {
+				if call := d.decodeWithABI(ctx, to, contract.Name, contract.ABI, data); call != nil {
+					return call, nil
+				}
+			}
+		}
+	}
+
+	var fallback *DecodedCall
+	for _, bundled := range bundledABIs {
+		method, err := bundled.abi.MethodById(selector)
+		if err != nil {
+			continue
+		}
+		call, err := d.decodeMethod(ctx, to, bundled.name, *method, data)
+		if err != nil {
+			continue
+		}
+		if bundled.name == "ERC-20" && to != nil && !d.token(ctx, *to).ok {
+			if fallback == nil {
+				fallback = call
+			}
+			continue
+		}
+		return call, nil
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+
+	for _, contract := range saved {
+		if call := d.decodeWithABI(ctx, to, contract.Name, contract.ABI, data); call != nil {
+			return call, nil
+		}
+	}
+
+	if d.Selectors != nil {
+		signatures, err := d.Selectors.GetSignatures(hexutil.Encode(selector))
+		if err != nil {
+			return nil, fmt.Errorf("error reading the selector database: %v", err)
+		}
+		for _, signature := range signatures {
+			call, err := d.decodeSignature(ctx, to, signature, data)
+			if err != nil {
+				continue
+			}
+			if len(signatures) > 1 {
+				call.Warnings = append(call.Warnings, WarnSelectorCollision)
+			}
+			return call, nil
+		}
+	}
+	return nil, fmt.Errorf("no known ABI or signature matches selector %s", hexutil.Encode(selector))
+}
+
+func (d *CalldataDecoder) decodeWithABI(ctx context.Context, to *common.Address, source, abiJSON string, data []byte) *DecodedCall {
+	parsed, err := ParseABI(abiJSON)
+	if err != nil {
+		return nil
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return nil
+	}
+	call, err := d.decodeMethod(ctx, to, source, *method, data)
+	if err != nil {
+		return nil
+	}
+	return call
+}
+
+// decodeSignature decodes with a 4-byte database entry. Since such signatures carry no
+// names and may collide, the arguments must re-encode to exactly the same bytes.
+func (d *CalldataDecoder) decodeSignature(ctx context.Context, to *common.Address, signature string, data []byte) (*DecodedCall, error) {
+	name, args, err := ParseSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	values, err := args.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	repacked, err := args.Pack(values...)
+	if err != nil || !bytes.Equal(repacked, data[4:]) {
+		return nil, fmt.Errorf("calldata does not match %s", signature)
+	}
+	method := abi.NewMethod(name, name, abi.Function, "", false, false, args, nil)
+	return d.decodeMethod(ctx, to, "4byte", method, data)
+}
+
+func (d *CalldataDecoder) decodeMethod(ctx context.Context, to *common.Address, source string, method abi.Method, data []byte) (*DecodedCall, error) {
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	call := &DecodedCall{
+		Selector:  hexutil.Encode(data[:4]),
+		Signature: method.Sig,
+		Source:    source,
+	}
+
+	// Token amounts refer to the "token" argument when present (Permit2), otherwise to the callee
+	token := to
+	for i, input := range method.Inputs {
+		if address, ok := values[i].(common.Address); ok && strings.EqualFold(input.Name, "token") {
+			token = &address
+		}
+	}
+
+	approval := isApprovalMethod(method.RawName)
+	for i, input := range method.Inputs {
+		param := DecodedParam{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: FormatValue(values[i]),
+		}
+		switch value := values[i].(type) {
+		case *big.Int:
+			if input.Type.T == abi.UintTy && isAmountName(input.Name) && token != nil && source != "Safe" {
+				if info := d.token(ctx, *token); info.ok {
+					param.Value = fmt.Sprintf("%s %s (%s)", FormatUnits(value, info.decimals), info.symbol, value)
+				}
+			}
+			// Token ids, expirations and nonces may legitimately be maxed out; 4-byte entries have no names
+			if approval && (isAmountName(input.Name) || input.Name == "") && input.Type.Size >= 128 && isUnlimited(value, input.Type.Size) {
+				param.Warning = WarnUnlimitedApproval
+			}
+		case bool:
+			if method.RawName == "setApprovalForAll" && value {
+				param.Warning = WarnApprovalForAll
+			}
+		}
+		if param.Warning != "" {
+			call.Warnings = append(call.Warnings, param.Warning)
+		}
+		call.Params = append(call.Params, param)
+	}
+
+	if method.RawName == "execTransaction" && len(values) >= 4 {
+		d.decodeSafeCall(ctx, call, values)
+	}
+	return call, nil
+}
+
+// decodeSafeCall decodes the call a Safe will execute and flags delegate calls
+func (d *CalldataDecoder) decodeSafeCall(ctx context.Context, call *DecodedCall, values []interface{}) {
+	target, ok := values[0].(common.Address)
+	inner, _ := values[2].([]byte)
+	operation, _ := values[3].(uint8)
+	if !ok {
+		return
+	}
+	if operation == 1 {
+		call.Warnings = append(call.Warnings, WarnDelegateCall)
+	}
+	if len(inner) > 0 {
+		call.Inner, call.InnerErr = d.Decode(ctx, &target, inner)
+	}
+}
+
+// token reads and caches the ERC-20 symbol and decimals of a contract
+func (d *CalldataDecoder) token(ctx context.Context, contract common.Address) tokenInfo {
+	d.mu.Lock()
+	info, cached := d.tokens[contract]
+	d.mu.Unlock()
+	if cached {
+		return info
+	}
+
+	if out, err := callContract(ctx, d.Client, contract, ERC20ABI, "decimals"); err == nil {
+		if decimals, ok := out[0].(uint8); ok {
+			info = tokenInfo{decimals: int(decimals), ok: true}
+			if out, err := callContract(ctx, d.Client, contract, ERC20ABI, "symbol"); err == nil {
+				info.symbol, _ = out[0].(string)
+			}
+		}
+	}
+	d.mu.Lock()
+	d.tokens[contract] = info
+	d.mu.Unlock()
+	return info
+}
+
+func isApprovalMethod(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "approv") || strings.Contains(name, "allowance") || strings.HasPrefix(name, "permit")
+}
+
+func isAmountName(name string) bool {
+	switch strings.ToLower(strings.Trim(name, "_")) {
+	case "amount", "value", "wad", "amt", "rawamount", "addedvalue", "subtractedvalue":
+		return true
+	}
+	return false
+}
+
+// isUnlimited reports values in the top half of the type's range, which covers
+// the max-uint sentinels wallets and dapps use for "infinite" approvals
+func isUnlimited(value *big.Int, bits int) bool {
+	return bits > 0 && value.BitLen() >= bits
+}
@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ContractArtifact is an ABI loaded from disk, optionally with creation bytecode
+type ContractArtifact struct {
+	ABI      abi.ABI
+	ABIJSON  string
+	Bytecode []byte
+}
+
+// ContractService powers the contract workbench: read calls, write calldata,
+// deployments and the list of contracts saved for the active network
+type ContractService struct {
+	Client  domain.ChainClient
+	Repo    domain.ContractRepository
+	ChainID int64
+}
+
+func NewContractService(client domain.ChainClient, repo domain.ContractRepository, chainID int64) *ContractService {
+	return &ContractService{
+		Client:  client,
+		Repo:    repo,
+		ChainID: chainID,
+	}
+}
+
+// LoadArtifact reads a plain ABI array or a compiler artifact (Hardhat, Foundry or
+// solc --combined-json style) containing "abi" and "bytecode". When bytecodePath is
+// set, the bytecode is read from that file instead.
+func LoadArtifact(path, bytecodePath string) (*ContractArtifact, error) {
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	abiJSON := strings.TrimSpace(string(data))
+	var bytecode string
+	if strings.HasPrefix(abiJSON, "{") {
+		var artifact struct {
+			ABI      json.RawMessage `json:"abi"`
+			Bytecode json.RawMessage `json:"bytecode"`
+		}
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %v", path, err)
+		}
+		if len(artifact.ABI) == 0 {
+			return nil, fmt.Errorf("%s has no \"abi\" field", path)
+		}
+		abiJSON = string(artifact.ABI)
+		bytecode = artifactBytecode(artifact.Bytecode)
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI in %s: %v", path, err)
+	}
+
+	if bytecodePath != "" {
+		raw, err := os.ReadFile(expandHome(bytecodePath))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", bytecodePath, err)
+		}
+		bytecode = strings.TrimSpace(string(raw))
+	}
+	artifact := &ContractArtifact{ABI: parsed, ABIJSON: abiJSON}
+	if bytecode != "" {
+		if !strings.HasPrefix(bytecode, "0x") {
+			bytecode = "0x" + bytecode
+		}
+		artifact.Bytecode, err = hexutil.Decode(bytecode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytecode: %v", err)
+		}
+	}
+	return artifact, nil
+}
+
+// artifactBytecode accepts "0x…" as well as Foundry's {"object": "0x…"}
+func artifactBytecode(raw json.RawMessage) string {
+	var bytecode string
+	if json.Unmarshal(raw, &bytecode) == nil {
+		return bytecode
+	}
+	var object struct {
+		Object string `json:"object"`
+	}
+	if json.Unmarshal(raw, &object) == nil {
+		return object.Object
+	}
+	return ""
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// ParseABI parses an ABI previously stored with a saved contract
+func ParseABI(abiJSON string) (abi.ABI, error) {
+	return abi.JSON(strings.NewReader(abiJSON))
+}
+
+// Functions lists the ABI methods sorted by name, then by signature for overloads
+func Functions(contractABI abi.ABI) []abi.Method {
+	methods := make([]abi.Method, 0, len(contractABI.Methods))
+	for _, method := range contractABI.Methods {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if methods[i].RawName != methods[j].RawName {
+			return methods[i].RawName < methods[j].RawName
+		}
+		return methods[i].Sig < methods[j].Sig
+	})
+	return methods
+}
+
+// IsReadOnly reports whether a method can be executed with eth_call
+func IsReadOnly(method abi.Method) bool {
+	return method.IsConstant()
+}
+
+// Call executes a view or pure method and unpacks its outputs
+func (cs *ContractService) Call(ctx context.Context, from, contract common.Address, contractABI abi.ABI, method abi.Method, args []interface{}) ([]interface{}, error) {
+	data, err := cs.Calldata(contractABI, method, args)
+	if err != nil {
+		return nil, err
+	}
+	output, err := cs.Client.CallContract(ctx, ethereum.CallMsg{From: from, To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 && len(method.Outputs) > 0 {
+		return nil, fmt.Errorf("%s: empty response from %s", method.RawName, contract.Hex())
+	}
+	return method.Outputs.Unpack(output)
+}
+
+// Calldata encodes a method call, selecting overloads by their exact signature
+func (cs *ContractService) Calldata(contractABI abi.ABI, method abi.Method, args []interface{}) ([]byte, error) {
+	packed, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding %s: %v", method.Sig, err)
+	}
+	return append(append([]byte{}, method.ID...), packed...), nil
+}
+
+// DeployData appends the encoded constructor arguments to the creation bytecode
+func (cs *ContractService) DeployData(artifact *ContractArtifact, args []interface{}) ([]byte, error) {
+	if len(artifact.Bytecode) == 0 {
+		return nil, fmt.Errorf("no bytecode available for deployment")
+	}
+	packed, err := artifact.ABI.Pack("", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding constructor arguments: %v", err)
+	}
+	return append(append([]byte{}, artifact.Bytecode...), packed...), nil
+}
+
+func (cs *ContractService) SavedContracts() ([]domain.SavedContract, error) {
+	return cs.Repo.GetContracts(cs.ChainID)
+}
+
+// SaveContract remembers a contract for the active network
+func (cs *ContractService) SaveContract(name, address, abiJSON string) (*domain.SavedContract, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid contract address: %s", address)
+	}
+	if name == "" {
+		name = common.HexToAddress(address).Hex()
+	}
+	contract := &domain.SavedContract{
+		ChainID: cs.ChainID,
+		Name:    name,
+		Address: common.HexToAddress(address).Hex(),
+		ABI:     abiJSON,
+	}
+	if err := cs.Repo.SaveContract(contract); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+func (cs *ContractService) DeleteContract(id int) error {
+	return cs.Repo.DeleteContract(id)
+}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultHistoryPageSize = 25
+	DefaultHistoryCacheTTL = 5 * time.Minute
+)
+
+type HistoryPage struct {
+	Address   string
+	Kind      domain.HistoryKind
+	Page      int
+	Entries   []domain.HistoryEntry
+	HasMore   bool
+	FetchedAt time.Time
+	Stale     bool // True when the provider failed and the cached copy was returned instead
+}
+
+type HistoryService struct {
+	Provider domain.HistoryProvider
+	Cache    domain.HistoryRepository
+	ChainID  int64
+	PageSize int
+	CacheTTL time.Duration
+}
+
+func NewHistoryService(provider domain.HistoryProvider, cache domain.HistoryRepository, chainID int64) *HistoryService {
+	return &HistoryService{
+		Provider: provider,
+		Cache:    cache,
+		ChainID:  chainID,
+		PageSize: DefaultHistoryPageSize,
+		CacheTTL: DefaultHistoryCacheTTL,
+	}
+}
+
+// GetHistory returns one page of the wallet history, served from the local cache
+// while it is fresh. When refresh is true the provider is always queried.
+func (hs *HistoryService) GetHistory(ctx context.Context, address string, kind domain.HistoryKind, page int, refresh bool) (*HistoryPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	address = strings.ToLower(address)
+
+	cached, fetchedAt, err := hs.Cache.GetHistoryPage(hs.ChainID, address, kind, page, hs.PageSize)
+	if err != nil {
+		log.Printf("Error reading the history cache: %v\n", err)
+	}
+	if !refresh && !fetchedAt.IsZero() && time.Since(fetchedAt) < hs.CacheTTL {
+		return hs.newPage(address, kind, page, cached, fetchedAt, false), nil
+	}
+
+	entries, err := hs.Provider.FetchHistory(ctx, address, kind, page, hs.PageSize)
+	if err != nil {
+		// Serve the last known copy when the explorer is unreachable
+		if !fetchedAt.IsZero() {
+			log.Printf("Error fetching history, using cached page: %v\n", err)
+			return hs.newPage(address, kind, page, cached, fetchedAt, true), nil
+		}
+		return nil, err
+	}
+
+	if err := hs.Cache.SaveHistoryPage(hs.ChainID, address, kind, page, hs.PageSize, entries); err != nil {
+		log.Printf("Error saving the history cache: %v\n", err)
+	}
+	return hs.newPage(address, kind, page, entries, time.Now(), false), nil
+}
+
+func (hs *HistoryService) newPage(address string, kind domain.HistoryKind, page int, entries []domain.HistoryEntry, fetchedAt time.Time, stale bool) *HistoryPage {
+	return &HistoryPage{
+		Address:   address,
+		Kind:      kind,
+		Page:      page,
+		Entries:   entries,
+		HasMore:   len(entries) >= hs.PageSize,
+		FetchedAt: fetchedAt,
+		Stale:     stale,
+	}
+}
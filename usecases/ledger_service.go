@@ -0,0 +1,180 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+)
+
+type LedgerService struct {
+	Repo      domain.LedgerRepository
+	ExportDir string
+}
+
+func NewLedgerService(repo domain.LedgerRepository, exportDir string) *LedgerService {
+	return &LedgerService{
+		Repo:      repo,
+		ExportDir: exportDir,
+	}
+}
+
+// RecordSigned stores a freshly signed transaction. The fee is the maximum the
+// sender can be charged and is replaced by the actual fee once a receipt is known.
+func (ls *LedgerService) RecordSigned(tx *types.Transaction, from string, memo string) (*domain.LedgerEntry, error) {
+	to := ""
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	maxFee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+
+	entry := &domain.LedgerEntry{
+		Hash:      tx.Hash().Hex(),
+		ChainID:   tx.ChainId().Int64(),
+		From:      from,
+		To:        to,
+		Value:     tx.Value().String(),
+		Fee:       maxFee.String(),
+		Timestamp: time.Now(),
+		Memo:      memo,
+		Status:    domain.LedgerSigned,
+	}
+	if err := ls.Repo.AddLedgerEntry(entry); err != nil {
+		return nil, fmt.Errorf("error recording the transaction in the ledger: %v", err)
+	}
+	return entry, nil
+}
+
+func (ls *LedgerService) MarkBroadcast(hash string) error {
+	return ls.Repo.UpdateLedgerStatus(hash, domain.LedgerBroadcast, "")
+}
+
+// MarkReceipt records the final status and the fee effectively paid
+func (ls *LedgerService) MarkReceipt(receipt *types.Receipt) error {
+	status := domain.LedgerConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = domain.LedgerFailed
+	}
+	fee := ""
+	if receipt.EffectiveGasPrice != nil {
+		fee = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice).String()
+	}
+	return ls.Repo.UpdateLedgerStatus(receipt.TxHash.Hex(), status, fee)
+}
+
+func (ls *LedgerService) GetEntries(filter domain.LedgerFilter) ([]domain.LedgerEntry, error) {
+	return ls.Repo.GetLedgerEntries(filter)
+}
+
+// Export writes the filtered ledger to a timestamped file in ExportDir and returns its path
+func (ls *LedgerService) Export(filter domain.LedgerFilter, format ExportFormat) (string, error) {
+	entries, err := ls.Repo.GetLedgerEntries(filter)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(ls.ExportDir, 0700); err != nil {
+		return "", fmt.Errorf("error creating the export directory: %v", err)
+	}
+	path := filepath.Join(ls.ExportDir, fmt.Sprintf("ledger-%s.%s", time.Now().Format("20060102-150405"), format))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", fmt.Errorf("error creating the export file: %v", err)
+	}
+	defer file.Close()
+
+	switch format {
+	case ExportCSV:
+		err = WriteLedgerCSV(file, entries)
+	case ExportJSON:
+		err = WriteLedgerJSON(file, entries)
+	default:
+		err = fmt.Errorf("unsupported export format: %s", format)
+	}
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+var ledgerCSVHeader = []string{
+	"timestamp", "chain_id", "hash", "from", "to", "value_wei", "value", "fee_wei", "fee", "memo", "status",
+}
+
+func WriteLedgerCSV(w io.Writer, entries []domain.LedgerEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ledgerCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			e.Timestamp.UTC().Format(time.RFC3339),
+			strconv.FormatInt(e.ChainID, 10),
+			e.Hash,
+			e.From,
+			e.To,
+			e.Value,
+			FormatUnitsString(e.Value, 18),
+			e.Fee,
+			FormatUnitsString(e.Fee, 18),
+			e.Memo,
+			string(e.Status),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type ledgerJSONEntry struct {
+	Timestamp string `json:"timestamp"`
+	ChainID   int64  `json:"chain_id"`
+	Hash      string `json:"hash"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	ValueWei  string `json:"value_wei"`
+	Value     string `json:"value"`
+	FeeWei    string `json:"fee_wei"`
+	Fee       string `json:"fee"`
+	Memo      string `json:"memo"`
+	Status    string `json:"status"`
+}
+
+func WriteLedgerJSON(w io.Writer, entries []domain.LedgerEntry) error {
+	records := make([]ledgerJSONEntry, 0, len(entries))
+	for _, e := range entries {
+		records = append(records, ledgerJSONEntry{
+			Timestamp: e.Timestamp.UTC().Format(time.RFC3339),
+			ChainID:   e.ChainID,
+			Hash:      e.Hash,
+			From:      e.From,
+			To:        e.To,
+			ValueWei:  e.Value,
+			Value:     FormatUnitsString(e.Value, 18),
+			FeeWei:    e.Fee,
+			Fee:       FormatUnitsString(e.Fee, 18),
+			Memo:      e.Memo,
+			Status:    string(e.Status),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
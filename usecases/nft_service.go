@@ -0,0 +1,273 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ERC-165 identifier of the ERC-721 Enumerable extension
+var erc721EnumerableInterfaceID = [4]byte{0x78, 0x0e, 0x9d, 0x63}
+
+// NFTService lists ERC-721 and ERC-1155 tokens held by a wallet. Configured
+// collections are queried directly; without them collections are discovered
+// from the transfer logs that credit the wallet.
+type NFTService struct {
+	Client      domain.ChainClient
+	Collections []common.Address
+	FromBlock   uint64
+	BlockRange  uint64 // Maximum blocks per eth_getLogs request, 0 for a single request
+}
+
+func NewNFTService(client domain.ChainClient, collections []string) *NFTService {
+	service := &NFTService{Client: client}
+	for _, collection := range collections {
+		if common.IsHexAddress(collection) {
+			service.Collections = append(service.Collections, common.HexToAddress(collection))
+		} else {
+			log.Printf("Ignoring invalid NFT collection address: %s\n", collection)
+		}
+	}
+	return service
+}
+
+type nftCandidate struct {
+	contract common.Address
+	standard domain.NFTStandard
+	tokenID  *big.Int
+}
+
+func (c nftCandidate) key() string {
+	return c.contract.Hex() + "/" + c.tokenID.String()
+}
+
+// Inventory returns the tokens currently held by owner
+func (ns *NFTService) Inventory(ctx context.Context, owner common.Address) ([]domain.NFT, error) {
+	candidates := map[string]nftCandidate{}
+	var logCollections []common.Address
+
+	for _, collection := range ns.Collections {
+		enumerated, err := ns.enumerate(ctx, collection, owner, candidates)
+		if err != nil {
+			log.Printf("Error enumerating %s, falling back to logs: %v\n", collection.Hex(), err)
+		}
+		if !enumerated {
+			logCollections = append(logCollections, collection)
+		}
+	}
+
+	if len(ns.Collections) == 0 || len(logCollections) > 0 {
+		if err := ns.scanLogs(ctx, owner, logCollections, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	var nfts []domain.NFT
+	for _, candidate := range candidates {
+		nft, held, err := ns.resolve(ctx, owner, candidate)
+		if err != nil {
+			log.Printf("Error reading %s: %v\n", candidate.key(), err)
+			continue
+		}
+		if held {
+			nfts = append(nfts, nft)
+		}
+	}
+
+	sort.Slice(nfts, func(i, j int) bool {
+		if nfts[i].Contract != nfts[j].Contract {
+			return nfts[i].Contract < nfts[j].Contract
+		}
+		a, _ := new(big.Int).SetString(nfts[i].TokenID, 10)
+		b, _ := new(big.Int).SetString(nfts[j].TokenID, 10)
+		return a.Cmp(b) < 0
+	})
+	return nfts, nil
+}
+
+// enumerate lists the tokens of an ERC-721 Enumerable collection without touching logs
+func (ns *NFTService) enumerate(ctx context.Context, collection, owner common.Address, candidates map[string]nftCandidate) (bool, error) {
+	if !ns.supportsInterface(ctx, collection, erc721EnumerableInterfaceID) {
+		return false, nil
+	}
+	out, err := callContract(ctx, ns.Client, collection, ERC721ABI, "balanceOf", owner)
+	if err != nil {
+		return false, err
+	}
+	balance := out[0].(*big.Int)
+	for i := int64(0); i < balance.Int64(); i++ {
+		out, err := callContract(ctx, ns.Client, collection, ERC721ABI, "tokenOfOwnerByIndex", owner, big.NewInt(i))
+		if err != nil {
+			return false, err
+		}
+		candidate := nftCandidate{contract: collection, standard: domain.ERC721, tokenID: out[0].(*big.Int)}
+		candidates[candidate.key()] = candidate
+	}
+	return true, nil
+}
+
+// scanLogs collects every token ever transferred to owner. Restricting the query
+// to the given collections is optional; an empty list searches all contracts.
+func (ns *NFTService) scanLogs(ctx context.Context, owner common.Address, collections []common.Address, candidates map[string]nftCandidate) error {
+	head, err := ns.Client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading the latest block: %v", err)
+	}
+	ownerTopic := common.BytesToHash(owner.Bytes())
+	transfer := ERC721ABI.Events["Transfer"].ID
+	single := ERC1155ABI.Events["TransferSingle"].ID
+	batch := ERC1155ABI.Events["TransferBatch"].ID
+
+	queries := []ethereum.FilterQuery{
+		{Addresses: collections, Topics: [][]common.Hash{{transfer}, nil, {ownerTopic}}},
+		{Addresses: collections, Topics: [][]common.Hash{{single, batch}, nil, nil, {ownerTopic}}},
+	}
+
+	for _, query := range queries {
+		logs, err := FilterLogsInRange(ctx, ns.Client, query, ns.FromBlock, head, ns.BlockRange)
+		if err != nil {
+			return err
+		}
+		for _, entry := range logs {
+			for _, candidate := range nftCandidatesFromLog(entry) {
+				candidates[candidate.key()] = candidate
+			}
+		}
+	}
+	return nil
+}
+
+func nftCandidatesFromLog(entry types.Log) []nftCandidate {
+	switch entry.Topics[0] {
+	case ERC721ABI.Events["Transfer"].ID:
+		// ERC-20 shares the Transfer signature but keeps the amount in data
+		if len(entry.Topics) != 4 {
+			return nil
+		}
+		return []nftCandidate{{contract: entry.Address, standard: domain.ERC721, tokenID: entry.Topics[3].Big()}}
+	case ERC1155ABI.Events["TransferSingle"].ID:
+		values, err := ERC1155ABI.Events["TransferSingle"].Inputs.NonIndexed().Unpack(entry.Data)
+		if err != nil {
+			return nil
+		}
+		return []nftCandidate{{contract: entry.Address, standard: domain.ERC1155, tokenID: values[0].(*big.Int)}}
+	case ERC1155ABI.Events["TransferBatch"].ID:
+		values, err := ERC1155ABI.Events["TransferBatch"].Inputs.NonIndexed().Unpack(entry.Data)
+		if err != nil {
+			return nil
+		}
+		var candidates []nftCandidate
+		for _, id := range values[0].([]*big.Int) {
+			candidates = append(candidates, nftCandidate{contract: entry.Address, standard: domain.ERC1155, tokenID: id})
+		}
+		return candidates
+	}
+	return nil
+}
+
+// resolve checks the current ownership of a candidate and reads its metadata URI
+func (ns *NFTService) resolve(ctx context.Context, owner common.Address, candidate nftCandidate) (domain.NFT, bool, error) {
+	nft := domain.NFT{
+		Contract: candidate.contract.Hex(),
+		Standard: candidate.standard,
+		TokenID:  candidate.tokenID.String(),
+	}
+
+	if candidate.standard == domain.ERC721 {
+		out, err := callContract(ctx, ns.Client, candidate.contract, ERC721ABI, "ownerOf", candidate.tokenID)
+		if err != nil {
+			return nft, false, err
+		}
+		if out[0].(common.Address) != owner {
+			return nft, false, nil
+		}
+		nft.Balance = "1"
+		if out, err := callContract(ctx, ns.Client, candidate.contract, ERC721ABI, "tokenURI", candidate.tokenID); err == nil {
+			nft.MetadataURI = out[0].(string)
+		}
+		if out, err := callContract(ctx, ns.Client, candidate.contract, ERC721ABI, "name"); err == nil {
+			nft.Collection = out[0].(string)
+		}
+		return nft, true, nil
+	}
+
+	out, err := callContract(ctx, ns.Client, candidate.contract, ERC1155ABI, "balanceOf", owner, candidate.tokenID)
+	if err != nil {
+		return nft, false, err
+	}
+	balance := out[0].(*big.Int)
+	if balance.Sign() == 0 {
+		return nft, false, nil
+	}
+	nft.Balance = balance.String()
+	if out, err := callContract(ctx, ns.Client, candidate.contract, ERC1155ABI, "uri", candidate.tokenID); err == nil {
+		// ERC-1155 clients must replace {id} with the zero padded hex token id
+		nft.MetadataURI = strings.ReplaceAll(out[0].(string), "{id}", fmt.Sprintf("%064x", candidate.tokenID))
+	}
+	return nft, true, nil
+}
+
+func (ns *NFTService) supportsInterface(ctx context.Context, contract common.Address, interfaceID [4]byte) bool {
+	out, err := callContract(ctx, ns.Client, contract, ERC721ABI, "supportsInterface", interfaceID)
+	if err != nil {
+		return false
+	}
+	return out[0].(bool)
+}
+
+// TransferCalldata encodes safeTransferFrom for the given token. For ERC-1155 the
+// whole balance held by the wallet is transferred unless amount is set.
+func (ns *NFTService) TransferCalldata(nft domain.NFT, from, to common.Address, amount *big.Int) ([]byte, error) {
+	tokenID, ok := new(big.Int).SetString(nft.TokenID, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token id: %s", nft.TokenID)
+	}
+	if nft.Standard == domain.ERC721 {
+		return ERC721ABI.Pack("safeTransferFrom", from, to, tokenID)
+	}
+	if amount == nil {
+		amount, ok = new(big.Int).SetString(nft.Balance, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid balance: %s", nft.Balance)
+		}
+	}
+	return ERC1155ABI.Pack("safeTransferFrom", from, to, tokenID, amount, []byte{})
+}
+
+// FilterLogsInRange runs eth_getLogs between from and to, splitting the range in
+// chunks of blockRange blocks when the node limits the size of a single query.
+func FilterLogsInRange(ctx context.Context, client domain.ChainClient, query ethereum.FilterQuery, from, to, blockRange uint64) ([]types.Log, error) {
+	if blockRange == 0 {
+		query.FromBlock = new(big.Int).SetUint64(from)
+		query.ToBlock = new(big.Int).SetUint64(to)
+		logs, err := client.FilterLogs(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("error querying logs: %v", err)
+		}
+		return logs, nil
+	}
+
+	var logs []types.Log
+	for start := from; start <= to; start += blockRange {
+		end := start + blockRange - 1
+		if end > to {
+			end = to
+		}
+		query.FromBlock = new(big.Int).SetUint64(start)
+		query.ToBlock = new(big.Int).SetUint64(end)
+		chunk, err := client.FilterLogs(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("error querying logs between blocks %d and %d: %v", start, end, err)
+		}
+		logs = append(logs, chunk...)
+	}
+	return logs, nil
+}
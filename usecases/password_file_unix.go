@@ -0,0 +1,20 @@
+//go:build !windows
+
+package usecases
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkSecretFile rejects password files that other users could read or write
+func checkSecretFile(path string, info os.FileInfo) error {
+	if info.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("password file %s must not be accessible by group or others (mode %04o)", path, info.Mode().Perm())
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("password file %s is not owned by the current user", path)
+	}
+	return nil
+}
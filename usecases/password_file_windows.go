@@ -0,0 +1,11 @@
+//go:build windows
+
+package usecases
+
+import "os"
+
+// checkSecretFile relies on the file's ACL on Windows, where permission bits
+// are not meaningful
+func checkSecretFile(path string, info os.FileInfo) error {
+	return nil
+}
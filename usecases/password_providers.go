@@ -0,0 +1,261 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoPassword is returned when a provider has no password for a wallet, so the
+// chain moves on to the next one
+var ErrNoPassword = errors.New("no password available")
+
+// AddressPlaceholder is replaced with the lowercase wallet address in provider
+// settings, so one global provider can serve every wallet
+const AddressPlaceholder = "{address}"
+
+// PasswordProvider returns the password of a wallet without prompting
+type PasswordProvider interface {
+	Name() string
+	Password(ctx context.Context, wallet *domain.Wallet) (string, error)
+}
+
+// PasswordChain asks the providers configured for a wallet first and then the
+// global ones, in order
+type PasswordChain struct {
+	Global    []PasswordProvider
+	PerWallet map[string][]PasswordProvider // By lowercase address
+}
+
+func NewPasswordChain(global []PasswordProvider, perWallet map[string][]PasswordProvider) *PasswordChain {
+	chain := &PasswordChain{
+		Global:    global,
+		PerWallet: map[string][]PasswordProvider{},
+	}
+	for address, providers := range perWallet {
+		chain.PerWallet[strings.ToLower(address)] = providers
+	}
+	return chain
+}
+
+// Providers lists the providers consulted for wallet
+func (c *PasswordChain) Providers(wallet *domain.Wallet) []PasswordProvider {
+	providers := append([]PasswordProvider{}, c.PerWallet[strings.ToLower(wallet.Address)]...)
+	return append(providers, c.Global...)
+}
+
+// UnlockNonInteractive loads wallet with the passwords from the provider chain,
+// trying each provider until one opens the key. Backends that do not encrypt
+// keys with the wallet password are unlocked directly.
+func (ws *WalletService) UnlockNonInteractive(ctx context.Context, wallet *domain.Wallet) (*WalletDetails, error) {
+	backend, err := ws.backend(wallet)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := backend.(domain.KeyExporter); !ok {
+		return ws.LoadWallet(wallet, "")
+	}
+	if ws.Passwords == nil {
+		return nil, fmt.Errorf("no password providers configured")
+	}
+	if err := ws.checkLockout(wallet, "provider"); err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	for _, provider := range ws.Passwords.Providers(wallet) {
+		password, err := provider.Password(ctx, wallet)
+		if errors.Is(err, ErrNoPassword) {
+			continue
+		}
+		if err == nil {
+			var details *WalletDetails
+			if details, err = ws.LoadWallet(wallet, password); err == nil {
+				return details, nil
+			}
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", provider.Name(), err))
+	}
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("cannot unlock %s: %s", wallet.Address, strings.Join(failures, "; "))
+	}
+	return nil, fmt.Errorf("cannot unlock %s: %v", wallet.Address, ErrNoPassword)
+}
+
+// UnlockGroupNonInteractive is UnlockGroup with the provider chain instead of a
+// shared password
+func (ws *WalletService) UnlockGroupNonInteractive(ctx context.Context, wallets []domain.Wallet) map[int]*WalletDetails {
+	unlocked := make(map[int]*WalletDetails)
+	for i := range wallets {
+		details, err := ws.UnlockNonInteractive(ctx, &wallets[i])
+		if err == nil {
+			unlocked[i] = details
+		}
+	}
+	return unlocked
+}
+
+// UnlockNonInteractive opens the vault with the first provider password that
+// matches the master password, for runs that cannot prompt; {address} expands
+// to nothing
+func (vs *VaultService) UnlockNonInteractive(ctx context.Context, providers []PasswordProvider) error {
+	var failures []string
+	for _, provider := range providers {
+		password, err := provider.Password(ctx, &domain.Wallet{})
+		if errors.Is(err, ErrNoPassword) {
+			continue
+		}
+		if err == nil {
+			if err = vs.Unlock(password); err == nil {
+				return nil
+			}
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", provider.Name(), err))
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("cannot unlock the vault: %s", strings.Join(failures, "; "))
+	}
+	return fmt.Errorf("cannot unlock the vault: %v", ErrNoPassword)
+}
+
+// EnvPasswordProvider reads the password from an environment variable
+type EnvPasswordProvider struct {
+	Variable string
+}
+
+func (p *EnvPasswordProvider) Name() string {
+	return "env"
+}
+
+func (p *EnvPasswordProvider) Password(ctx context.Context, wallet *domain.Wallet) (string, error) {
+	password, ok := os.LookupEnv(expandAddress(p.Variable, wallet))
+	if !ok || password == "" {
+		return "", ErrNoPassword
+	}
+	return password, nil
+}
+
+// FilePasswordProvider reads the password from a file that must be a regular
+// file owned by the current user and unreadable by anyone else
+type FilePasswordProvider struct {
+	Path string
+}
+
+func (p *FilePasswordProvider) Name() string {
+	return "file"
+}
+
+func (p *FilePasswordProvider) Password(ctx context.Context, wallet *domain.Wallet) (string, error) {
+	path := expandAddress(p.Path, wallet)
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return "", ErrNoPassword
+	}
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("password file %s is not a regular file", path)
+	}
+	if err := checkSecretFile(path, info); err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return firstLine(content)
+}
+
+// PassPasswordProvider reads the first line of an entry in the pass or gopass
+// password store
+type PassPasswordProvider struct {
+	Program string // "pass" or "gopass"
+	Entry   string
+}
+
+func (p *PassPasswordProvider) Name() string {
+	return p.Program
+}
+
+func (p *PassPasswordProvider) Password(ctx context.Context, wallet *domain.Wallet) (string, error) {
+	args := []string{"show", expandAddress(p.Entry, wallet)}
+	if filepath.Base(p.Program) == "gopass" {
+		// Only the password line, without the entry's other fields
+		args = []string{"show", "--password", expandAddress(p.Entry, wallet)}
+	}
+	output, err := runPasswordCommand(ctx, p.Program, args, wallet)
+	if err != nil {
+		return "", err
+	}
+	return firstLine(output)
+}
+
+// CommandPasswordProvider runs a command, without a shell, and uses its standard
+// output as the password. The wallet address is also passed in the
+// BLOCO_WALLET_ADDRESS environment variable.
+type CommandPasswordProvider struct {
+	Command []string
+}
+
+func (p *CommandPasswordProvider) Name() string {
+	return "command"
+}
+
+func (p *CommandPasswordProvider) Password(ctx context.Context, wallet *domain.Wallet) (string, error) {
+	if len(p.Command) == 0 {
+		return "", fmt.Errorf("password command is empty")
+	}
+	args := make([]string, len(p.Command)-1)
+	for i, arg := range p.Command[1:] {
+		args[i] = expandAddress(arg, wallet)
+	}
+	output, err := runPasswordCommand(ctx, p.Command[0], args, wallet)
+	if err != nil {
+		return "", err
+	}
+	return firstLine(output)
+}
+
+func runPasswordCommand(ctx context.Context, program string, args []string, wallet *domain.Wallet) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Env = append(os.Environ(), "BLOCO_WALLET_ADDRESS="+wallet.Address)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s failed: %v: %s", filepath.Base(program), err, message)
+		}
+		return nil, fmt.Errorf("%s failed: %v", filepath.Base(program), err)
+	}
+	return output, nil
+}
+
+// firstLine returns the password without its line ending; an empty password
+// counts as missing
+func firstLine(content []byte) (string, error) {
+	line, _, _ := strings.Cut(string(content), "\n")
+	line = strings.TrimRight(line, "\r")
+	if line == "" {
+		return "", ErrNoPassword
+	}
+	return line, nil
+}
+
+func expandAddress(template string, wallet *domain.Wallet) string {
+	return strings.ReplaceAll(template, AddressPlaceholder, strings.ToLower(wallet.Address))
+}
@@ -0,0 +1,531 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Payment status values written to the results file
+const (
+	PaymentPending = "pending"
+	PaymentSent    = "sent"
+	PaymentFailed  = "failed"
+)
+
+// Gas limit used for disperseToken when it follows an approval in the same batch,
+// since it cannot be estimated until the approval is mined
+const (
+	disperseGasBase         = 60000
+	disperseGasPerRecipient = 40000
+)
+
+// Payment is one line of a batch payment file
+type Payment struct {
+	Line       int
+	Recipient  common.Address
+	AmountText string
+	Amount     *big.Int
+	Asset      domain.Asset
+	Hash       string
+	Status     string
+	Error      string
+}
+
+// AssetTotal is the amount owed in one asset compared with the payer's balance
+type AssetTotal struct {
+	Asset   domain.Asset
+	Total   *big.Int
+	Balance *big.Int
+}
+
+func (t AssetTotal) Short() bool {
+	return t.Balance.Cmp(t.Total) < 0
+}
+
+// NativeTotal is the amount of the native coin owed, zero when only tokens are paid
+func NativeTotal(totals []AssetTotal) *big.Int {
+	for _, total := range totals {
+		if total.Asset.Contract == "" {
+			return total.Total
+		}
+	}
+	return new(big.Int)
+}
+
+// SignedTotal is the amount of the payments checked against the two-factor sign
+// threshold: the native total, or nil when the batch also pays tokens
+func SignedTotal(totals []AssetTotal) *big.Int {
+	for _, total := range totals {
+		if total.Asset.Contract != "" && total.Total.Sign() > 0 {
+			return nil
+		}
+	}
+	return NativeTotal(totals)
+}
+
+// PaymentBatch holds the transactions that settle a set of payments in nonce order
+type PaymentBatch struct {
+	Txs      []*types.Transaction
+	Payments [][]int // Payment indexes settled by each transaction; empty for approvals
+	Fees     *big.Int
+}
+
+// PaymentService pays many recipients from one wallet, either with one transfer
+// per line or through a Disperse contract when one is configured for the network
+type PaymentService struct {
+	Transactions *TransactionService
+	Assets       []domain.Asset
+	Disperse     *common.Address
+}
+
+func NewPaymentService(transactions *TransactionService, assets []domain.Asset, disperse string) *PaymentService {
+	service := &PaymentService{
+		Transactions: transactions,
+		Assets:       assets,
+	}
+	if common.IsHexAddress(disperse) {
+		address := common.HexToAddress(disperse)
+		service.Disperse = &address
+	}
+	return service
+}
+
+// ResultsPath returns the results file written next to a payment file
+func ResultsPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "-results.csv"
+}
+
+// LoadPayments parses a recipient,amount,token file. The token column holds a
+// configured symbol or a token contract address; an empty token is the native coin.
+// Lines already marked as sent in a previous results file are kept as sent so a
+// rerun only pays the remaining lines.
+func (ps *PaymentService) LoadPayments(ctx context.Context, path string) ([]Payment, error) {
+	path = expandHome(path)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening the payment file: %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var payments []Payment
+	var problems []string
+	tokens := map[string]domain.Asset{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading the payment file: %v", err)
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "recipient") {
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		payment, err := ps.parsePayment(ctx, line, record, tokens)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		payments = append(payments, payment)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("the payment file has no payments")
+	}
+
+	if err := applyPreviousResults(ResultsPath(path), payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (ps *PaymentService) parsePayment(ctx context.Context, line int, record []string, tokens map[string]domain.Asset) (Payment, error) {
+	if len(record) < 2 || len(record) > 3 {
+		return Payment{}, fmt.Errorf("expected recipient,amount,token")
+	}
+	recipient := strings.TrimSpace(record[0])
+	if !common.IsHexAddress(recipient) {
+		return Payment{}, fmt.Errorf("invalid recipient %q", recipient)
+	}
+	token := ""
+	if len(record) == 3 {
+		token = strings.TrimSpace(record[2])
+	}
+	asset, err := ps.resolveAsset(ctx, token, tokens)
+	if err != nil {
+		return Payment{}, err
+	}
+	amountText := strings.TrimSpace(record[1])
+	amount, err := ParseUnits(amountText, asset.Decimals)
+	if err != nil {
+		return Payment{}, err
+	}
+	if amount.Sign() <= 0 {
+		return Payment{}, fmt.Errorf("amount must be positive")
+	}
+	return Payment{
+		Line:       line,
+		Recipient:  common.HexToAddress(recipient),
+		AmountText: amountText,
+		Amount:     amount,
+		Asset:      asset,
+		Status:     PaymentPending,
+	}, nil
+}
+
+// resolveAsset maps the token column to a configured asset, or reads the
+// symbol and decimals of an unlisted token contract
+func (ps *PaymentService) resolveAsset(ctx context.Context, token string, cache map[string]domain.Asset) (domain.Asset, error) {
+	for _, asset := range ps.Assets {
+		if (token == "" && asset.Contract == "") || (token != "" && (strings.EqualFold(asset.Symbol, token) || strings.EqualFold(asset.Contract, token))) {
+			return asset, nil
+		}
+	}
+	if !common.IsHexAddress(token) {
+		return domain.Asset{}, fmt.Errorf("unknown token %q", token)
+	}
+	key := strings.ToLower(token)
+	if asset, ok := cache[key]; ok {
+		return asset, nil
+	}
+	contract := common.HexToAddress(token)
+	out, err := callContract(ctx, ps.Transactions.Client, contract, ERC20ABI, "decimals")
+	if err != nil {
+		return domain.Asset{}, fmt.Errorf("%s is not an ERC-20 token: %v", token, err)
+	}
+	asset := domain.Asset{Symbol: shortSymbol(contract), Contract: contract.Hex(), Decimals: int(out[0].(uint8))}
+	if out, err := callContract(ctx, ps.Transactions.Client, contract, ERC20ABI, "symbol"); err == nil {
+		if symbol, ok := out[0].(string); ok && symbol != "" {
+			asset.Symbol = symbol
+		}
+	}
+	cache[key] = asset
+	return asset, nil
+}
+
+func shortSymbol(contract common.Address) string {
+	hex := contract.Hex()
+	return hex[:6] + "…" + hex[len(hex)-4:]
+}
+
+// applyPreviousResults marks as sent the lines a previous run already paid.
+// A line only matches when recipient, amount and token are unchanged.
+func applyPreviousResults(path string, payments []Payment) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening the previous results: %v", err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return fmt.Errorf("error reading the previous results: %v", err)
+	}
+	for _, record := range records {
+		if len(record) < 6 || record[5] != PaymentSent {
+			continue
+		}
+		line, err := strconv.Atoi(record[0])
+		if err != nil {
+			continue
+		}
+		for i := range payments {
+			p := &payments[i]
+			if p.Line == line && strings.EqualFold(p.Recipient.Hex(), record[1]) && p.AmountText == record[2] &&
+				strings.EqualFold(resultToken(p.Asset), record[3]) {
+				p.Hash = record[4]
+				p.Status = PaymentSent
+			}
+		}
+	}
+	return nil
+}
+
+// Totals sums the pending payments per asset and reads the payer's balances
+func (ps *PaymentService) Totals(ctx context.Context, from common.Address, payments []Payment) ([]AssetTotal, error) {
+	var totals []AssetTotal
+	index := map[string]int{}
+	for _, p := range payments {
+		if p.Status == PaymentSent {
+			continue
+		}
+		i, ok := index[p.Asset.ID()]
+		if !ok {
+			i = len(totals)
+			index[p.Asset.ID()] = i
+			totals = append(totals, AssetTotal{Asset: p.Asset, Total: new(big.Int)})
+		}
+		totals[i].Total.Add(totals[i].Total, p.Amount)
+	}
+	for i := range totals {
+		balance, err := ps.balance(ctx, from, totals[i].Asset)
+		if err != nil {
+			return nil, err
+		}
+		totals[i].Balance = balance
+	}
+	return totals, nil
+}
+
+func (ps *PaymentService) balance(ctx context.Context, owner common.Address, asset domain.Asset) (*big.Int, error) {
+	if asset.Contract == "" {
+		balance, err := ps.Transactions.Client.BalanceAt(ctx, owner, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the balance: %v", err)
+		}
+		return balance, nil
+	}
+	out, err := callContract(ctx, ps.Transactions.Client, common.HexToAddress(asset.Contract), ERC20ABI, "balanceOf", owner)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the %s balance: %v", asset.Symbol, err)
+	}
+	return out[0].(*big.Int), nil
+}
+
+// Build prepares the transactions for every pending payment with consecutive
+// nonces. With useDisperse each asset is paid in a single Disperse call, preceded
+// by an approval when the token allowance is not enough.
+func (ps *PaymentService) Build(ctx context.Context, from common.Address, payments []Payment, useDisperse bool) (*PaymentBatch, error) {
+	totals, err := ps.Totals(ctx, from, payments)
+	if err != nil {
+		return nil, err
+	}
+	for _, total := range totals {
+		if total.Short() {
+			return nil, fmt.Errorf("insufficient %s balance: %s needed, %s available", total.Asset.Symbol,
+				FormatUnits(total.Total, total.Asset.Decimals), FormatUnits(total.Balance, total.Asset.Decimals))
+		}
+	}
+
+	var requests []TxRequest
+	var settled [][]int
+	if useDisperse {
+		if ps.Disperse == nil {
+			return nil, fmt.Errorf("no Disperse contract configured for this network")
+		}
+		requests, settled, err = ps.disperseRequests(ctx, from, payments)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for i, p := range payments {
+			if p.Status == PaymentSent {
+				continue
+			}
+			request, err := transferRequest(p)
+			if err != nil {
+				return nil, err
+			}
+			requests = append(requests, request)
+			settled = append(settled, []int{i})
+		}
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("all payments were already sent")
+	}
+
+	txs, err := ps.Transactions.BuildBatch(ctx, from, requests)
+	if err != nil {
+		return nil, err
+	}
+	batch := &PaymentBatch{Txs: txs, Payments: settled, Fees: new(big.Int)}
+	for _, tx := range txs {
+		batch.Fees.Add(batch.Fees, new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap()))
+	}
+
+	// The native balance must also cover the fees of the whole batch
+	native, err := ps.Transactions.Client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the balance: %v", err)
+	}
+	needed := new(big.Int).Set(batch.Fees)
+	for _, total := range totals {
+		if total.Asset.Contract == "" {
+			needed.Add(needed, total.Total)
+		}
+	}
+	if native.Cmp(needed) < 0 {
+		return nil, fmt.Errorf("insufficient balance for payments and fees: %s needed, %s available",
+			FormatUnits(needed, 18), FormatUnits(native, 18))
+	}
+	return batch, nil
+}
+
+func transferRequest(p Payment) (TxRequest, error) {
+	if p.Asset.Contract == "" {
+		to := p.Recipient
+		return TxRequest{To: &to, Value: p.Amount}, nil
+	}
+	data, err := ERC20ABI.Pack("transfer", p.Recipient, p.Amount)
+	if err != nil {
+		return TxRequest{}, err
+	}
+	token := common.HexToAddress(p.Asset.Contract)
+	return TxRequest{To: &token, Data: data}, nil
+}
+
+func (ps *PaymentService) disperseRequests(ctx context.Context, from common.Address, payments []Payment) ([]TxRequest, [][]int, error) {
+	// Group pending payments per asset, keeping the file order of the assets
+	groups := map[string][]int{}
+	var order []string
+	for i, p := range payments {
+		if p.Status == PaymentSent {
+			continue
+		}
+		id := p.Asset.ID()
+		if _, ok := groups[id]; !ok {
+			order = append(order, id)
+		}
+		groups[id] = append(groups[id], i)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i] == "native" && order[j] != "native" })
+
+	var requests []TxRequest
+	var settled [][]int
+	for _, id := range order {
+		indexes := groups[id]
+		asset := payments[indexes[0]].Asset
+		recipients := make([]common.Address, len(indexes))
+		values := make([]*big.Int, len(indexes))
+		total := new(big.Int)
+		for k, i := range indexes {
+			recipients[k] = payments[i].Recipient
+			values[k] = payments[i].Amount
+			total.Add(total, payments[i].Amount)
+		}
+
+		if asset.Contract == "" {
+			data, err := DisperseABI.Pack("disperseEther", recipients, values)
+			if err != nil {
+				return nil, nil, err
+			}
+			requests = append(requests, TxRequest{To: ps.Disperse, Value: total, Data: data})
+			settled = append(settled, indexes)
+			continue
+		}
+
+		token := common.HexToAddress(asset.Contract)
+		out, err := callContract(ctx, ps.Transactions.Client, token, ERC20ABI, "allowance", from, *ps.Disperse)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading the %s allowance: %v", asset.Symbol, err)
+		}
+		var gas uint64
+		if out[0].(*big.Int).Cmp(total) < 0 {
+			data, err := ERC20ABI.Pack("approve", *ps.Disperse, total)
+			if err != nil {
+				return nil, nil, err
+			}
+			requests = append(requests, TxRequest{To: &token, Data: data})
+			settled = append(settled, nil)
+			gas = disperseGasBase + disperseGasPerRecipient*uint64(len(indexes))
+		}
+		data, err := DisperseABI.Pack("disperseToken", token, recipients, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		requests = append(requests, TxRequest{To: ps.Disperse, Data: data, Gas: gas})
+		settled = append(settled, indexes)
+	}
+	return requests, settled, nil
+}
+
+// Sign signs every transaction of the batch before anything is broadcast
+func (ps *PaymentService) Sign(ctx context.Context, details *WalletDetails, batch *PaymentBatch, payments []Payment) error {
+	for i, tx := range batch.Txs {
+		memo := "batch payment approval"
+		if len(batch.Payments[i]) > 0 {
+			lines := make([]string, len(batch.Payments[i]))
+			for k, index := range batch.Payments[i] {
+				lines[k] = strconv.Itoa(payments[index].Line)
+			}
+			memo = "batch payment line " + strings.Join(lines, ",")
+		}
+		signed, err := ps.Transactions.SignTransaction(ctx, details, tx, memo)
+		if err != nil {
+			return err
+		}
+		batch.Txs[i] = signed
+	}
+	return nil
+}
+
+// Broadcast sends one signed transaction of the batch. A transaction the node
+// already knows counts as sent, so resuming after a failure is safe.
+func (ps *PaymentService) Broadcast(ctx context.Context, tx *types.Transaction) error {
+	err := ps.Transactions.SendTransaction(ctx, tx)
+	if err != nil && strings.Contains(err.Error(), "already known") {
+		return nil
+	}
+	return err
+}
+
+// RecordBroadcast marks the payments settled by transaction index of the batch
+// with the outcome of its broadcast
+func (b *PaymentBatch) RecordBroadcast(payments []Payment, index int, err error) {
+	hash := b.Txs[index].Hash().Hex()
+	for _, i := range b.Payments[index] {
+		p := &payments[i]
+		p.Hash = hash
+		p.Status = PaymentSent
+		p.Error = ""
+		if err != nil {
+			p.Status = PaymentFailed
+			p.Error = err.Error()
+		}
+	}
+}
+
+// WriteResults writes line,recipient,amount,token,tx_hash,status,error for every payment
+func WriteResults(path string, payments []Payment) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating the results file: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"line", "recipient", "amount", "token", "tx_hash", "status", "error"}); err != nil {
+		return err
+	}
+	for _, p := range payments {
+		record := []string{strconv.Itoa(p.Line), p.Recipient.Hex(), p.AmountText, resultToken(p.Asset), p.Hash, p.Status, p.Error}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// resultToken is the token column of the results file: the contract of a token,
+// or the symbol of the native coin
+func resultToken(asset domain.Asset) string {
+	if asset.Contract != "" {
+		return asset.Contract
+	}
+	return asset.Symbol
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPaymentsResumesFromPreviousResults(t *testing.T) {
+	const (
+		alice = "0x00000000000000000000000000000000000A11CE"
+		bob   = "0x0000000000000000000000000000000000000B0B"
+		usdc  = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	)
+	assets := []domain.Asset{
+		{Symbol: "ETH", Decimals: 18},
+		{Symbol: "USDC", Contract: usdc, Decimals: 6},
+	}
+	payments := NewPaymentService(nil, assets, "")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "payroll.csv")
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(path, "recipient,amount,token\n"+
+		alice+",1.5\n"+
+		bob+",100,USDC\n"+
+		alice+",100,USDC\n"+
+		bob+",2\n")
+
+	// The previous run paid line 2 in ETH and line 4 to another recipient
+	// before the file was edited; only lines 3 and 5 still match
+	write(ResultsPath(path), "line,recipient,amount,token,tx_hash,status,error\n"+
+		"2,"+alice+",1.5,ETH,0x02,failed,out of gas\n"+
+		"3,"+bob+",100,ETH,0x03,sent,\n"+
+		"4,"+bob+",100,"+usdc+",0x04,sent,\n"+
+		"5,"+bob+",2,ETH,0x05,sent,\n")
+
+	loaded, err := payments.LoadPayments(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		status, hash string
+	}{
+		{PaymentPending, ""},
+		{PaymentPending, ""},
+		{PaymentPending, ""},
+		{PaymentSent, "0x05"},
+	}
+	if len(loaded) != len(want) {
+		t.Fatalf("loaded %d payments, want %d", len(loaded), len(want))
+	}
+	for i, p := range loaded {
+		if p.Status != want[i].status || p.Hash != want[i].hash {
+			t.Errorf("line %d: status %s hash %q, want %s %q", p.Line, p.Status, p.Hash, want[i].status, want[i].hash)
+		}
+	}
+
+	// The results written by a run are recognized by the next one
+	for i := range loaded {
+		loaded[i].Status = PaymentSent
+		loaded[i].Hash = "0xfinished"
+	}
+	if err := WriteResults(ResultsPath(path), loaded); err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := payments.LoadPayments(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range resumed {
+		if p.Status != PaymentSent {
+			t.Errorf("line %d was not recognized as sent", p.Line)
+		}
+	}
+}
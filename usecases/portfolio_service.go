@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Holding struct {
+	Asset   domain.Asset
+	Balance *big.Int
+	Price   *domain.Price // Nil when the provider has no quote for the asset
+	Value   float64
+	Proven  bool // Balance verified against the trusted checkpoint
+}
+
+type WalletValuation struct {
+	Address    string
+	Holdings   []Holding
+	Total      float64
+	Unverified []string // Symbols whose balance could not be proven in verified mode
+}
+
+type Portfolio struct {
+	Currency  string
+	Wallets   map[string]*WalletValuation // Keyed by lowercase address
+	Totals    []Holding                   // Aggregated per asset across all wallets
+	Total     float64
+	PricedAt  time.Time // Oldest price used in the valuation
+	UpdatedAt time.Time
+	Unpriced  []string // Symbols held without a price
+
+	// Verified mode
+	Verified    bool           // Balances were requested with proofs
+	Checkpoint  *VerifiedBlock // Trusted block the proofs were checked against
+	VerifyError error          // Why no balance could be proven, such as an unreachable checkpoint
+}
+
+// PortfolioService values managed wallets in a fiat currency using a pluggable price feed
+type PortfolioService struct {
+	Client   domain.ChainClient
+	Prices   domain.PriceProvider
+	Assets   []domain.Asset // Native coin first, then tracked tokens
+	Currency string
+	Verifier *BalanceVerifier // Optional; proven balances replace the endpoint's answers
+}
+
+func NewPortfolioService(client domain.ChainClient, prices domain.PriceProvider, assets []domain.Asset, currency string) *PortfolioService {
+	return &PortfolioService{
+		Client:   client,
+		Prices:   prices,
+		Assets:   assets,
+		Currency: strings.ToLower(currency),
+	}
+}
+
+// Valuate reads the balances of every wallet and prices them
+func (ps *PortfolioService) Valuate(ctx context.Context, wallets []domain.Wallet) (*Portfolio, error) {
+	prices, err := ps.Prices.GetPrices(ctx, ps.Assets, ps.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching prices: %v", err)
+	}
+
+	portfolio := &Portfolio{
+		Currency:  ps.Currency,
+		Wallets:   map[string]*WalletValuation{},
+		UpdatedAt: time.Now(),
+	}
+	totals := make([]Holding, len(ps.Assets))
+	for i, asset := range ps.Assets {
+		totals[i] = Holding{Asset: asset, Balance: new(big.Int)}
+		if price, ok := prices[asset.ID()]; ok {
+			p := price
+			totals[i].Price = &p
+		}
+	}
+
+	proven := ps.prove(ctx, portfolio, wallets)
+
+	for _, wallet := range wallets {
+		valuation := &WalletValuation{Address: wallet.Address}
+		owner := common.HexToAddress(wallet.Address)
+		for i, asset := range ps.Assets {
+			holding := Holding{Asset: asset, Price: totals[i].Price}
+			if proven != nil {
+				balance, err := proven.Balance(owner, asset)
+				if err != nil {
+					log.Printf("Unverified %s balance of %s: %v\n", asset.Symbol, wallet.Address, err)
+				} else {
+					holding.Balance = balance
+					holding.Proven = true
+				}
+			}
+			if !holding.Proven {
+				if portfolio.Verified {
+					valuation.Unverified = append(valuation.Unverified, asset.Symbol)
+				}
+				balance, err := ps.balance(ctx, owner, asset)
+				if err != nil {
+					log.Printf("Error reading the %s balance of %s: %v\n", asset.Symbol, wallet.Address, err)
+					continue
+				}
+				holding.Balance = balance
+			}
+			balance := holding.Balance
+			if holding.Price != nil {
+				holding.Value = toFloat(balance, asset.Decimals) * holding.Price.Value
+			}
+			valuation.Holdings = append(valuation.Holdings, holding)
+			valuation.Total += holding.Value
+			totals[i].Balance.Add(totals[i].Balance, balance)
+			totals[i].Value += holding.Value
+		}
+		portfolio.Wallets[strings.ToLower(wallet.Address)] = valuation
+		portfolio.Total += valuation.Total
+	}
+
+	for _, total := range totals {
+		if total.Balance.Sign() == 0 {
+			continue
+		}
+		portfolio.Totals = append(portfolio.Totals, total)
+		if total.Price == nil {
+			portfolio.Unpriced = append(portfolio.Unpriced, total.Asset.Symbol)
+			continue
+		}
+		if portfolio.PricedAt.IsZero() || total.Price.UpdatedAt.Before(portfolio.PricedAt) {
+			portfolio.PricedAt = total.Price.UpdatedAt
+		}
+	}
+	return portfolio, nil
+}
+
+// prove fetches proven balances when a verifier is configured. A failure to obtain
+// the trusted checkpoint is recorded on the portfolio and every balance stays unverified.
+func (ps *PortfolioService) prove(ctx context.Context, portfolio *Portfolio, wallets []domain.Wallet) *ProvenBalances {
+	if ps.Verifier == nil {
+		return nil
+	}
+	portfolio.Verified = true
+	owners := make([]common.Address, len(wallets))
+	for i, wallet := range wallets {
+		owners[i] = common.HexToAddress(wallet.Address)
+	}
+	proven, err := ps.Verifier.Verify(ctx, owners, ps.Assets)
+	if err != nil {
+		log.Printf("Error verifying balances: %v\n", err)
+		portfolio.VerifyError = err
+		return nil
+	}
+	portfolio.Checkpoint = &proven.Block
+	return proven
+}
+
+func (ps *PortfolioService) balance(ctx context.Context, owner common.Address, asset domain.Asset) (*big.Int, error) {
+	if asset.Contract == "" {
+		return ps.Client.BalanceAt(ctx, owner, nil)
+	}
+	out, err := callContract(ctx, ps.Client, common.HexToAddress(asset.Contract), ERC20ABI, "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+func toFloat(amount *big.Int, decimals int) float64 {
+	value, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(amount),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)),
+	).Float64()
+	return value
+}
@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session keeps unlocked wallets in memory for a limited time, so reopening a
+// wallet skips the password and the key derivation, and tracks user activity
+// for the inactivity lock
+type Session struct {
+	TTL         time.Duration // How long an unlocked wallet stays cached; zero disables the cache
+	IdleTimeout time.Duration // Inactivity before the application locks; zero disables it
+
+	mu           sync.Mutex
+	wallets      map[string]sessionEntry // By lowercase address
+	lastActivity time.Time
+}
+
+type sessionEntry struct {
+	details *WalletDetails
+	expires time.Time
+	timer   *time.Timer // Drops the entry at expiry, even if it is never looked up again
+}
+
+func NewSession(ttl, idleTimeout time.Duration) *Session {
+	return &Session{
+		TTL:          ttl,
+		IdleTimeout:  idleTimeout,
+		wallets:      map[string]sessionEntry{},
+		lastActivity: time.Now(),
+	}
+}
+
+// Get returns the cached unlock of address while it has not expired
+func (s *Session) Get(address string) (*WalletDetails, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.ToLower(address)
+	entry, ok := s.wallets[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		s.remove(key)
+		return nil, false
+	}
+	return entry.details, true
+}
+
+func (s *Session) Put(details *WalletDetails) {
+	if s.TTL <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.ToLower(details.Wallet.Address)
+	s.remove(key)
+	s.wallets[key] = sessionEntry{
+		details: details,
+		expires: time.Now().Add(s.TTL),
+		timer:   time.AfterFunc(s.TTL, func() { s.expire(key, details) }),
+	}
+}
+
+func (s *Session) Forget(address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(strings.ToLower(address))
+}
+
+// Clear drops every cached key
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.wallets {
+		s.remove(key)
+	}
+}
+
+// expire drops the entry of key if it still holds details, since a newer unlock
+// may have replaced it
+func (s *Session) expire(key string, details *WalletDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.wallets[key]; ok && entry.details == details {
+		delete(s.wallets, key)
+	}
+}
+
+// remove deletes the entry of key and stops its timer; s.mu must be held
+func (s *Session) remove(key string) {
+	if entry, ok := s.wallets[key]; ok {
+		entry.timer.Stop()
+		delete(s.wallets, key)
+	}
+}
+
+// Touch records user activity, postponing the inactivity lock
+func (s *Session) Touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActivity = time.Now()
+}
+
+// Remaining is the time left before the inactivity lock
+func (s *Session) Remaining() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	remaining := s.IdleTimeout - time.Since(s.lastActivity)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Idle reports whether the inactivity timeout has elapsed
+func (s *Session) Idle() bool {
+	return s.IdleTimeout > 0 && s.Remaining() == 0
+}
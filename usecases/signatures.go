@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ParseSignature turns a text signature such as "transfer(address,uint256)" or
+// "f((uint8,bytes4)[],bool)" into a function name and unnamed ABI arguments
+func ParseSignature(signature string) (string, abi.Arguments, error) {
+	signature = strings.ReplaceAll(strings.TrimSpace(signature), " ", "")
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return "", nil, fmt.Errorf("invalid signature: %s", signature)
+	}
+	types, err := splitTypes(signature[open+1 : len(signature)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid signature %s: %v", signature, err)
+	}
+	args := make(abi.Arguments, len(types))
+	for i, t := range types {
+		marshaling, err := typeMarshaling(fmt.Sprintf("arg%d", i), t)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid signature %s: %v", signature, err)
+		}
+		typ, err := abi.NewType(marshaling.Type, "", marshaling.Components)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid signature %s: %v", signature, err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return signature[:open], args, nil
+}
+
+// typeMarshaling expands tuple types into components, naming fields positionally
+func typeMarshaling(name, t string) (abi.ArgumentMarshaling, error) {
+	if !strings.HasPrefix(t, "(") {
+		return abi.ArgumentMarshaling{Name: name, Type: t}, nil
+	}
+	closing := strings.LastIndex(t, ")")
+	if closing < 0 {
+		return abi.ArgumentMarshaling{}, fmt.Errorf("unbalanced parentheses in %s", t)
+	}
+	fields, err := splitTypes(t[1:closing])
+	if err != nil {
+		return abi.ArgumentMarshaling{}, err
+	}
+	marshaling := abi.ArgumentMarshaling{Name: name, Type: "tuple" + t[closing+1:]}
+	for i, field := range fields {
+		component, err := typeMarshaling(fmt.Sprintf("field%d", i), field)
+		if err != nil {
+			return abi.ArgumentMarshaling{}, err
+		}
+		marshaling.Components = append(marshaling.Components, component)
+	}
+	return marshaling, nil
+}
+
+// splitTypes splits a comma separated type list, keeping tuples intact
+func splitTypes(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+	var types []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case ',':
+			if depth == 0 {
+				types = append(types, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	return append(types, list[start:]), nil
+}
+
+// SignatureSelector computes the 4-byte selector of a text signature
+func SignatureSelector(signature string) string {
+	return hexutil.Encode(crypto.Keccak256([]byte(signature))[:4])
+}
+
+// ImportSignatures loads a 4-byte database file into the repository. Two formats are
+// accepted: a JSON object mapping selectors to a signature or a list of signatures,
+// or a text file with one "<selector> <signature>" or bare "<signature>" per line.
+// Entries whose selector does not match the signature are skipped.
+func ImportSignatures(repo domain.SelectorRepository, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	entries := map[string][]string{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return 0, fmt.Errorf("error decoding %s: %v", path, err)
+		}
+		for selector, value := range raw {
+			var list []string
+			var single string
+			if json.Unmarshal(value, &single) == nil {
+				list = []string{single}
+			} else if err := json.Unmarshal(value, &list); err != nil {
+				return 0, fmt.Errorf("error decoding %s: invalid entry for %s", path, selector)
+			}
+			entries[selector] = append(entries[selector], list...)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if sep := strings.IndexAny(line, " \t,"); sep > 0 && !strings.Contains(line[:sep], "(") {
+				selector := line[:sep]
+				entries[selector] = append(entries[selector], strings.TrimSpace(line[sep+1:]))
+				continue
+			}
+			selector := SignatureSelector(strings.ReplaceAll(line, " ", ""))
+			entries[selector] = append(entries[selector], line)
+		}
+		if err := scanner.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	valid := map[string][]string{}
+	count := 0
+	for selector, list := range entries {
+		selector = strings.ToLower(selector)
+		if !strings.HasPrefix(selector, "0x") {
+			selector = "0x" + selector
+		}
+		for _, signature := range list {
+			signature = strings.ReplaceAll(signature, " ", "")
+			if SignatureSelector(signature) != selector {
+				continue
+			}
+			valid[selector] = append(valid[selector], signature)
+			count++
+		}
+	}
+	return count, repo.AddSignatures(valid)
+}
@@ -0,0 +1,319 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// nativeTransferAddress is the pseudo-contract eth_simulateV1 uses for the Transfer
+// logs it emits for native value transfers when traceTransfers is enabled
+var nativeTransferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+// StateOverride replaces account fields for the duration of a simulation
+type StateOverride struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes              `json:"code,omitempty"`
+	State     map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// BalanceDelta is a change in one of the sender's balances
+type BalanceDelta struct {
+	Token    string // Contract address, empty for the native coin
+	Symbol   string
+	Decimals int
+	TokenID  string   // Set for NFT transfers
+	Amount   *big.Int // Signed change for the sender
+}
+
+type Simulation struct {
+	Success      bool
+	RevertReason string
+	GasUsed      uint64
+	Fee          *big.Int // GasUsed at the price the transaction would pay in the latest block
+	Deltas       []BalanceDelta
+	// LogsAvailable is false when the node lacks eth_simulateV1 and the simulation fell
+	// back to eth_call; only the native value and fee are known in that case
+	LogsAvailable bool
+}
+
+// SimulationService runs unsigned transactions against the node before signing
+type SimulationService struct {
+	RPC    domain.RPCCaller
+	Client domain.ChainClient
+}
+
+func NewSimulationService(rpc domain.RPCCaller, client domain.ChainClient) *SimulationService {
+	return &SimulationService{
+		RPC:    rpc,
+		Client: client,
+	}
+}
+
+type simulatedCall struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Logs       []types.Log    `json:"logs"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Status     hexutil.Uint64 `json:"status"`
+	Error      *struct {
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// Simulate executes tx from the sender on top of the latest block. eth_simulateV1 is
+// used when the node supports it, since it reports logs; otherwise eth_call and
+// eth_estimateGas provide the outcome and gas.
+func (ss *SimulationService) Simulate(ctx context.Context, from common.Address, tx *types.Transaction, overrides map[common.Address]StateOverride) (*Simulation, error) {
+	call := map[string]interface{}{
+		"from":  from,
+		"gas":   hexutil.Uint64(tx.Gas()),
+		"value": (*hexutil.Big)(tx.Value()),
+		"input": hexutil.Bytes(tx.Data()),
+	}
+	if tx.To() != nil {
+		call["to"] = *tx.To()
+	}
+
+	simulation, err := ss.simulateV1(ctx, from, call, overrides)
+	if err != nil {
+		if !isMethodNotFound(err) {
+			return nil, err
+		}
+		simulation, err = ss.simulateCall(ctx, from, tx, call, overrides)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	price, err := ss.effectiveGasPrice(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	simulation.Fee = new(big.Int).Mul(new(big.Int).SetUint64(simulation.GasUsed), price)
+	return simulation, nil
+}
+
+func (ss *SimulationService) simulateV1(ctx context.Context, from common.Address, call map[string]interface{}, overrides map[common.Address]StateOverride) (*Simulation, error) {
+	block := map[string]interface{}{"calls": []interface{}{call}}
+	if len(overrides) > 0 {
+		block["stateOverrides"] = overrides
+	}
+	opts := map[string]interface{}{
+		"blockStateCalls": []interface{}{block},
+		"traceTransfers":  true,
+	}
+
+	var blocks []struct {
+		Calls []simulatedCall `json:"calls"`
+	}
+	if err := ss.RPC.CallContext(ctx, &blocks, "eth_simulateV1", opts, "latest"); err != nil {
+		return nil, err
+	}
+	if len(blocks) != 1 || len(blocks[0].Calls) != 1 {
+		return nil, fmt.Errorf("unexpected eth_simulateV1 response")
+	}
+	result := blocks[0].Calls[0]
+
+	simulation := &Simulation{
+		Success:       result.Status == 1,
+		GasUsed:       uint64(result.GasUsed),
+		LogsAvailable: true,
+	}
+	if !simulation.Success {
+		simulation.RevertReason = revertReason(result.ReturnData)
+		if simulation.RevertReason == "" && result.Error != nil {
+			simulation.RevertReason = strings.TrimPrefix(result.Error.Message, "execution reverted: ")
+		}
+		return simulation, nil
+	}
+	simulation.Deltas = ss.deltas(ctx, from, result.Logs)
+	return simulation, nil
+}
+
+// simulateCall is the eth_call fallback for nodes without eth_simulateV1
+func (ss *SimulationService) simulateCall(ctx context.Context, from common.Address, tx *types.Transaction, call map[string]interface{}, overrides map[common.Address]StateOverride) (*Simulation, error) {
+	args := []interface{}{call, "latest"}
+	if len(overrides) > 0 {
+		args = append(args, overrides)
+	}
+	var output hexutil.Bytes
+	err := ss.RPC.CallContext(ctx, &output, "eth_call", args...)
+	if err != nil {
+		reason, ok := callRevertReason(err)
+		if !ok {
+			return nil, err
+		}
+		return &Simulation{RevertReason: reason}, nil
+	}
+
+	var gas hexutil.Uint64
+	if err := ss.RPC.CallContext(ctx, &gas, "eth_estimateGas", args...); err != nil {
+		return nil, fmt.Errorf("error estimating gas: %v", err)
+	}
+	simulation := &Simulation{Success: true, GasUsed: uint64(gas)}
+	if tx.Value().Sign() > 0 && (tx.To() == nil || *tx.To() != from) {
+		simulation.Deltas = []BalanceDelta{{Decimals: 18, Amount: new(big.Int).Neg(tx.Value())}}
+	}
+	return simulation, nil
+}
+
+// deltas sums the Transfer logs touching the sender, per token and NFT id
+func (ss *SimulationService) deltas(ctx context.Context, owner common.Address, logs []types.Log) []BalanceDelta {
+	transfer := ERC20ABI.Events["Transfer"].ID
+	single := ERC1155ABI.Events["TransferSingle"].ID
+	batch := ERC1155ABI.Events["TransferBatch"].ID
+
+	totals := map[string]*BalanceDelta{}
+	add := func(contract common.Address, tokenID string, from, to common.Address, amount *big.Int) {
+		if from == to || (from != owner && to != owner) {
+			return
+		}
+		key := strings.ToLower(contract.Hex()) + "/" + tokenID
+		delta, ok := totals[key]
+		if !ok {
+			delta = &BalanceDelta{TokenID: tokenID, Amount: new(big.Int)}
+			if contract != nativeTransferAddress {
+				delta.Token = contract.Hex()
+			}
+			totals[key] = delta
+		}
+		if from == owner {
+			delta.Amount.Sub(delta.Amount, amount)
+		} else {
+			delta.Amount.Add(delta.Amount, amount)
+		}
+	}
+
+	for _, entry := range logs {
+		if len(entry.Topics) == 0 {
+			continue
+		}
+		switch {
+		case entry.Topics[0] == transfer && len(entry.Topics) == 3 && len(entry.Data) == 32:
+			// ERC-20 and native transfers traced by the node
+			add(entry.Address, "", topicAddress(entry.Topics[1]), topicAddress(entry.Topics[2]), new(big.Int).SetBytes(entry.Data))
+		case entry.Topics[0] == transfer && len(entry.Topics) == 4:
+			add(entry.Address, entry.Topics[3].Big().String(), topicAddress(entry.Topics[1]), topicAddress(entry.Topics[2]), big.NewInt(1))
+		case entry.Topics[0] == single && len(entry.Topics) == 4:
+			values, err := ERC1155ABI.Events["TransferSingle"].Inputs.NonIndexed().Unpack(entry.Data)
+			if err != nil {
+				continue
+			}
+			add(entry.Address, values[0].(*big.Int).String(), topicAddress(entry.Topics[2]), topicAddress(entry.Topics[3]), values[1].(*big.Int))
+		case entry.Topics[0] == batch && len(entry.Topics) == 4:
+			values, err := ERC1155ABI.Events["TransferBatch"].Inputs.NonIndexed().Unpack(entry.Data)
+			if err != nil {
+				continue
+			}
+			ids, amounts := values[0].([]*big.Int), values[1].([]*big.Int)
+			for i := range ids {
+				if i < len(amounts) {
+					add(entry.Address, ids[i].String(), topicAddress(entry.Topics[2]), topicAddress(entry.Topics[3]), amounts[i])
+				}
+			}
+		}
+	}
+
+	deltas := make([]BalanceDelta, 0, len(totals))
+	for _, delta := range totals {
+		if delta.Amount.Sign() == 0 {
+			continue
+		}
+		if delta.Token == "" {
+			delta.Decimals = 18
+		} else if delta.TokenID == "" {
+			ss.tokenMetadata(ctx, delta)
+		}
+		deltas = append(deltas, *delta)
+	}
+	// Native coin first, then by token and id for a stable presentation
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Token != deltas[j].Token {
+			return deltas[i].Token < deltas[j].Token
+		}
+		return deltas[i].TokenID < deltas[j].TokenID
+	})
+	return deltas
+}
+
+func (ss *SimulationService) tokenMetadata(ctx context.Context, delta *BalanceDelta) {
+	contract := common.HexToAddress(delta.Token)
+	if out, err := callContract(ctx, ss.Client, contract, ERC20ABI, "decimals"); err == nil {
+		if decimals, ok := out[0].(uint8); ok {
+			delta.Decimals = int(decimals)
+		}
+	}
+	if out, err := callContract(ctx, ss.Client, contract, ERC20ABI, "symbol"); err == nil {
+		delta.Symbol, _ = out[0].(string)
+	}
+}
+
+// effectiveGasPrice is what the transaction would pay per gas in the latest block
+func (ss *SimulationService) effectiveGasPrice(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	if tx.Type() == types.LegacyTxType {
+		return tx.GasPrice(), nil
+	}
+	head, err := ss.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the latest block: %v", err)
+	}
+	if head.BaseFee == nil {
+		return tx.GasFeeCap(), nil
+	}
+	price := new(big.Int).Add(head.BaseFee, tx.GasTipCap())
+	if price.Cmp(tx.GasFeeCap()) > 0 {
+		price = tx.GasFeeCap()
+	}
+	return price, nil
+}
+
+func topicAddress(topic common.Hash) common.Address {
+	return common.BytesToAddress(topic.Bytes())
+}
+
+// revertReason decodes Error(string) and Panic(uint256) revert data
+func revertReason(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	return hexutil.Encode(data)
+}
+
+// callRevertReason extracts the revert reason from an eth_call error
+func callRevertReason(err error) (string, bool) {
+	var dataErr interface{ ErrorData() interface{} }
+	if !errors.As(err, &dataErr) {
+		return "", false
+	}
+	if data, ok := dataErr.ErrorData().(string); ok {
+		if raw, decodeErr := hexutil.Decode(data); decodeErr == nil {
+			if reason := revertReason(raw); reason != "" {
+				return reason, true
+			}
+		}
+	}
+	return err.Error(), true
+}
+
+func isMethodNotFound(err error) bool {
+	var rpcErr interface{ ErrorCode() int }
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+		return true
+	}
+	return strings.Contains(err.Error(), "method not found") || strings.Contains(err.Error(), "does not exist")
+}
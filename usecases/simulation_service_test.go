@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"blocowallet/infrastructure"
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// noSimulateV1 stands for a node without eth_simulateV1, forcing the eth_call fallback
+type noSimulateV1 struct {
+	rpc *rpc.Client
+}
+
+func (c noSimulateV1) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method == "eth_simulateV1" {
+		return errors.New("the method eth_simulateV1 does not exist/is not available")
+	}
+	return c.rpc.CallContext(ctx, result, method, args...)
+}
+
+// newTestChain starts a dev chain with the given balances. The simulated backend
+// keeps its RPC client private, so it is reached through the dev chain's HTTP
+// endpoint, as the application does.
+func newTestChain(t *testing.T, prefund map[common.Address]*big.Int) (*infrastructure.DevChain, *rpc.Client) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	chain, err := infrastructure.NewDevChain("127.0.0.1", port, 0, prefund)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	raw, err := rpc.Dial(chain.Endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(raw.Close)
+	return chain, raw
+}
+
+func TestSimulateTransferBalancePreview(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x00000000000000000000000000000000000b10c0")
+	funded := new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))
+
+	_, raw := newTestChain(t, map[common.Address]*big.Int{from: funded})
+	client := ethclient.NewClient(raw)
+	ctx := context.Background()
+
+	value := big.NewInt(params.Ether)
+	tx, err := NewTransactionService(client, nil).BuildTransaction(ctx, from, &to, value, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	price := new(big.Int).Add(head.BaseFee, tx.GasTipCap())
+	wantFee := new(big.Int).Mul(big.NewInt(int64(params.TxGas)), price)
+
+	// An address without funds only succeeds with a balance override
+	empty := common.HexToAddress("0x00000000000000000000000000000000000e3e3e")
+	overrides := map[common.Address]StateOverride{empty: {Balance: (*hexutil.Big)(funded)}}
+
+	tests := []struct {
+		name      string
+		rpc       domain.RPCCaller
+		from      common.Address
+		overrides map[common.Address]StateOverride
+		wantLogs  bool
+	}{
+		{name: "eth_simulateV1", rpc: raw, from: from, wantLogs: true},
+		{name: "eth_call fallback", rpc: noSimulateV1{raw}, from: from},
+		{name: "state override", rpc: raw, from: empty, overrides: overrides, wantLogs: true},
+	}
+	for _, tt := range tests {
+		simulation, err := NewSimulationService(tt.rpc, client).Simulate(ctx, tt.from, tx, tt.overrides)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !simulation.Success {
+			t.Errorf("%s: reverted: %s", tt.name, simulation.RevertReason)
+			continue
+		}
+		if simulation.LogsAvailable != tt.wantLogs {
+			t.Errorf("%s: LogsAvailable = %v, want %v", tt.name, simulation.LogsAvailable, tt.wantLogs)
+		}
+		if simulation.GasUsed != params.TxGas {
+			t.Errorf("%s: GasUsed = %d, want %d", tt.name, simulation.GasUsed, params.TxGas)
+		}
+		if simulation.Fee.Cmp(wantFee) != 0 {
+			t.Errorf("%s: Fee = %s, want %s", tt.name, simulation.Fee, wantFee)
+		}
+		if len(simulation.Deltas) != 1 {
+			t.Errorf("%s: got %d balance changes, want 1: %+v", tt.name, len(simulation.Deltas), simulation.Deltas)
+			continue
+		}
+		delta := simulation.Deltas[0]
+		if delta.Token != "" || delta.Decimals != 18 || delta.Amount.Cmp(new(big.Int).Neg(value)) != 0 {
+			t.Errorf("%s: balance change = %+v, want -%s wei of the native coin", tt.name, delta, value)
+		}
+	}
+
+	// Without the override the unfunded sender cannot pay for the transfer
+	if simulation, err := NewSimulationService(raw, client).Simulate(ctx, empty, tx, nil); err == nil && simulation.Success {
+		t.Error("a transfer from an unfunded address was simulated as successful")
+	}
+}
@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// sweepBaseFeeMargin is the headroom, in percent, over the next block's base fee
+const sweepBaseFeeMargin = 25
+
+// londonConfig computes base fees with the EIP-1559 rules active from genesis;
+// only the parent header decides the result
+var londonConfig = &params.ChainConfig{LondonBlock: common.Big0}
+
+// SweepItem is the planned transfer of one source wallet's balance
+type SweepItem struct {
+	Wallet  domain.Wallet
+	Balance *big.Int // Balance of the swept asset
+	Amount  *big.Int // Amount transferred to the destination
+	Fee     *big.Int // Native fee paid by the wallet
+	Tx      *types.Transaction
+	Skip    string // Reason the wallet is left out, empty when Tx is set
+}
+
+// SweepService consolidates the balances of many wallets into one destination
+type SweepService struct {
+	Transactions *TransactionService
+	Assets       []domain.Asset // Native coin first, then tokens that can be swept
+}
+
+func NewSweepService(transactions *TransactionService, assets []domain.Asset) *SweepService {
+	return &SweepService{
+		Transactions: transactions,
+		Assets:       assets,
+	}
+}
+
+// Plan builds one unsigned transaction per wallet. Native sweeps send the balance
+// minus the most the fee can cost; token sweeps send the full token balance and pay the fee
+// from the wallet's native balance. Wallets that cannot be swept are kept with a
+// Skip reason so the caller can report them.
+func (s *SweepService) Plan(ctx context.Context, wallets []domain.Wallet, to common.Address, asset domain.Asset) ([]SweepItem, error) {
+	items := make([]SweepItem, 0, len(wallets))
+	for _, w := range wallets {
+		from := common.HexToAddress(w.Address)
+		item := SweepItem{Wallet: w}
+		if from == to {
+			item.Skip = "destination"
+			items = append(items, item)
+			continue
+		}
+
+		var err error
+		if asset.Contract == "" {
+			err = s.planNative(ctx, &item, from, to)
+		} else {
+			err = s.planToken(ctx, &item, from, to, common.HexToAddress(asset.Contract))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", w.Address, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *SweepService) planNative(ctx context.Context, item *SweepItem, from, to common.Address) error {
+	client := s.Transactions.Client
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return fmt.Errorf("error reading the balance: %v", err)
+	}
+	item.Balance = balance
+	if balance.Sign() == 0 {
+		item.Skip = "empty"
+		return nil
+	}
+
+	// Build with a zero value to size gas and fees, then move the rest of the balance
+	tx, err := s.Transactions.BuildTransaction(ctx, from, &to, nil, nil)
+	if err != nil {
+		return err
+	}
+	feeCap := tx.GasPrice()
+	if tx.Type() != types.LegacyTxType {
+		head, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("error reading the latest block: %v", err)
+		}
+		if head.BaseFee == nil {
+			return fmt.Errorf("the latest block has no base fee")
+		}
+		feeCap = new(big.Int).Add(sweepBaseFee(head), tx.GasTipCap())
+	}
+	item.Fee = new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), feeCap)
+	amount := new(big.Int).Sub(balance, item.Fee)
+	if amount.Sign() <= 0 {
+		item.Skip = "below_fee"
+		return nil
+	}
+	item.Amount = amount
+	item.Tx = sweepTx(tx, feeCap, amount)
+	return nil
+}
+
+func (s *SweepService) planToken(ctx context.Context, item *SweepItem, from, to, token common.Address) error {
+	client := s.Transactions.Client
+	out, err := callContract(ctx, client, token, ERC20ABI, "balanceOf", from)
+	if err != nil {
+		return fmt.Errorf("error reading the token balance: %v", err)
+	}
+	balance, ok := out[0].(*big.Int)
+	if !ok {
+		return fmt.Errorf("unexpected balanceOf result from %s", token.Hex())
+	}
+	item.Balance = balance
+	if balance.Sign() == 0 {
+		item.Skip = "empty"
+		return nil
+	}
+
+	data, err := ERC20ABI.Pack("transfer", to, balance)
+	if err != nil {
+		return err
+	}
+	tx, err := s.Transactions.BuildTransaction(ctx, from, &token, nil, data)
+	if err != nil {
+		return err
+	}
+	item.Fee = new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+	native, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return fmt.Errorf("error reading the balance: %v", err)
+	}
+	if native.Cmp(item.Fee) < 0 {
+		item.Skip = "below_fee"
+		return nil
+	}
+	item.Amount = balance
+	item.Tx = tx
+	return nil
+}
+
+// sweepBaseFee is the base fee a sweep is priced for: the next block's base fee
+// plus sweepBaseFeeMargin. A sweep moves the whole balance, so it cannot be
+// replaced with a higher fee if it gets stuck; the margin lets it wait out about
+// two blocks of maximum base fee increase instead.
+func sweepBaseFee(head *types.Header) *big.Int {
+	next := eip1559.CalcBaseFee(londonConfig, head)
+	margin := new(big.Int).Mul(next, big.NewInt(sweepBaseFeeMargin))
+	return next.Add(next, margin.Div(margin, big.NewInt(100)))
+}
+
+// sweepTx copies tx with the given value and, for EIP-1559 transactions, the fee
+// cap of the sweep, so the validator gets no more than the suggested tip. The
+// wallet pays at most gas * feeCap, which is taken out of the swept amount; if
+// the base fee ends up below sweepBaseFee, the difference stays in the wallet.
+func sweepTx(tx *types.Transaction, feeCap, value *big.Int) *types.Transaction {
+	if tx.Type() == types.LegacyTxType {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: tx.GasPrice(),
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    value,
+			Data:     tx.Data(),
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: feeCap,
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     value,
+		Data:      tx.Data(),
+	})
+}
+
+// UnlockGroup decrypts every wallet that opens with password and returns the
+// details by position in wallets. Wallets with another password are left out,
+// as are wallets waiting after failed attempts. Wallets of several groups are
+// swept with one password per group, so a password that opens some of them is
+// not a guess at the others; only a password that opens none is counted as a
+// failure, once, for each wallet it was tried on.
+func (ws *WalletService) UnlockGroup(wallets []domain.Wallet, password string) map[int]*WalletDetails {
+	const source = "sweep"
+	unlocked := make(map[int]*WalletDetails)
+	var missed []int
+	for i := range wallets {
+		wallet := &wallets[i]
+		if ws.Guard != nil {
+			if err := ws.Guard.Check(wallet, source); err != nil {
+				continue
+			}
+		}
+		details, err := ws.LoadWallet(wallet, password)
+		if errors.Is(err, domain.ErrIncorrectPassword) {
+			missed = append(missed, i)
+			continue
+		}
+		if err != nil {
+			log.Printf("Error unlocking %s for the sweep: %v\n", wallet.Address, err)
+			continue
+		}
+		if ws.Guard != nil {
+			if err := ws.Guard.Success(wallet); err != nil {
+				log.Printf("Error clearing the failed attempts of %s: %v\n", wallet.Address, err)
+			}
+		}
+		unlocked[i] = details
+	}
+
+	if len(unlocked) > 0 || ws.Guard == nil {
+		return unlocked
+	}
+	for _, i := range missed {
+		if _, err := ws.Guard.Failure(&wallets[i], source); err != nil {
+			log.Printf("Error recording the failed attempt of %s: %v\n", wallets[i].Address, err)
+		}
+	}
+	return unlocked
+}
@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"blocowallet/infrastructure"
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newTestWalletService keeps wallets in a temporary database and keystore, with
+// the default attempt limits
+func newTestWalletService(t *testing.T) (*WalletService, *infrastructure.SQLiteRepository) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := infrastructure.NewSQLiteRepository(filepath.Join(dir, "wallets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	ks := keystore.NewKeyStore(filepath.Join(dir, "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+	ws := NewWalletService(repo, infrastructure.NewKeystoreBackend(ks))
+	ws.Guard = NewUnlockGuard(repo, 5, time.Second, time.Minute, 30*time.Minute)
+	return ws, repo
+}
+
+func TestUnlockGroupCountsOnePasswordPerGroup(t *testing.T) {
+	ws, _ := newTestWalletService(t)
+	groups := []string{"password-a", "password-b", "password-c"}
+	var wallets []domain.Wallet
+	for _, password := range groups {
+		for i := 0; i < 2; i++ {
+			details, err := ws.CreateWallet(password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wallets = append(wallets, *details.Wallet)
+		}
+	}
+
+	failures := func(wallet *domain.Wallet) int {
+		t.Helper()
+		status, err := ws.Guard.Status(wallet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return status.Failures
+	}
+
+	// Each group's password opens its own wallets and leaves no failure on the
+	// wallets still locked
+	opened := map[string]bool{}
+	for g, password := range groups {
+		var locked []domain.Wallet
+		for _, wallet := range wallets {
+			if !opened[wallet.Address] {
+				locked = append(locked, wallet)
+			}
+		}
+		unlocked := ws.UnlockGroup(locked, password)
+		if len(unlocked) != 2 {
+			t.Fatalf("%s opened %d wallet(s), want 2", password, len(unlocked))
+		}
+		for i := range unlocked {
+			if address := locked[i].Address; address != wallets[2*g].Address && address != wallets[2*g+1].Address {
+				t.Errorf("%s opened %s, which belongs to another group", password, locked[i].Address)
+			}
+			opened[locked[i].Address] = true
+		}
+		for i := range wallets {
+			if n := failures(&wallets[i]); n != 0 {
+				t.Errorf("after %s, wallet %d has %d failure(s)", password, i, n)
+			}
+		}
+	}
+
+	// A password that opens nothing is one failure for each wallet it was tried on
+	if unlocked := ws.UnlockGroup(wallets, "wrong"); len(unlocked) != 0 {
+		t.Fatalf("a wrong password opened %d wallet(s)", len(unlocked))
+	}
+	for i := range wallets {
+		if n := failures(&wallets[i]); n != 1 {
+			t.Errorf("after a wrong password, wallet %d has %d failure(s), want 1", i, n)
+		}
+	}
+}
+
+func TestPlanNativeSweepLeavesFeeHeadroom(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x00000000000000000000000000000000000b10c0")
+	balance := big.NewInt(params.Ether)
+	chain, raw := newTestChain(t, map[common.Address]*big.Int{from: balance})
+	client := ethclient.NewClient(raw)
+	ctx := context.Background()
+
+	sweeps := NewSweepService(NewTransactionService(client, nil), nil)
+	items, err := sweeps.Plan(ctx, []domain.Wallet{{Address: from.Hex()}}, to, domain.Asset{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := items[0]
+	if item.Tx == nil {
+		t.Fatalf("the wallet was skipped: %s", item.Skip)
+	}
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := eip1559.CalcBaseFee(londonConfig, head)
+	wantCap := new(big.Int).Add(next, new(big.Int).Div(new(big.Int).Mul(next, big.NewInt(sweepBaseFeeMargin)), big.NewInt(100)))
+	wantCap.Add(wantCap, item.Tx.GasTipCap())
+	if item.Tx.GasFeeCap().Cmp(wantCap) != 0 {
+		t.Errorf("fee cap = %s, want %s", item.Tx.GasFeeCap(), wantCap)
+	}
+	if item.Tx.GasTipCap().Cmp(item.Tx.GasFeeCap()) >= 0 {
+		t.Errorf("tip %s is not below the fee cap %s", item.Tx.GasTipCap(), item.Tx.GasFeeCap())
+	}
+	if sum := new(big.Int).Add(item.Amount, item.Fee); sum.Cmp(balance) != 0 {
+		t.Errorf("amount + fee = %s, want the balance %s", sum, balance)
+	}
+
+	signed, err := types.SignTx(item.Tx, types.LatestSignerForChainID(item.Tx.ChainId()), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, signed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.Mine(ctx); err != nil {
+		t.Fatal(err)
+	}
+	received, err := client.BalanceAt(ctx, to, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Cmp(item.Amount) != 0 {
+		t.Errorf("destination received %s, want %s", received, item.Amount)
+	}
+}
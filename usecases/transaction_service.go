@@ -0,0 +1,180 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransactionService builds, signs and broadcasts transactions for managed wallets.
+// Every signed or broadcast transaction is recorded in the ledger.
+type TransactionService struct {
+	Client domain.ChainClient
+	Ledger *LedgerService
+}
+
+func NewTransactionService(client domain.ChainClient, ledger *LedgerService) *TransactionService {
+	return &TransactionService{
+		Client: client,
+		Ledger: ledger,
+	}
+}
+
+// TxRequest is one call of a batch built with consecutive nonces
+type TxRequest struct {
+	To    *common.Address
+	Value *big.Int
+	Data  []byte
+	Gas   uint64 // Fixed gas limit for calls that cannot be estimated before earlier ones are mined
+}
+
+// BuildTransaction prepares an unsigned transaction with nonce, gas limit and fees
+// filled from the node. EIP-1559 is used whenever the chain reports a base fee.
+func (ts *TransactionService) BuildTransaction(ctx context.Context, from common.Address, to *common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	nonce, err := ts.Client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the nonce: %v", err)
+	}
+	return ts.BuildTransactionWithNonce(ctx, from, nonce, to, value, data)
+}
+
+// BuildBatch prepares one transaction per request with consecutive nonces starting
+// at the sender's pending nonce, so they can be signed and broadcast in sequence
+func (ts *TransactionService) BuildBatch(ctx context.Context, from common.Address, requests []TxRequest) ([]*types.Transaction, error) {
+	nonce, err := ts.Client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the nonce: %v", err)
+	}
+	txs := make([]*types.Transaction, 0, len(requests))
+	for i, request := range requests {
+		tx, err := ts.buildTransaction(ctx, from, nonce+uint64(i), request)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %v", i+1, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// BuildTransactionWithNonce is BuildTransaction with an explicit nonce
+func (ts *TransactionService) BuildTransactionWithNonce(ctx context.Context, from common.Address, nonce uint64, to *common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return ts.buildTransaction(ctx, from, nonce, TxRequest{To: to, Value: value, Data: data})
+}
+
+func (ts *TransactionService) buildTransaction(ctx context.Context, from common.Address, nonce uint64, request TxRequest) (*types.Transaction, error) {
+	to, value, data := request.To, request.Value, request.Data
+	if value == nil {
+		value = new(big.Int)
+	}
+	chainID, err := ts.Client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the chain id: %v", err)
+	}
+	gas := request.Gas
+	if gas == 0 {
+		gas, err = ts.Client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: to, Value: value, Data: data})
+		if err != nil {
+			return nil, fmt.Errorf("error estimating gas: %v", err)
+		}
+	}
+
+	head, err := ts.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the latest block: %v", err)
+	}
+	if head.BaseFee == nil {
+		gasPrice, err := ts.Client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the gas price: %v", err)
+		}
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasPrice,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}), nil
+	}
+
+	tip, err := ts.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the priority fee: %v", err)
+	}
+	// Leave room for the base fee to double before the transaction is included
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        to,
+		Value:     value,
+		Data:      data,
+	}), nil
+}
+
+// SignTransaction signs tx with the unlocked wallet key and records it in the ledger
+func (ts *TransactionService) SignTransaction(ctx context.Context, details *WalletDetails, tx *types.Transaction, memo string) (*types.Transaction, error) {
+	chainID, err := ts.Client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the chain id: %v", err)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+	signature, err := details.SignDigest(ctx, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("error signing the transaction: %v", err)
+	}
+	signed, err := tx.WithSignature(signer, signature)
+	if err != nil {
+		return nil, fmt.Errorf("error signing the transaction: %v", err)
+	}
+	if ts.Ledger != nil {
+		if _, err := ts.Ledger.RecordSigned(signed, details.Wallet.Address, memo); err != nil {
+			return nil, err
+		}
+	}
+	log.Printf("Transaction %s signed by %s\n", signed.Hash().Hex(), details.Wallet.Address)
+	return signed, nil
+}
+
+// SendTransaction broadcasts a signed transaction and updates its ledger status
+func (ts *TransactionService) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := ts.Client.SendTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("error broadcasting the transaction: %v", err)
+	}
+	if ts.Ledger != nil {
+		if err := ts.Ledger.MarkBroadcast(tx.Hash().Hex()); err != nil {
+			log.Printf("Error updating the ledger for %s: %v\n", tx.Hash().Hex(), err)
+		}
+	}
+	log.Printf("Transaction %s broadcast\n", tx.Hash().Hex())
+	return nil
+}
+
+// RefreshReceipt checks whether a broadcast transaction was mined and records the outcome.
+// It returns nil without error while the transaction is still pending.
+func (ts *TransactionService) RefreshReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := ts.Client.TransactionReceipt(ctx, hash)
+	if err == ethereum.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ts.Ledger != nil {
+		if err := ts.Ledger.MarkReceipt(receipt); err != nil {
+			log.Printf("Error updating the ledger for %s: %v\n", hash.Hex(), err)
+		}
+	}
+	return receipt, nil
+}
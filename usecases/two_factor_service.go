@@ -0,0 +1,315 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RFC 6238 parameters understood by every authenticator app
+const (
+	totpPeriod        = 30 // Seconds per time step
+	totpDigits        = 6
+	totpSkew          = 1  // Steps accepted before and after the current one, for clock drift
+	totpSecretSize    = 20 // 160 bits, the HMAC-SHA1 block recommended by RFC 4226
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5 // 40 bits, eight base32 characters
+)
+
+var (
+	ErrInvalidTwoFactorCode = errors.New("invalid authentication code")
+	ErrTwoFactorEnrolled    = errors.New("two-factor authentication is already enabled for this wallet")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not enabled for this wallet")
+	ErrTwoFactorNeedsVault  = errors.New("two-factor authentication needs the master password to be unlocked")
+	ErrTwoFactorNoVault     = errors.New("set a master password first: two-factor secrets are encrypted with it")
+)
+
+// TwoFactorService enrolls wallets in TOTP two-factor authentication and checks
+// the codes required by the wallet policy. Secrets are encrypted with the vault
+// data key, so enrollment needs the master password.
+type TwoFactorService struct {
+	Repo   domain.TwoFactorRepository
+	Vault  *VaultService
+	Guard  *UnlockGuard // Throttles wrong codes; nil accepts unlimited attempts
+	Issuer string       // Account issuer shown by authenticator apps
+	Now    func() time.Time
+}
+
+// TwoFactorEnrollment is a generated secret waiting for the first code; the
+// recovery codes are shown only at this point
+type TwoFactorEnrollment struct {
+	WalletID      int
+	Secret        string
+	URI           string // otpauth:// URI for authenticator apps
+	RecoveryCodes []string
+}
+
+func NewTwoFactorService(repo domain.TwoFactorRepository, vault *VaultService) *TwoFactorService {
+	return &TwoFactorService{Repo: repo, Vault: vault, Issuer: "BLOCO Wallet", Now: time.Now}
+}
+
+// DefaultTwoFactorPolicy protects every operation except signing, whose
+// threshold is chosen by the user
+func DefaultTwoFactorPolicy() domain.TwoFactorPolicy {
+	return domain.TwoFactorPolicy{Reveal: true, Export: true, Delete: true}
+}
+
+// Status returns the enrollment of the wallet, or nil when it is not enrolled
+func (s *TwoFactorService) Status(walletID int) (*domain.TwoFactor, error) {
+	return s.Repo.GetTwoFactor(walletID)
+}
+
+// Required reports whether op on the wallet needs a code; value is the native
+// amount signed, ignored by the other operations
+func (s *TwoFactorService) Required(walletID int, op domain.TwoFactorOperation, value *big.Int) (bool, error) {
+	tf, err := s.Repo.GetTwoFactor(walletID)
+	if err != nil || tf == nil {
+		return false, err
+	}
+	return tf.Policy.Requires(op, value), nil
+}
+
+// SignedValue is the native amount tx moves, as checked against the sign
+// threshold; nil when tx carries calldata, whose effect is not priced
+func SignedValue(tx *types.Transaction) *big.Int {
+	if len(tx.Data()) > 0 {
+		return nil
+	}
+	return tx.Value()
+}
+
+// Enroll generates the secret and the recovery codes; nothing is stored until
+// ConfirmEnrollment receives a valid code
+func (s *TwoFactorService) Enroll(wallet *domain.Wallet) (*TwoFactorEnrollment, error) {
+	if err := s.requireVault(); err != nil {
+		return nil, err
+	}
+	existing, err := s.Repo.GetTwoFactor(wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrTwoFactorEnrolled
+	}
+
+	raw, err := randomBytes(totpSecretSize)
+	if err != nil {
+		return nil, err
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := randomBytes(recoveryCodeSize)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = formatRecoveryKey(code)
+	}
+	return &TwoFactorEnrollment{
+		WalletID:      wallet.ID,
+		Secret:        secret,
+		URI:           s.otpauthURI(wallet.Address, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// ConfirmEnrollment stores the enrollment once the authenticator app produced a
+// valid code, proving the secret was copied correctly
+func (s *TwoFactorService) ConfirmEnrollment(enrollment *TwoFactorEnrollment, code string) error {
+	step, ok := s.matchTOTP(enrollment.Secret, code, 0)
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	hashes := make([]string, len(enrollment.RecoveryCodes))
+	for i, recoveryCode := range enrollment.RecoveryCodes {
+		hashes[i] = hashRecoveryCode(recoveryCode)
+	}
+	return s.Repo.SaveTwoFactor(&domain.TwoFactor{
+		WalletID:      enrollment.WalletID,
+		Secret:        enrollment.Secret,
+		RecoveryCodes: hashes,
+		Policy:        DefaultTwoFactorPolicy(),
+		LastStep:      step,
+	})
+}
+
+// Verify accepts a current TOTP code or one of the unused recovery codes, which
+// is then discarded
+func (s *TwoFactorService) Verify(wallet *domain.Wallet, code string) error {
+	tf, err := s.enrollment(wallet.ID)
+	if err != nil {
+		return err
+	}
+	return s.verify(wallet, tf, code, "verify")
+}
+
+// UpdatePolicy changes the protected operations after checking a code
+func (s *TwoFactorService) UpdatePolicy(wallet *domain.Wallet, code string, policy domain.TwoFactorPolicy) error {
+	tf, err := s.enrollment(wallet.ID)
+	if err != nil {
+		return err
+	}
+	tf.Policy = policy
+	return s.verify(wallet, tf, code, "policy")
+}
+
+// Disable removes the enrollment after checking a code
+func (s *TwoFactorService) Disable(wallet *domain.Wallet, code string) error {
+	tf, err := s.enrollment(wallet.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.verify(wallet, tf, code, "disable"); err != nil {
+		return err
+	}
+	return s.Repo.DeleteTwoFactor(wallet.ID)
+}
+
+func (s *TwoFactorService) enrollment(walletID int) (*domain.TwoFactor, error) {
+	if err := s.requireVault(); err != nil {
+		return nil, err
+	}
+	tf, err := s.Repo.GetTwoFactor(walletID)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	return tf, nil
+}
+
+// requireVault tells a missing master password, which the user has to set,
+// apart from a locked one, which only has to be entered
+func (s *TwoFactorService) requireVault() error {
+	if s.Vault == nil {
+		return ErrTwoFactorNoVault
+	}
+	initialized, err := s.Vault.Initialized()
+	if err != nil {
+		return err
+	}
+	if !initialized {
+		return ErrTwoFactorNoVault
+	}
+	if s.Vault.Locked() {
+		return ErrTwoFactorNeedsVault
+	}
+	return nil
+}
+
+// verify checks the code through the guard, so wrong codes back off and lock
+// the wallet out like wrong passwords, and saves tf with the accepted step or
+// without the used recovery code
+func (s *TwoFactorService) verify(wallet *domain.Wallet, tf *domain.TwoFactor, code, source string) error {
+	if s.Guard != nil {
+		if err := s.Guard.Check(wallet, source); err != nil {
+			return err
+		}
+	}
+	if !s.match(tf, code) {
+		if s.Guard == nil {
+			return ErrInvalidTwoFactorCode
+		}
+		status, err := s.Guard.Failure(wallet, source)
+		if err != nil {
+			return err
+		}
+		return &UnlockError{Status: status, Err: ErrInvalidTwoFactorCode}
+	}
+	if err := s.Repo.SaveTwoFactor(tf); err != nil {
+		return err
+	}
+	if s.Guard != nil {
+		return s.Guard.Success(wallet)
+	}
+	return nil
+}
+
+// match accepts the code into tf: the TOTP step is recorded, or the recovery
+// code is removed
+func (s *TwoFactorService) match(tf *domain.TwoFactor, code string) bool {
+	if step, ok := s.matchTOTP(tf.Secret, code, tf.LastStep); ok {
+		tf.LastStep = step
+		return true
+	}
+	hash := hashRecoveryCode(code)
+	for i, stored := range tf.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			tf.RecoveryCodes = append(tf.RecoveryCodes[:i:i], tf.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// matchTOTP looks for code around the current time step, accepting only steps
+// after lastStep, and returns the matching step
+func (s *TwoFactorService) matchTOTP(secret, code string, lastStep int64) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits || secret == "" {
+		return 0, false
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return 0, false
+	}
+	current := s.Now().Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if hmac.Equal([]byte(totpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func (s *TwoFactorService) otpauthURI(address, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", s.Issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(s.Issuer + ":" + address)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// totpCode computes the RFC 4226 HOTP value for the time step
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulus := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%modulus)
+}
+
+// hashRecoveryCode normalizes the code like parseRecoveryKey before hashing, so
+// separators and case do not matter
+func hashRecoveryCode(code string) string {
+	cleaned := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(cleaned))
+	return hex.EncodeToString(sum[:])
+}
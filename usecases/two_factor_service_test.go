@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnrollNeedsAMasterPassword(t *testing.T) {
+	ws, repo := newTestWalletService(t)
+	details, err := ws.CreateWallet("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := details.Wallet
+	vault := NewVaultService(repo)
+	twoFactor := NewTwoFactorService(repo, vault)
+
+	// master_password.disabled: no vault has ever been set up
+	if _, err := twoFactor.Enroll(wallet); !errors.Is(err, ErrTwoFactorNoVault) {
+		t.Fatalf("without a master password: got %v, want %v", err, ErrTwoFactorNoVault)
+	}
+	if _, err := NewTwoFactorService(repo, nil).Enroll(wallet); !errors.Is(err, ErrTwoFactorNoVault) {
+		t.Fatalf("without a vault service: got %v, want %v", err, ErrTwoFactorNoVault)
+	}
+
+	if _, err := vault.Setup("master password"); err != nil {
+		t.Fatal(err)
+	}
+	vault.Lock()
+	if _, err := twoFactor.Enroll(wallet); !errors.Is(err, ErrTwoFactorNeedsVault) {
+		t.Fatalf("with a locked master password: got %v, want %v", err, ErrTwoFactorNeedsVault)
+	}
+
+	if err := vault.Unlock("master password"); err != nil {
+		t.Fatal(err)
+	}
+	enrollment, err := twoFactor.Enroll(wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enrollment.Secret == "" || len(enrollment.RecoveryCodes) != recoveryCodeCount {
+		t.Errorf("incomplete enrollment: %+v", enrollment)
+	}
+}
@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FormatUnits renders an integer amount in base units as a decimal string with
+// the given number of decimals, trimming trailing zeros.
+func FormatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+	negative := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		if len(digits) <= decimals {
+			digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+		}
+		intPart := digits[:len(digits)-decimals]
+		fracPart := strings.TrimRight(digits[len(digits)-decimals:], "0")
+		digits = intPart
+		if fracPart != "" {
+			digits += "." + fracPart
+		}
+	}
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+// FormatUnitsString is FormatUnits for amounts already encoded as decimal strings
+func FormatUnitsString(amount string, decimals int) string {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return amount
+	}
+	return FormatUnits(value, decimals)
+}
+
+// ParseUnits converts a decimal string such as "1.5" into base units with the
+// given number of decimals. Amounts with more fractional digits are rejected.
+func ParseUnits(amount string, decimals int) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return nil, fmt.Errorf("empty amount")
+	}
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+	intPart, fracPart, _ := strings.Cut(amount, ".")
+	if len(fracPart) > decimals {
+		return nil, fmt.Errorf("%s has more than %d decimal places", amount, decimals)
+	}
+	digits := intPart + fracPart + strings.Repeat("0", decimals-len(fracPart))
+	if strings.Trim(digits, "0123456789") != "" || intPart+fracPart == "" {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	if negative {
+		value.Neg(value)
+	}
+	return value, nil
+}
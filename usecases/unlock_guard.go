@@ -0,0 +1,218 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+var (
+	ErrUnlockBlocked    = errors.New("temporarily blocked after failed attempts")
+	ErrNoMasterPassword = errors.New("no master password is set")
+	ErrNotLockedOut     = errors.New("the wallet is not locked out")
+)
+
+// UnlockStatus describes the failed password attempts of a wallet
+type UnlockStatus struct {
+	Failures  int
+	Remaining int       // Attempts left before the lockout; -1 when lockouts are disabled
+	RetryAt   time.Time // Zero when a password can be tried now
+	LockedOut bool
+}
+
+// Wait is the time left before the next attempt
+func (s UnlockStatus) Wait() time.Duration {
+	if s.RetryAt.IsZero() {
+		return 0
+	}
+	wait := time.Until(s.RetryAt)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// UnlockError carries the attempt status with an incorrect or refused secret
+type UnlockError struct {
+	Status UnlockStatus
+	Err    error // domain.ErrIncorrectPassword, ErrInvalidTwoFactorCode, ErrWrongMasterPassword or ErrUnlockBlocked
+}
+
+func (e *UnlockError) Error() string {
+	if e.Status.LockedOut {
+		return fmt.Sprintf("%v: locked out until %s", e.Err, e.Status.RetryAt.Format("15:04:05"))
+	}
+	if wait := e.Status.Wait(); wait > 0 {
+		return fmt.Sprintf("%v: retry in %s", e.Err, wait.Round(time.Second))
+	}
+	return e.Err.Error()
+}
+
+func (e *UnlockError) Unwrap() error {
+	return e.Err
+}
+
+// UnlockGuard slows down the guessing of a secret: each wrong guess doubles the
+// wait before the next one, and MaxAttempts consecutive failures lock the wallet
+// out until the cooldown ends or the master password is entered. Every failure
+// is written to the audit log.
+type UnlockGuard struct {
+	Repo        domain.UnlockRepository
+	Secret      string        // Guessed secret, one of the domain.Unlock* kinds
+	Vault       *VaultService // Lifts lockouts; nil leaves only the cooldown
+	MaxAttempts int           // Zero or less disables the lockout
+	BaseDelay   time.Duration // Wait after the first failure
+	MaxDelay    time.Duration
+	Cooldown    time.Duration
+	Now         func() time.Time
+}
+
+func NewUnlockGuard(repo domain.UnlockRepository, maxAttempts int, baseDelay, maxDelay, cooldown time.Duration) *UnlockGuard {
+	return &UnlockGuard{
+		Repo:        repo,
+		Secret:      domain.UnlockWalletPassword,
+		MaxAttempts: maxAttempts,
+		BaseDelay:   baseDelay,
+		MaxDelay:    maxDelay,
+		Cooldown:    cooldown,
+		Now:         time.Now,
+	}
+}
+
+// For returns a guard with the same limits counting the guesses of another secret
+func (g *UnlockGuard) For(secret string) *UnlockGuard {
+	guard := *g
+	guard.Secret = secret
+	return &guard
+}
+
+// Status returns the attempts of the wallet; a finished lockout starts over
+func (g *UnlockGuard) Status(wallet *domain.Wallet) (UnlockStatus, error) {
+	attempts, err := g.attempts(wallet.ID)
+	if err != nil {
+		return UnlockStatus{}, err
+	}
+	return g.status(attempts), nil
+}
+
+// Check refuses the attempt while the wallet waits after failures; refusals are
+// audited too, since they are attempts all the same
+func (g *UnlockGuard) Check(wallet *domain.Wallet, source string) error {
+	status, err := g.Status(wallet)
+	if err != nil {
+		return err
+	}
+	if status.RetryAt.IsZero() {
+		return nil
+	}
+	g.audit(wallet, domain.AuditUnlockRejected, fmt.Sprintf("%s, retry at %s", source, status.RetryAt.Format(time.RFC3339)))
+	return &UnlockError{Status: status, Err: ErrUnlockBlocked}
+}
+
+// Failure records a wrong password and returns the new status
+func (g *UnlockGuard) Failure(wallet *domain.Wallet, source string) (UnlockStatus, error) {
+	attempts, err := g.attempts(wallet.ID)
+	if err != nil {
+		return UnlockStatus{}, err
+	}
+	now := g.Now()
+	attempts.Failures++
+	attempts.RetryAt = ceilSecond(now.Add(g.delay(attempts.Failures)))
+	event, detail := domain.AuditUnlockFailed, fmt.Sprintf("%s, failure %d", source, attempts.Failures)
+	if g.MaxAttempts > 0 && attempts.Failures >= g.MaxAttempts {
+		attempts.LockedOut = true
+		attempts.RetryAt = ceilSecond(now.Add(g.Cooldown))
+		event, detail = domain.AuditLockout, fmt.Sprintf("%s, failure %d, locked out until %s", source, attempts.Failures, attempts.RetryAt.Format(time.RFC3339))
+	}
+	if err := g.Repo.SaveUnlockAttempts(attempts); err != nil {
+		return UnlockStatus{}, err
+	}
+	g.audit(wallet, event, detail)
+	return g.status(attempts), nil
+}
+
+// Success clears the failures after a correct password
+func (g *UnlockGuard) Success(wallet *domain.Wallet) error {
+	return g.Repo.ResetUnlockAttempts(wallet.ID, g.Secret)
+}
+
+// Release ends a lockout with the master password
+func (g *UnlockGuard) Release(wallet *domain.Wallet, masterPassword string) error {
+	if g.Vault == nil {
+		return ErrNoMasterPassword
+	}
+	status, err := g.Status(wallet)
+	if err != nil {
+		return err
+	}
+	if !status.LockedOut {
+		return ErrNotLockedOut
+	}
+	if err := g.Vault.VerifyPassword(masterPassword); err != nil {
+		g.audit(wallet, domain.AuditReleaseFailed, err.Error())
+		return err
+	}
+	if err := g.Repo.ResetUnlockAttempts(wallet.ID, g.Secret); err != nil {
+		return err
+	}
+	g.audit(wallet, domain.AuditLockoutRelease, fmt.Sprintf("after %d failures", status.Failures))
+	return nil
+}
+
+// attempts loads the record, starting over once a lockout has ended
+func (g *UnlockGuard) attempts(walletID int) (*domain.UnlockAttempts, error) {
+	attempts, err := g.Repo.GetUnlockAttempts(walletID, g.Secret)
+	if err != nil {
+		return nil, err
+	}
+	if attempts.LockedOut && !g.Now().Before(attempts.RetryAt) {
+		return &domain.UnlockAttempts{WalletID: walletID, Secret: g.Secret}, nil
+	}
+	return attempts, nil
+}
+
+func (g *UnlockGuard) status(attempts *domain.UnlockAttempts) UnlockStatus {
+	status := UnlockStatus{Failures: attempts.Failures, Remaining: -1, LockedOut: attempts.LockedOut}
+	if g.MaxAttempts > 0 {
+		status.Remaining = max(g.MaxAttempts-attempts.Failures, 0)
+	}
+	if g.Now().Before(attempts.RetryAt) {
+		status.RetryAt = attempts.RetryAt
+	}
+	return status
+}
+
+// delay doubles BaseDelay for each failure after the first, up to MaxDelay
+func (g *UnlockGuard) delay(failures int) time.Duration {
+	delay := g.BaseDelay
+	for i := 1; i < failures && delay < g.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, g.MaxDelay)
+}
+
+// ceilSecond rounds t up to the whole second kept by the repository, so the
+// returned status matches the stored one
+func ceilSecond(t time.Time) time.Time {
+	rounded := t.Truncate(time.Second)
+	if rounded.Before(t) {
+		rounded = rounded.Add(time.Second)
+	}
+	return rounded
+}
+
+// audit writes the entry; a failing audit log must not hide the unlock result
+func (g *UnlockGuard) audit(wallet *domain.Wallet, event, detail string) {
+	entry := &domain.AuditEntry{
+		Time:     g.Now(),
+		WalletID: wallet.ID,
+		Address:  wallet.Address,
+		Event:    event,
+		Detail:   g.Secret + ": " + detail,
+	}
+	if err := g.Repo.AddAuditEntry(entry); err != nil {
+		log.Printf("Error writing the audit log: %v\n", err)
+	}
+}
@@ -0,0 +1,387 @@
+package usecases
+
+import (
+	"blocowallet/domain"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id parameters for new master passwords; stored in the vault record, so
+// changing them only affects passwords set afterwards
+const (
+	vaultKDF         = "argon2id"
+	vaultArgonTime   = 3
+	vaultArgonMemory = 64 * 1024
+	vaultArgonThread = 4
+	vaultSaltSize    = 16
+	vaultKeySize     = 32
+	recoveryKeySize  = 20 // 160 bits, 32 base32 characters
+)
+
+var (
+	ErrWrongMasterPassword = errors.New("incorrect master password")
+	ErrInvalidRecoveryKey  = errors.New("invalid recovery key")
+	ErrVaultInitialized    = errors.New("a master password is already set")
+)
+
+// Additional data binding each sealed copy of the data key to its purpose
+var (
+	passwordKeyAD = []byte("bloco-vault-password")
+	recoveryKeyAD = []byte("bloco-vault-recovery")
+)
+
+// vaultWallet stands for the application in the attempts of the master password
+var vaultWallet = &domain.Wallet{}
+
+// VaultService guards the application behind a master password. The key derived
+// from it unlocks the data key that encrypts the metadata in the repository.
+type VaultService struct {
+	Repo  domain.VaultRepository
+	Guard *UnlockGuard // Throttles wrong master passwords; nil accepts unlimited attempts
+
+	mu       sync.Mutex
+	unlocked bool
+}
+
+func NewVaultService(repo domain.VaultRepository) *VaultService {
+	return &VaultService{Repo: repo}
+}
+
+// Initialized reports whether a master password has been set
+func (vs *VaultService) Initialized() (bool, error) {
+	record, err := vs.Repo.GetVault()
+	if err != nil {
+		return false, err
+	}
+	return record != nil, nil
+}
+
+func (vs *VaultService) Locked() bool {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	return !vs.unlocked
+}
+
+// Lock drops the data key; metadata reads as empty until the next unlock
+func (vs *VaultService) Lock() {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	vs.Repo.SetFieldCipher(nil)
+	vs.unlocked = false
+}
+
+// Setup sets the first master password, encrypts the existing metadata and
+// returns the recovery key, which is shown to the user only once
+func (vs *VaultService) Setup(password string) (string, error) {
+	existing, err := vs.Repo.GetVault()
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		return "", ErrVaultInitialized
+	}
+
+	dataKey, err := randomBytes(vaultKeySize)
+	if err != nil {
+		return "", err
+	}
+	record := &domain.VaultRecord{}
+	if err := sealWithPassword(record, password, dataKey); err != nil {
+		return "", err
+	}
+	recoveryKey, err := sealWithRecoveryKey(record, dataKey)
+	if err != nil {
+		return "", err
+	}
+	if err := vs.Repo.SaveVault(record); err != nil {
+		return "", err
+	}
+	if err := vs.open(dataKey); err != nil {
+		return "", err
+	}
+	return recoveryKey, nil
+}
+
+// Unlock opens the vault with the master password
+func (vs *VaultService) Unlock(password string) error {
+	_, dataKey, err := vs.openWithPassword(password, "unlock")
+	if err != nil {
+		return err
+	}
+	return vs.open(dataKey)
+}
+
+// VerifyPassword checks the master password without changing the vault state
+func (vs *VaultService) VerifyPassword(password string) error {
+	_, _, err := vs.openWithPassword(password, "verify")
+	return err
+}
+
+// Recover opens the vault with the recovery key and replaces the forgotten
+// master password. The recovery key stays valid.
+func (vs *VaultService) Recover(recoveryKey, newPassword string) error {
+	record, err := vs.record()
+	if err != nil {
+		return err
+	}
+	raw, err := parseRecoveryKey(recoveryKey)
+	if err != nil {
+		return err
+	}
+	dataKey, err := openSealed(recoveryAEADKey(raw), record.RecoveryKey, recoveryKeyAD)
+	if err != nil {
+		return ErrInvalidRecoveryKey
+	}
+	if err := sealWithPassword(record, newPassword, dataKey); err != nil {
+		return err
+	}
+	if err := vs.Repo.SaveVault(record); err != nil {
+		return err
+	}
+	// The new password starts without failures, even during a lockout
+	if vs.Guard != nil {
+		if err := vs.Guard.Success(vaultWallet); err != nil {
+			return err
+		}
+	}
+	return vs.open(dataKey)
+}
+
+// ChangePassword replaces the master password; the metadata keeps its data key,
+// so nothing else is re-encrypted
+func (vs *VaultService) ChangePassword(current, newPassword string) error {
+	record, dataKey, err := vs.openWithPassword(current, "change")
+	if err != nil {
+		return err
+	}
+	if err := sealWithPassword(record, newPassword, dataKey); err != nil {
+		return err
+	}
+	return vs.Repo.SaveVault(record)
+}
+
+// NewRecoveryKey replaces the recovery key; the previous one stops working
+func (vs *VaultService) NewRecoveryKey(password string) (string, error) {
+	record, dataKey, err := vs.openWithPassword(password, "recovery_key")
+	if err != nil {
+		return "", err
+	}
+	recoveryKey, err := sealWithRecoveryKey(record, dataKey)
+	if err != nil {
+		return "", err
+	}
+	if err := vs.Repo.SaveVault(record); err != nil {
+		return "", err
+	}
+	return recoveryKey, nil
+}
+
+func (vs *VaultService) record() (*domain.VaultRecord, error) {
+	record, err := vs.Repo.GetVault()
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("no master password is set")
+	}
+	return record, nil
+}
+
+// openWithPassword derives the data key from the master password; through the
+// guard, wrong passwords back off, lock out and are audited like wallet ones
+func (vs *VaultService) openWithPassword(password, source string) (*domain.VaultRecord, []byte, error) {
+	record, err := vs.record()
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.KDF != vaultKDF {
+		return nil, nil, fmt.Errorf("unsupported key derivation %q", record.KDF)
+	}
+	if vs.Guard != nil {
+		if err := vs.Guard.Check(vaultWallet, source); err != nil {
+			return nil, nil, err
+		}
+	}
+	key := argon2.IDKey([]byte(password), record.Salt, record.Time, record.Memory, record.Threads, vaultKeySize)
+	dataKey, err := openSealed(key, record.PasswordKey, passwordKeyAD)
+	if err != nil {
+		if vs.Guard == nil {
+			return nil, nil, ErrWrongMasterPassword
+		}
+		status, err := vs.Guard.Failure(vaultWallet, source)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, &UnlockError{Status: status, Err: ErrWrongMasterPassword}
+	}
+	if vs.Guard != nil {
+		if err := vs.Guard.Success(vaultWallet); err != nil {
+			return nil, nil, err
+		}
+	}
+	return record, dataKey, nil
+}
+
+// open installs the metadata cipher and seals anything still in plaintext
+func (vs *VaultService) open(dataKey []byte) error {
+	fieldCipher, err := newFieldCipher(dataKey)
+	if err != nil {
+		return err
+	}
+	vs.mu.Lock()
+	vs.Repo.SetFieldCipher(fieldCipher)
+	vs.unlocked = true
+	vs.mu.Unlock()
+	if err := vs.Repo.EncryptMetadata(); err != nil {
+		return fmt.Errorf("error encrypting the metadata: %v", err)
+	}
+	return nil
+}
+
+// sealWithPassword derives a key from password with a fresh salt and seals the
+// data key with it
+func sealWithPassword(record *domain.VaultRecord, password string, dataKey []byte) error {
+	salt, err := randomBytes(vaultSaltSize)
+	if err != nil {
+		return err
+	}
+	key := argon2.IDKey([]byte(password), salt, vaultArgonTime, vaultArgonMemory, vaultArgonThread, vaultKeySize)
+	sealed, err := seal(key, dataKey, passwordKeyAD)
+	if err != nil {
+		return err
+	}
+	record.KDF = vaultKDF
+	record.Salt = salt
+	record.Time = vaultArgonTime
+	record.Memory = vaultArgonMemory
+	record.Threads = vaultArgonThread
+	record.PasswordKey = sealed
+	return nil
+}
+
+// sealWithRecoveryKey generates a recovery key, seals the data key with it and
+// returns the key formatted for printing
+func sealWithRecoveryKey(record *domain.VaultRecord, dataKey []byte) (string, error) {
+	raw, err := randomBytes(recoveryKeySize)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(recoveryAEADKey(raw), dataKey, recoveryKeyAD)
+	if err != nil {
+		return "", err
+	}
+	record.RecoveryKey = sealed
+	return formatRecoveryKey(raw), nil
+}
+
+// formatRecoveryKey renders the key as groups of four base32 characters
+func formatRecoveryKey(raw []byte) string {
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+	var groups []string
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:min(i+4, len(encoded))])
+	}
+	return strings.Join(groups, "-")
+}
+
+// parseRecoveryKey accepts the printed key with or without separators, in any case
+func parseRecoveryKey(recoveryKey string) ([]byte, error) {
+	cleaned := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(recoveryKey)))
+	raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(cleaned)
+	if err != nil || len(raw) != recoveryKeySize {
+		return nil, ErrInvalidRecoveryKey
+	}
+	return raw, nil
+}
+
+// recoveryAEADKey stretches the random recovery key to an AES-256 key; it has
+// full entropy, so no password hashing is needed
+func recoveryAEADKey(raw []byte) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, recoveryKeyAD...), raw...))
+	return sum[:]
+}
+
+func randomBytes(size int) ([]byte, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts with AES-256-GCM and prepends the random nonce
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func openSealed(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed value is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// fieldCipher encrypts metadata values with the vault data key
+type fieldCipher struct {
+	key []byte
+}
+
+var _ domain.FieldCipher = &fieldCipher{}
+
+func newFieldCipher(dataKey []byte) (*fieldCipher, error) {
+	if len(dataKey) != vaultKeySize {
+		return nil, fmt.Errorf("invalid data key size %d", len(dataKey))
+	}
+	return &fieldCipher{key: dataKey}, nil
+}
+
+func (c *fieldCipher) Encrypt(plaintext string) (string, error) {
+	sealed, err := seal(c.key, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *fieldCipher) Decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %v", err)
+	}
+	plaintext, err := openSealed(c.key, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt metadata: %v", err)
+	}
+	return string(plaintext), nil
+}
@@ -2,84 +2,105 @@ package usecases
 
 import (
 	"blocowallet/domain"
+	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/tyler-smith/go-bip32"
 	"github.com/tyler-smith/go-bip39"
-	"os"
-	"path/filepath"
+	"time"
 )
 
+// keyTimeout bounds a single call to a key backend
+const keyTimeout = 30 * time.Second
+
 type WalletDetails struct {
 	Wallet     *domain.Wallet
 	Mnemonic   string
-	PrivateKey *ecdsa.PrivateKey
+	PrivateKey *ecdsa.PrivateKey // Nil when the backend never releases the key
 	PublicKey  *ecdsa.PublicKey
+
+	backend  domain.KeyBackend
+	password string
+}
+
+// SignDigest signs a 32-byte digest with the unlocked key, locally when the key was
+// exported and through the wallet's backend otherwise
+func (d *WalletDetails) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	if d.PrivateKey != nil {
+		return crypto.Sign(digest, d.PrivateKey)
+	}
+	if d.backend == nil {
+		return nil, fmt.Errorf("wallet %s is not unlocked", d.Wallet.Address)
+	}
+	return d.backend.SignDigest(ctx, d.Wallet.KeyRef, d.password, digest)
 }
 
 type WalletService struct {
-	Repo     domain.WalletRepository
-	KeyStore *keystore.KeyStore
+	Repo      domain.WalletRepository
+	Backends  map[string]domain.KeyBackend
+	Default   string             // Backend that holds the keys of new wallets
+	Secrets   domain.SecretStore // Desktop keyring for wallet passwords; nil disables it
+	Passwords *PasswordChain     // Non-interactive password sources; nil disables them
+	Session   *Session           // In-memory cache of unlocked wallets; nil disables it
+	Guard     *UnlockGuard       // Throttling of wrong passwords; nil disables it
 }
 
-func NewWalletService(repo domain.WalletRepository, ks *keystore.KeyStore) *WalletService {
-	return &WalletService{
+// NewWalletService registers the key backends; the first one holds new wallets
+func NewWalletService(repo domain.WalletRepository, backends ...domain.KeyBackend) *WalletService {
+	ws := &WalletService{
 		Repo:     repo,
-		KeyStore: ks,
+		Backends: map[string]domain.KeyBackend{},
+	}
+	for _, backend := range backends {
+		ws.Backends[backend.Name()] = backend
+	}
+	if len(backends) > 0 {
+		ws.Default = backends[0].Name()
 	}
+	return ws
 }
 
-func (ws *WalletService) CreateWallet(password string) (*WalletDetails, error) {
-	mnemonic, err := GenerateMnemonic()
-	if err != nil {
-		return nil, err
+// backend returns the backend holding the key of wallet. Wallets created before
+// backends were recorded live in the keystore.
+func (ws *WalletService) backend(wallet *domain.Wallet) (domain.KeyBackend, error) {
+	name := wallet.Backend
+	if name == "" {
+		name = domain.DefaultKeyBackend
 	}
-
-	privateKeyHex, err := DerivePrivateKey(mnemonic)
-	if err != nil {
-		return nil, err
+	backend, ok := ws.Backends[name]
+	if !ok {
+		return nil, fmt.Errorf("key backend %q is not configured", name)
 	}
+	return backend, nil
+}
 
-	privKey, err := HexToECDSA(privateKeyHex)
-	if err != nil {
-		return nil, err
+// CreateWallet derives a new key from a fresh mnemonic in local backends. Remote
+// backends generate the key themselves, so those wallets have no mnemonic.
+func (ws *WalletService) CreateWallet(password string) (*WalletDetails, error) {
+	if !ws.DefaultExportable() {
+		return ws.createInBackend(password)
 	}
 
-	account, err := ws.KeyStore.ImportECDSA(privKey, password)
+	mnemonic, err := GenerateMnemonic()
 	if err != nil {
 		return nil, err
 	}
 
-	originalPath := account.URL.Path
-	newFilename := fmt.Sprintf("%s.json", account.Address.Hex())
-	newPath := filepath.Join(filepath.Dir(originalPath), newFilename)
-	err = os.Rename(originalPath, newPath)
+	privateKeyHex, err := DerivePrivateKey(mnemonic)
 	if err != nil {
-		return nil, fmt.Errorf("error renaming the wallet file: %v", err)
-	}
-
-	wallet := &domain.Wallet{
-		Address:      account.Address.Hex(),
-		KeyStorePath: newPath,
-		Mnemonic:     mnemonic, // Store the mnemonic
+		return nil, err
 	}
 
-	err = ws.Repo.AddWallet(wallet)
+	privKey, err := HexToECDSA(privateKeyHex)
 	if err != nil {
 		return nil, err
 	}
 
-	walletDetails := &WalletDetails{
-		Wallet:     wallet,
-		Mnemonic:   mnemonic,
-		PrivateKey: privKey,
-		PublicKey:  &privKey.PublicKey,
-	}
-
-	return walletDetails, nil
+	return ws.storeKey(privKey, mnemonic, password)
 }
 
 func (ws *WalletService) ImportWallet(mnemonic, password string) (*WalletDetails, error) {
@@ -97,38 +118,7 @@ func (ws *WalletService) ImportWallet(mnemonic, password string) (*WalletDetails
 		return nil, err
 	}
 
-	account, err := ws.KeyStore.ImportECDSA(privKey, password)
-	if err != nil {
-		return nil, err
-	}
-
-	originalPath := account.URL.Path
-	newFilename := fmt.Sprintf("%s.json", account.Address.Hex())
-	newPath := filepath.Join(filepath.Dir(originalPath), newFilename)
-	err = os.Rename(originalPath, newPath)
-	if err != nil {
-		return nil, fmt.Errorf("error renaming the wallet file: %v", err)
-	}
-
-	wallet := &domain.Wallet{
-		Address:      account.Address.Hex(),
-		KeyStorePath: newPath,
-		Mnemonic:     mnemonic, // Store the mnemonic
-	}
-
-	err = ws.Repo.AddWallet(wallet)
-	if err != nil {
-		return nil, err
-	}
-
-	walletDetails := &WalletDetails{
-		Wallet:     wallet,
-		Mnemonic:   mnemonic,
-		PrivateKey: privKey,
-		PublicKey:  &privKey.PublicKey,
-	}
-
-	return walletDetails, nil
+	return ws.storeKey(privKey, mnemonic, password)
 }
 
 func (ws *WalletService) ImportWalletFromPrivateKey(privateKeyHex, password string) (*WalletDetails, error) {
@@ -156,73 +146,272 @@ func (ws *WalletService) ImportWalletFromPrivateKey(privateKeyHex, password stri
 		return nil, fmt.Errorf("error generating mnemonic: %v", err)
 	}
 
-	// Import the private key to keystore
-	account, err := ws.KeyStore.ImportECDSA(privKey, password)
+	return ws.storeKey(privKey, mnemonic, password)
+}
+
+// DefaultExportable reports whether the default backend holds keys locally,
+// which is when new wallets get a mnemonic phrase
+func (ws *WalletService) DefaultExportable() bool {
+	_, ok := ws.Backends[ws.Default].(domain.KeyExporter)
+	return ok
+}
+
+func (ws *WalletService) createInBackend(password string) (*WalletDetails, error) {
+	backend, ok := ws.Backends[ws.Default]
+	if !ok {
+		return nil, fmt.Errorf("no key backend configured")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyTimeout)
+	defer cancel()
+	ref, err := backend.CreateKey(ctx, password)
 	if err != nil {
 		return nil, err
 	}
-
-	// Rename the keystore file to match Ethereum address
-	originalPath := account.URL.Path
-	newFilename := fmt.Sprintf("%s.json", account.Address.Hex())
-	newPath := filepath.Join(filepath.Dir(originalPath), newFilename)
-	err = os.Rename(originalPath, newPath)
+	pub, err := backend.PublicKey(ctx, ref, password)
 	if err != nil {
-		return nil, fmt.Errorf("error renaming the wallet file: %v", err)
+		return nil, discardKey(ctx, backend, ref, err)
 	}
 
-	// Create the wallet entry with the generated mnemonic
 	wallet := &domain.Wallet{
-		Address:      account.Address.Hex(),
-		KeyStorePath: newPath,
-		Mnemonic:     mnemonic, // Store the generated mnemonic
+		Address: crypto.PubkeyToAddress(*pub).Hex(),
+		Backend: backend.Name(),
+		KeyRef:  ref,
+	}
+	if err := ws.Repo.AddWallet(wallet); err != nil {
+		return nil, discardKey(ctx, backend, ref, err)
+	}
+	return &WalletDetails{
+		Wallet:    wallet,
+		PublicKey: pub,
+		backend:   backend,
+		password:  password,
+	}, nil
+}
+
+// storeKey imports privKey into the default backend and registers the wallet
+func (ws *WalletService) storeKey(privKey *ecdsa.PrivateKey, mnemonic, password string) (*WalletDetails, error) {
+	backend, ok := ws.Backends[ws.Default]
+	if !ok {
+		return nil, fmt.Errorf("no key backend configured")
+	}
+	public := privKey.PublicKey
+	ctx, cancel := context.WithTimeout(context.Background(), keyTimeout)
+	defer cancel()
+	ref, err := backend.ImportKey(ctx, privKey, password)
+	if err != nil {
+		return nil, err
+	}
+	// Remote backends keep the key, so neither the key nor the mnemonic that
+	// derives it is handed back or stored
+	if _, ok := backend.(domain.KeyExporter); !ok {
+		privKey = nil
+		mnemonic = ""
 	}
 
-	// Add wallet to repository
+	wallet := &domain.Wallet{
+		Address:  crypto.PubkeyToAddress(public).Hex(),
+		Backend:  backend.Name(),
+		KeyRef:   ref,
+		Mnemonic: mnemonic,
+	}
 	err = ws.Repo.AddWallet(wallet)
 	if err != nil {
-		return nil, err
+		return nil, discardKey(ctx, backend, ref, err)
 	}
 
-	// Return wallet details with the generated mnemonic
 	walletDetails := &WalletDetails{
 		Wallet:     wallet,
 		Mnemonic:   mnemonic,
 		PrivateKey: privKey,
-		PublicKey:  &privKey.PublicKey,
+		PublicKey:  &public,
+		backend:    backend,
+		password:   password,
 	}
-
 	return walletDetails, nil
 }
 
+// discardKey deletes a key whose wallet could not be registered, so it is not
+// left behind in the backend. The key never belonged to a wallet, so backends
+// set to keep the keys of deleted wallets remove it all the same. A failed
+// cleanup is added to err, naming the key so it can be removed by hand.
+func discardKey(ctx context.Context, backend domain.KeyBackend, ref string, err error) error {
+	var discardErr error
+	if discarder, ok := backend.(domain.KeyDiscarder); ok {
+		discardErr = discarder.DiscardKey(ctx, ref)
+	} else {
+		discardErr = backend.DeleteKey(ctx, ref)
+	}
+	if discardErr != nil {
+		return fmt.Errorf("%v (the unregistered key %s could not be removed from %s: %v)", err, ref, backend.Name(), discardErr)
+	}
+	return err
+}
+
+// LoadWallet unlocks the wallet in its backend. The private key is only loaded
+// when the backend can export it; otherwise signing goes through the backend.
 func (ws *WalletService) LoadWallet(wallet *domain.Wallet, password string) (*WalletDetails, error) {
-	keyJSON, err := os.ReadFile(wallet.KeyStorePath)
-	if err != nil {
-		return nil, fmt.Errorf("error reading the wallet file: %v", err)
-	}
-	key, err := keystore.DecryptKey(keyJSON, password)
+	backend, err := ws.backend(wallet)
 	if err != nil {
-		return nil, fmt.Errorf("incorrect password")
+		return nil, err
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyTimeout)
+	defer cancel()
 
 	walletDetails := &WalletDetails{
-		Wallet:     wallet,
-		Mnemonic:   wallet.Mnemonic,
-		PrivateKey: key.PrivateKey,
-		PublicKey:  &key.PrivateKey.PublicKey,
+		Wallet:   wallet,
+		Mnemonic: wallet.Mnemonic,
+		backend:  backend,
+		password: password,
+	}
+	if exporter, ok := backend.(domain.KeyExporter); ok {
+		key, err := exporter.ExportKey(ctx, wallet.KeyRef, password)
+		if err != nil {
+			return nil, err
+		}
+		walletDetails.PrivateKey = key
+		walletDetails.PublicKey = &key.PublicKey
+	} else {
+		walletDetails.PublicKey, err = backend.PublicKey(ctx, wallet.KeyRef, password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if crypto.PubkeyToAddress(*walletDetails.PublicKey) != common.HexToAddress(wallet.Address) {
+		return nil, fmt.Errorf("the key in %s does not match %s", backend.Name(), wallet.Address)
+	}
+	if ws.Session != nil {
+		ws.Session.Put(walletDetails)
 	}
 	return walletDetails, nil
 }
 
+// UnlockWithPassword is LoadWallet for a password typed by the user: wallets
+// waiting after failed attempts are refused, and a wrong password is counted and
+// returned as an *UnlockError with the new status
+func (ws *WalletService) UnlockWithPassword(wallet *domain.Wallet, password, source string) (*WalletDetails, error) {
+	if ws.Guard == nil {
+		return ws.LoadWallet(wallet, password)
+	}
+	if err := ws.Guard.Check(wallet, source); err != nil {
+		return nil, err
+	}
+	details, err := ws.LoadWallet(wallet, password)
+	if errors.Is(err, domain.ErrIncorrectPassword) {
+		status, guardErr := ws.Guard.Failure(wallet, source)
+		if guardErr != nil {
+			return nil, guardErr
+		}
+		return nil, &UnlockError{Status: status, Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ws.Guard.Success(wallet); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// checkLockout refuses unlocks with stored passwords while the wallet waits
+// after wrong passwords; their own failures are not counted, since they are
+// not guesses
+func (ws *WalletService) checkLockout(wallet *domain.Wallet, source string) error {
+	if ws.Guard == nil {
+		return nil
+	}
+	return ws.Guard.Check(wallet, source)
+}
+
+// CachedWallet returns the unlock of wallet kept by the session, if still valid
+func (ws *WalletService) CachedWallet(wallet *domain.Wallet) (*WalletDetails, bool) {
+	if ws.Session == nil {
+		return nil, false
+	}
+	cached, ok := ws.Session.Get(wallet.Address)
+	if !ok {
+		return nil, false
+	}
+	details := *cached
+	details.Wallet = wallet
+	details.Mnemonic = wallet.Mnemonic
+	return &details, true
+}
+
+// KeyringAvailable reports whether wallet passwords can be kept in the keyring
+func (ws *WalletService) KeyringAvailable() bool {
+	return ws.Secrets != nil && ws.Secrets.Available()
+}
+
+// UnlockWithKeyring loads a wallet that opted in to the keyring with its stored
+// password. Any error means the caller should prompt for the password instead.
+func (ws *WalletService) UnlockWithKeyring(wallet *domain.Wallet) (*WalletDetails, error) {
+	if ws.Secrets == nil || !wallet.Keyring {
+		return nil, domain.ErrSecretNotFound
+	}
+	if err := ws.checkLockout(wallet, "keyring"); err != nil {
+		return nil, err
+	}
+	password, err := ws.Secrets.GetSecret(wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+	return ws.LoadWallet(wallet, password)
+}
+
+// RememberPassword stores the password of wallet in the keyring and opts the
+// wallet in, so later unlocks skip the prompt
+func (ws *WalletService) RememberPassword(wallet *domain.Wallet, password string) error {
+	if ws.Secrets == nil {
+		return fmt.Errorf("no keyring configured")
+	}
+	if err := ws.Secrets.SetSecret(wallet.Address, password); err != nil {
+		return fmt.Errorf("error saving the password in the keyring: %v", err)
+	}
+	if err := ws.Repo.SetWalletKeyring(wallet.ID, true); err != nil {
+		return err
+	}
+	wallet.Keyring = true
+	return nil
+}
+
+// ForgetPassword removes the stored password and opts the wallet out
+func (ws *WalletService) ForgetPassword(wallet *domain.Wallet) error {
+	if ws.Secrets != nil {
+		if err := ws.Secrets.DeleteSecret(wallet.Address); err != nil {
+			return fmt.Errorf("error removing the password from the keyring: %v", err)
+		}
+	}
+	if err := ws.Repo.SetWalletKeyring(wallet.ID, false); err != nil {
+		return err
+	}
+	wallet.Keyring = false
+	return nil
+}
+
 func (ws *WalletService) GetAllWallets() ([]domain.Wallet, error) {
 	return ws.Repo.GetAllWallets()
 }
 
 func (ws *WalletService) DeleteWallet(wallet *domain.Wallet) error {
-	// Remove o arquivo keystore do sistema
-	err := os.Remove(wallet.KeyStorePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove keystore file: %v", err)
+	backend, err := ws.backend(wallet)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyTimeout)
+	defer cancel()
+	// Remove a chave do backend
+	if err := backend.DeleteKey(ctx, wallet.KeyRef); err != nil {
+		return err
+	}
+	// Remove a senha guardada no chaveiro
+	if wallet.Keyring && ws.Secrets != nil {
+		if err := ws.Secrets.DeleteSecret(wallet.Address); err != nil {
+			return fmt.Errorf("error removing the password from the keyring: %v", err)
+		}
+	}
+	if ws.Session != nil {
+		ws.Session.Forget(wallet.Address)
 	}
 	// Remove do banco de dados
 	return ws.Repo.DeleteWallet(wallet.ID)
@@ -243,6 +432,11 @@ func GenerateMnemonic() (string, error) {
 }
 
 func DerivePrivateKey(mnemonic string) (string, error) {
+	return DerivePrivateKeyAt(mnemonic, 0)
+}
+
+// DerivePrivateKeyAt derives the key of the BIP-44 path m/44'/60'/0'/0/index
+func DerivePrivateKeyAt(mnemonic string, index uint32) (string, error) {
 	if !bip39.IsMnemonicValid(mnemonic) {
 		return "", fmt.Errorf("invalid mnemonic phrase")
 	}
@@ -267,7 +461,7 @@ func DerivePrivateKey(mnemonic string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	addressKey, err := changeKey.NewChildKey(0)
+	addressKey, err := changeKey.NewChildKey(index)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keepingBackend stands for a remote backend set to keep the keys of deleted
+// wallets: DeleteKey does nothing and only DiscardKey removes a key. Every key
+// it creates is the same, so a second wallet fails to register.
+type keepingBackend struct {
+	key        *ecdsa.PrivateKey
+	keys       map[string]bool
+	created    int
+	discardErr error
+}
+
+func (b *keepingBackend) Name() string { return "remote" }
+
+func (b *keepingBackend) CreateKey(ctx context.Context, password string) (string, error) {
+	b.created++
+	ref := fmt.Sprintf("key-%d", b.created)
+	b.keys[ref] = true
+	return ref, nil
+}
+
+func (b *keepingBackend) ImportKey(ctx context.Context, key *ecdsa.PrivateKey, password string) (string, error) {
+	return b.CreateKey(ctx, password)
+}
+
+func (b *keepingBackend) SignDigest(ctx context.Context, ref, password string, digest []byte) ([]byte, error) {
+	return crypto.Sign(digest, b.key)
+}
+
+func (b *keepingBackend) PublicKey(ctx context.Context, ref, password string) (*ecdsa.PublicKey, error) {
+	return &b.key.PublicKey, nil
+}
+
+func (b *keepingBackend) DeleteKey(ctx context.Context, ref string) error { return nil }
+
+func (b *keepingBackend) DiscardKey(ctx context.Context, ref string) error {
+	if b.discardErr != nil {
+		return b.discardErr
+	}
+	delete(b.keys, ref)
+	return nil
+}
+
+func TestFailedRegistrationDiscardsTheKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, _ := newTestWalletService(t)
+	backend := &keepingBackend{key: key, keys: map[string]bool{}}
+	ws.Backends[backend.Name()] = backend
+	ws.Default = backend.Name()
+
+	if _, err := ws.CreateWallet("password"); err != nil {
+		t.Fatal(err)
+	}
+	// The same address is already registered, so the new key must go
+	_, err = ws.CreateWallet("password")
+	if err == nil {
+		t.Fatal("a duplicate wallet was registered")
+	}
+	if backend.keys["key-2"] {
+		t.Error("the key of the failed registration was kept although DeleteKey keeps keys")
+	}
+	if !backend.keys["key-1"] {
+		t.Error("the registered wallet's key was removed")
+	}
+
+	// A failed cleanup is reported along with the registration error
+	backend.discardErr = errors.New("backend unavailable")
+	_, err = ws.CreateWallet("password")
+	if err == nil || !strings.Contains(err.Error(), "key-3") || !strings.Contains(err.Error(), "backend unavailable") {
+		t.Errorf("got %v, want the registration error naming the leftover key", err)
+	}
+}